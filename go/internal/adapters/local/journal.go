@@ -0,0 +1,101 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// DefaultJournalDir is where run journals live relative to the project directory.
+//
+// It sits next to account-ids.json, which the bash scripts already keep
+// in .aws-bootstrap/.
+const DefaultJournalDir = ".aws-bootstrap/runs"
+
+// JournalStore persists run journals as JSON files on the local filesystem.
+//
+// Each run is stored in its own file: <Dir>/<runID>.json, so run IDs must
+// not contain path separators or "..". Writes go to a
+// temporary file first and are renamed into place, so a crash mid-write
+// never leaves a truncated journal behind.
+type JournalStore struct {
+	Dir string
+}
+
+// NewJournalStore creates a journal store rooted at dir.
+func NewJournalStore(dir string) *JournalStore {
+	return &JournalStore{Dir: dir}
+}
+
+// LoadJournal reads the journal for runID, or returns nil if it doesn't exist.
+func (s *JournalStore) LoadJournal(ctx context.Context, runID string) (*ports.RunJournal, error) {
+	path, err := s.path(runID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var journal ports.RunJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", path, err)
+	}
+	return &journal, nil
+}
+
+// SaveJournal writes the journal atomically.
+func (s *JournalStore) SaveJournal(ctx context.Context, journal *ports.RunJournal) error {
+	path, err := s.path(journal.RunID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// path returns the file for runID, refusing IDs that would escape Dir.
+func (s *JournalStore) path(runID string) (string, error) {
+	if runID == "" || strings.ContainsAny(runID, `/\`) || strings.Contains(runID, "..") {
+		return "", fmt.Errorf("invalid run ID %q: must not be empty or contain path separators or \"..\"", runID)
+	}
+	return filepath.Join(s.Dir, runID+".json"), nil
+}
+
+// writeFileAtomic writes data to a temp file in the same directory and
+// renames it over path.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
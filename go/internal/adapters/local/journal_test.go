@@ -0,0 +1,62 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func TestJournalStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := NewJournalStore(t.TempDir())
+
+	missing, err := store.LoadJournal(ctx, "TPA")
+	if err != nil {
+		t.Fatalf("LoadJournal() on missing file failed: %v", err)
+	}
+	if missing != nil {
+		t.Fatalf("Expected nil journal for missing file, got %+v", missing)
+	}
+
+	completed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := &ports.RunJournal{
+		RunID: "TPA",
+		Entries: []ports.JournalEntry{
+			{Environment: "dev", Step: "account-created", AccountID: "100000000001", CompletedAt: completed},
+		},
+		UpdatedAt: completed,
+	}
+	if err := store.SaveJournal(ctx, want); err != nil {
+		t.Fatalf("SaveJournal() failed: %v", err)
+	}
+
+	got, err := store.LoadJournal(ctx, "TPA")
+	if err != nil {
+		t.Fatalf("LoadJournal() failed: %v", err)
+	}
+	if len(got.Entries) != 1 || got.Entries[0] != want.Entries[0] {
+		t.Errorf("LoadJournal() = %+v, want %+v", got, want)
+	}
+}
+
+func TestJournalStoreRejectsUnsafeRunIDs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewJournalStore(filepath.Join(dir, "runs"))
+
+	for _, runID := range []string{"", "../x", "a/b", `a\b`, ".."} {
+		if err := store.SaveJournal(ctx, &ports.RunJournal{RunID: runID}); err == nil {
+			t.Errorf("SaveJournal(%q) should fail", runID)
+		}
+		if _, err := store.LoadJournal(ctx, runID); err == nil {
+			t.Errorf("LoadJournal(%q) should fail", runID)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Unsafe run IDs wrote %v", entries)
+	}
+}
@@ -0,0 +1,65 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// DefaultStatePath is where project state lives relative to the project
+// directory. create-project-accounts.sh writes the same file, and the
+// other bash scripts read account IDs from it.
+const DefaultStatePath = ".aws-bootstrap/account-ids.json"
+
+// StateStore persists project state in a single JSON file.
+//
+// The file holds one project, so the bash scripts and the Go code can
+// share a project directory during migration: bash reads the v1 keys that
+// every version keeps, and a file bash rewrote is upgraded on the next
+// save (see ports.StateVersion). Writes are atomic, like JournalStore's.
+type StateStore struct {
+	Path string
+}
+
+// NewStateStore creates a state store for the project in projectDir.
+func NewStateStore(projectDir string) *StateStore {
+	return &StateStore{Path: filepath.Join(projectDir, DefaultStatePath)}
+}
+
+// LoadState reads the state file, or returns nil if it doesn't exist.
+//
+// A file recorded for a different project is an error rather than "no
+// state", so a setup never mistakes another project's accounts for its own.
+func (s *StateStore) LoadState(ctx context.Context, projectCode string) (*ports.ProjectState, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state ports.ProjectState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", s.Path, err)
+	}
+	if state.ProjectCode != "" && state.ProjectCode != projectCode {
+		return nil, fmt.Errorf("state %s is for project %s, not %s", s.Path, state.ProjectCode, projectCode)
+	}
+	return &state, nil
+}
+
+// SaveState writes the state file atomically in the current schema.
+func (s *StateStore) SaveState(ctx context.Context, state *ports.ProjectState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.Path, append(data, '\n'))
+}
@@ -0,0 +1,172 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// v1State is the file create-project-accounts.sh writes.
+const v1State = `{
+  "projectCode": "TPA",
+  "devAccountId": "100000000001",
+  "stagingAccountId": "100000000002",
+  "prodAccountId": "100000000003",
+  "ouId": "ou-813y-8teevv2l"
+}
+`
+
+func writeState(t *testing.T, store *StateStore, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(store.Path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.Path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStateStoreReadsV1(t *testing.T) {
+	ctx := context.Background()
+	store := NewStateStore(t.TempDir())
+
+	missing, err := store.LoadState(ctx, "TPA")
+	if err != nil || missing != nil {
+		t.Fatalf("LoadState() on missing file = %+v, %v; want nil, nil", missing, err)
+	}
+
+	writeState(t, store, v1State)
+	state, err := store.LoadState(ctx, "TPA")
+	if err != nil {
+		t.Fatalf("LoadState() failed: %v", err)
+	}
+	if state.Version != 1 || state.ProjectCode != "TPA" || state.OUID != "ou-813y-8teevv2l" {
+		t.Errorf("LoadState() = %+v", state)
+	}
+	if len(state.Environments) != 3 || state.Environments["staging"].AccountID != "100000000002" {
+		t.Errorf("Environments = %+v", state.Environments)
+	}
+
+	if _, err := store.LoadState(ctx, "XYZ"); err == nil || !strings.Contains(err.Error(), "is for project TPA") {
+		t.Errorf("LoadState() for another project = %v, want project mismatch error", err)
+	}
+}
+
+func TestStateStoreUpgradeKeepsBashCompatibility(t *testing.T) {
+	ctx := context.Background()
+	store := NewStateStore(t.TempDir())
+	writeState(t, store, strings.Replace(v1State, `"ouId"`, `"githubRepo": "tpa-app",
+  "ouId"`, 1))
+
+	state, _ := store.LoadState(ctx, "TPA")
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	dev := state.Environments["dev"]
+	dev.DeployRoleARN = "arn:aws:iam::100000000001:role/GitHubActionsDeployRole"
+	state.Environments["dev"] = dev
+	state.Environments["qa"] = ports.EnvironmentState{AccountID: "100000000004", UpdatedAt: now}
+	state.UpdatedAt = now
+	if err := store.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState() failed: %v", err)
+	}
+
+	// What `jq -r '.devAccountId'` sees
+	data, _ := os.ReadFile(store.Path)
+	var flat map[string]any
+	if err := json.Unmarshal(data, &flat); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]any{
+		"version":          float64(ports.StateVersion),
+		"projectCode":      "TPA",
+		"devAccountId":     "100000000001",
+		"stagingAccountId": "100000000002",
+		"prodAccountId":    "100000000003",
+		"qaAccountId":      "100000000004",
+		"ouId":             "ou-813y-8teevv2l",
+		"githubRepo":       "tpa-app",
+	} {
+		if flat[key] != want {
+			t.Errorf("%s = %v, want %v", key, flat[key], want)
+		}
+	}
+
+	upgraded, _ := store.LoadState(ctx, "TPA")
+	if upgraded.Version != ports.StateVersion || upgraded.Environments["dev"].DeployRoleARN != dev.DeployRoleARN || !upgraded.UpdatedAt.Equal(now) {
+		t.Errorf("Reloaded state = %+v", upgraded)
+	}
+
+	// Saving an unchanged state rewrites the same bytes
+	if err := store.SaveState(ctx, upgraded); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(store.Path); string(again) != string(data) {
+		t.Errorf("Round trip changed the file:\n%s\nwant:\n%s", again, data)
+	}
+}
+
+func TestStateStoreKeepsEmptyV1AccountIDs(t *testing.T) {
+	ctx := context.Background()
+	store := NewStateStore(t.TempDir())
+	// A failed v1 run records the environments it didn't get to as ""
+	writeState(t, store, strings.Replace(v1State, `"100000000002"`, `""`, 1))
+
+	state, err := store.LoadState(ctx, "TPA")
+	if err != nil {
+		t.Fatalf("LoadState() failed: %v", err)
+	}
+	if err := store.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState() failed: %v", err)
+	}
+
+	// `jq -r '.stagingAccountId'` must still print "", not null
+	data, _ := os.ReadFile(store.Path)
+	var flat map[string]any
+	if err := json.Unmarshal(data, &flat); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := flat["stagingAccountId"]; !ok || id != "" {
+		t.Errorf("stagingAccountId = %v (present: %v), want \"\"", id, ok)
+	}
+}
+
+func TestStateStoreBashRewriteWins(t *testing.T) {
+	ctx := context.Background()
+	store := NewStateStore(t.TempDir())
+	writeState(t, store, `{
+  "version": 2,
+  "projectCode": "TPA",
+  "devAccountId": "100000000009",
+  "prodAccountId": "",
+  "ouId": "ou-813y-8teevv2l",
+  "environments": {
+    "dev": {"accountId": "100000000001", "deployRoleArn": "arn:aws:iam::100000000001:role/GitHubActionsDeployRole"},
+    "prod": {"accountId": "100000000003"},
+    "qa": {"accountId": "100000000004"}
+  }
+}`)
+
+	state, err := store.LoadState(ctx, "TPA")
+	if err != nil {
+		t.Fatalf("LoadState() failed: %v", err)
+	}
+	if dev := state.Environments["dev"]; dev.AccountID != "100000000009" || dev.DeployRoleARN != "" {
+		t.Errorf("dev = %+v, want the rewritten ID without the old account's ARNs", dev)
+	}
+	if prod, ok := state.Environments["prod"]; !ok || prod.AccountID != "" {
+		t.Errorf("prod = %+v, want it kept without an account: its ID was cleared", prod)
+	}
+	if state.Environments["qa"].AccountID != "100000000004" {
+		t.Errorf("qa = %+v, want it kept", state.Environments["qa"])
+	}
+
+	writeState(t, store, `{"version": 3, "projectCode": "TPA"}`)
+	if _, err := store.LoadState(ctx, "TPA"); err == nil {
+		t.Error("LoadState() should reject a newer schema version")
+	}
+}
@@ -3,6 +3,9 @@ package mock
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,16 +16,23 @@ import (
 //
 // This adapter implements the AWSClient interface but doesn't actually
 // make any AWS API calls. Instead, it:
-//   - Records all operations in an internal log
-//   - Simulates AWS account creation with fake IDs
-//   - Returns success for all operations
+//   - Records all operations in a typed log (see Operations)
+//   - Simulates AWS account creation with fake IDs, instantly or through
+//     the asynchronous IN_PROGRESS lifecycle (see SimulateAccountCreation)
+//   - Models the organization tree: new accounts land in the root (RootID),
+//     like real AWS, and must be moved into their OU
+//   - Models account status: CloseAccount suspends accounts, up to a
+//     close-account quota (see SetCloseAccountQuota)
+//   - Remembers created resources (roles, budgets, alarms, ...) so lookups work
+//   - Returns success unless errors are queued with InjectError or faults
+//     (failures, throttling, latency) are added with AddFault
 //   - Provides deterministic, fast behavior
 //
 // Usage:
 //
 //	mockAWS := mock.NewAWSClient()
 //	accountID, err := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{
-//	    Name:  "TPA-dev",
+//	    Name:  "TPA_DEV",
 //	    Email: "user+tpa-dev@gmail.com",
 //	    OrgUnitID: "ou-test-12345678",
 //	})
@@ -37,65 +47,207 @@ type AWSClient struct {
 	mu             sync.Mutex
 	accounts       map[string]*mockAWSAccount
 	accountsByName map[string]string
-	operations     []string
+	operations     []Operation
+	clock          ports.Clock
 	nextAccountID  int64
+	ous            map[string]string // key: OU ID, value: parent ID
+	closeQuota     int               // CloseAccount calls allowed (AWS: per rolling 30 days)
+	closed         int               // Accounts closed so far
+
+	// Asynchronous account creation; nil creates accounts instantly.
+	lifecycle         *AccountCreation
+	creations         map[string]*creation     // key: account ID
+	creationDurations map[string]time.Duration // key: account name
+	creationFailures  map[string]string        // key: account name, value: failure reason
+	accountLimit      int                      // 0 = unlimited
+
+	// Resources inside accounts. Keys are "accountID/name" unless noted.
+	oidcProviders map[string]bool // key: accountID
+	roles         map[string]ports.AWSCreateRoleRequest
+	cdkBootstraps map[string]string // key: accountID/region, value: trusted account
+	budgets       map[string]ports.AWSBudget
+	alarms        map[string]ports.AWSBillingAlarm
+	topics        map[string][]string // key: topic ARN, value: subscribed emails
+
+	// Errors queued by InjectError and faults added by AddFault, keyed by
+	// method name; calls counts calls per method for Call.N.
+	injected map[string][]error
+	faults   map[string][]Fault
+	calls    map[string]int
 }
 
 type mockAWSAccount struct {
 	ID        string
 	Name      string
 	Email     string
-	OrgUnitID string
+	ParentID  string // RootID or an OU ID
+	Status    string // ports.AccountStatus*
+	Tags      map[string]string
 	CreatedAt time.Time
 }
 
 // NewAWSClient creates a new mock AWS client.
 func NewAWSClient() *AWSClient {
 	return &AWSClient{
-		accounts:       make(map[string]*mockAWSAccount),
-		accountsByName: make(map[string]string),
-		operations:     make([]string, 0),
-		nextAccountID:  100000000001, // Start with realistic 12-digit AWS account IDs
+		accounts:          make(map[string]*mockAWSAccount),
+		accountsByName:    make(map[string]string),
+		operations:        make([]Operation, 0),
+		clock:             ports.SystemClock(),
+		nextAccountID:     100000000001, // Start with realistic 12-digit AWS account IDs
+		ous:               make(map[string]string),
+		closeQuota:        DefaultCloseAccountQuota,
+		creations:         make(map[string]*creation),
+		creationDurations: make(map[string]time.Duration),
+		creationFailures:  make(map[string]string),
+		oidcProviders:     make(map[string]bool),
+		roles:             make(map[string]ports.AWSCreateRoleRequest),
+		cdkBootstraps:     make(map[string]string),
+		budgets:           make(map[string]ports.AWSBudget),
+		alarms:            make(map[string]ports.AWSBillingAlarm),
+		topics:            make(map[string][]string),
+		injected:          make(map[string][]error),
+		faults:            make(map[string][]Fault),
+		calls:             make(map[string]int),
 	}
 }
 
+// RootID is the ID of the mock organization's root.
+const RootID = "r-mock"
+
+// DefaultCloseAccountQuota is how many accounts the mock lets CloseAccount
+// close (the AWS minimum for a rolling 30-day window).
+const DefaultCloseAccountQuota = 10
+
+// SetCloseAccountQuota changes how many accounts CloseAccount may close in
+// total. Once reached, CloseAccount fails with CLOSE_ACCOUNT_QUOTA_EXCEEDED.
+// The mock has no rolling window: closures never age out.
+func (m *AWSClient) SetCloseAccountQuota(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closeQuota = n
+}
+
+// CreateOrganizationalUnit adds an OU under parentID (RootID or another OU).
+//
+// OUs don't have to be created up front: any "ou-" ID the mock sees for
+// the first time is treated as an OU directly under the root. Use this
+// helper to build nested OUs.
+func (m *AWSClient) CreateOrganizationalUnit(ouID, parentID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.ensureParentLocked(parentID); err != nil {
+		return err
+	}
+	m.ous[ouID] = parentID
+	m.recordLocked(Operation{Method: "CreateOrganizationalUnit", Request: OURequest{OUID: ouID, ParentID: parentID}},
+		fmt.Sprintf("CreateOrganizationalUnit(%s, parent=%s)", ouID, parentID))
+	return nil
+}
+
+// ensureParentLocked checks that id is the root or an OU, registering
+// unseen OU IDs under the root. MUST only be called when m.mu is held.
+func (m *AWSClient) ensureParentLocked(id string) error {
+	if id == RootID {
+		return nil
+	}
+	if _, exists := m.ous[id]; exists {
+		return nil
+	}
+	if !strings.HasPrefix(id, "ou-") {
+		return fmt.Errorf("parent %s not found in organization", id)
+	}
+	m.ous[id] = RootID
+	return nil
+}
+
 // Name returns "Mock AWS" for logging/debugging.
 func (m *AWSClient) Name() string {
 	return "Mock AWS"
 }
 
-// logOperation records an operation for testing/debugging.
-// This acquires the lock - use logOperationLocked when already holding the lock.
-func (m *AWSClient) logOperation(op string) {
+// record appends a call to the operation log.
+// This acquires the lock - use recordLocked when already holding the lock.
+func (m *AWSClient) record(op Operation, text string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.operations = append(m.operations, op)
+	m.recordLocked(op, text)
 }
 
-// logOperationLocked records an operation without acquiring the lock.
+// recordLocked appends a call to the operation log, stamping its sequence
+// number and time. text is the human-readable form (see GetOperations).
 // MUST only be called when m.mu is already held.
-func (m *AWSClient) logOperationLocked(op string) {
+func (m *AWSClient) recordLocked(op Operation, text string) {
+	op.Seq = len(m.operations) + 1
+	op.Time = m.clock.Now()
+	if op.AccountID == "" {
+		op.AccountID = accountOf(op.Request)
+	}
+	op.text = text
 	m.operations = append(m.operations, op)
 }
 
-// GetOperations returns all operations that have been performed.
-// Useful for testing - verify that the right AWS operations happened.
+// failLocked records a call that failed with err and returns err.
+// MUST only be called when m.mu is already held.
+func (m *AWSClient) failLocked(method string, req any, err error) error {
+	m.recordLocked(Operation{Method: method, Request: req, Err: err}, fmt.Sprintf("%s -> error: %v", method, err))
+	return err
+}
+
+// InjectError queues errors to be returned by the named method.
+//
+// Each call to the method consumes one queued error; once the queue is
+// empty the method behaves normally again. A nil entry lets one call
+// through, so InjectError("CreateAccount", nil, err) fails the second call.
+//
+// Usage:
+//
+//	mockAWS.InjectError("CreateAccount", ports.NewAWSError(ports.CodeEmailAlreadyExists, "email in use"))
+func (m *AWSClient) InjectError(method string, errs ...error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.injected[method] = append(m.injected[method], errs...)
+}
+
+// GetOperations returns every operation in human-readable form, e.g.
+// "CreateAccount(TPA_DEV, ...) -> 100000000001". Handy when debugging a
+// test; assert on Operations instead, which doesn't depend on formatting.
 func (m *AWSClient) GetOperations() []string {
+	return m.Operations().Strings()
+}
+
+// Operations returns a copy of the typed operation log.
+func (m *AWSClient) Operations() OperationLog {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(OperationLog(m.operations))
+}
+
+// Mark returns the sequence number of the latest operation, to pass to
+// OperationLog.Since later.
+//
+//	mark := mockAWS.Mark()
+//	// ... second run ...
+//	mockAWS.Operations().Since(mark)
+func (m *AWSClient) Mark() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	ops := make([]string, len(m.operations))
-	copy(ops, m.operations)
-	return ops
+	return len(m.operations)
 }
 
 // CreateAccount simulates creating an AWS account in AWS Organizations.
 func (m *AWSClient) CreateAccount(ctx context.Context, req ports.AWSCreateAccountRequest) (string, error) {
+	if err := m.intercept(ctx, "CreateAccount", req); err != nil {
+		return "", err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Check if AWS account already exists
 	if existingID, exists := m.accountsByName[req.Name]; exists {
-		m.logOperationLocked(fmt.Sprintf("CreateAccount(%s) - already exists: %s", req.Name, existingID))
+		m.recordLocked(Operation{Method: "CreateAccount", AccountID: existingID, Request: req, Result: existingID},
+			fmt.Sprintf("CreateAccount(%s) - already exists: %s", req.Name, existingID))
 		return existingID, nil
 	}
 
@@ -108,110 +260,662 @@ func (m *AWSClient) CreateAccount(ctx context.Context, req ports.AWSCreateAccoun
 		ID:        accountID,
 		Name:      req.Name,
 		Email:     req.Email,
-		OrgUnitID: req.OrgUnitID,
-		CreatedAt: time.Now(),
+		ParentID:  RootID,
+		Status:    ports.AccountStatusActive,
+		CreatedAt: m.clock.Now(),
+	}
+	text := fmt.Sprintf("CreateAccount(%s, %s, %s) -> %s", req.Name, req.Email, req.OrgUnitID, accountID)
+	if m.lifecycle != nil {
+		m.requestCreationLocked(account)
+		text += " (" + m.creations[accountID].state + ")"
+	} else {
+		m.accounts[accountID] = account
+		m.accountsByName[req.Name] = accountID
 	}
-	m.accounts[accountID] = account
-	m.accountsByName[req.Name] = accountID
 
-	m.logOperationLocked(fmt.Sprintf("CreateAccount(%s, %s, %s) -> %s", req.Name, req.Email, req.OrgUnitID, accountID))
+	m.recordLocked(Operation{Method: "CreateAccount", AccountID: accountID, Request: req, Result: accountID}, text)
 
 	return accountID, nil
 }
 
-// WaitForAccountCreation simulates waiting for AWS account creation (instant for mock).
+// WaitForAccountCreation simulates waiting for AWS account creation.
 //
-// Real AWS Organizations creates accounts asynchronously. Mock is instant.
+// Real AWS Organizations creates accounts asynchronously. By default the
+// mock is instant; with SimulateAccountCreation it polls the request's
+// status on the mock's clock.
 func (m *AWSClient) WaitForAccountCreation(ctx context.Context, accountID string) error {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "WaitForAccountCreation", req); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
+	if lifecycle := m.lifecycle; lifecycle != nil {
+		m.mu.Unlock()
+		return m.waitForCreation(ctx, req, lifecycle.PollInterval)
+	}
 	defer m.mu.Unlock()
 
 	if _, exists := m.accounts[accountID]; !exists {
-		return fmt.Errorf("AWS account %s not found", accountID)
+		return m.failLocked("WaitForAccountCreation", req, fmt.Errorf("AWS account %s not found", accountID))
 	}
 
-	m.logOperationLocked(fmt.Sprintf("WaitForAccountCreation(%s) -> ready", accountID))
+	m.recordLocked(Operation{Method: "WaitForAccountCreation", Request: req}, fmt.Sprintf("WaitForAccountCreation(%s) -> ready", accountID))
 	return nil
 }
 
 // GetAccountByName retrieves an AWS account ID by name.
 func (m *AWSClient) GetAccountByName(ctx context.Context, name string) (string, error) {
+	req := NameRequest{Name: name}
+	if err := m.intercept(ctx, "GetAccountByName", req); err != nil {
+		return "", err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if accountID, exists := m.accountsByName[name]; exists {
-		m.logOperationLocked(fmt.Sprintf("GetAccountByName(%s) -> %s", name, accountID))
+		m.recordLocked(Operation{Method: "GetAccountByName", AccountID: accountID, Request: req, Result: accountID},
+			fmt.Sprintf("GetAccountByName(%s) -> %s", name, accountID))
 		return accountID, nil
 	}
 
-	m.logOperationLocked(fmt.Sprintf("GetAccountByName(%s) -> not found", name))
+	m.recordLocked(Operation{Method: "GetAccountByName", Request: req, Result: ""}, fmt.Sprintf("GetAccountByName(%s) -> not found", name))
 	return "", nil
 }
 
+// ListAccounts returns every account in the mock organization, ordered by ID.
+func (m *AWSClient) ListAccounts(ctx context.Context) ([]ports.AWSAccount, error) {
+	if err := m.intercept(ctx, "ListAccounts", nil); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	accounts := make([]ports.AWSAccount, 0, len(m.accounts))
+	for _, id := range slices.Sorted(maps.Keys(m.accounts)) {
+		account := m.accounts[id]
+		accounts = append(accounts, ports.AWSAccount{
+			ID:     account.ID,
+			Name:   account.Name,
+			Email:  account.Email,
+			Status: account.Status,
+		})
+	}
+
+	m.recordLocked(Operation{Method: "ListAccounts", Result: slices.Clone(accounts)}, fmt.Sprintf("ListAccounts() -> %d accounts", len(accounts)))
+	return accounts, nil
+}
+
+// DescribeAccount returns the account, or nil if it doesn't exist.
+func (m *AWSClient) DescribeAccount(ctx context.Context, accountID string) (*ports.AWSAccount, error) {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "DescribeAccount", req); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		m.recordLocked(Operation{Method: "DescribeAccount", Request: req, Result: (*ports.AWSAccount)(nil)},
+			fmt.Sprintf("DescribeAccount(%s) -> not found", accountID))
+		return nil, nil
+	}
+
+	described := ports.AWSAccount{
+		ID:     account.ID,
+		Name:   account.Name,
+		Email:  account.Email,
+		Status: account.Status,
+	}
+	m.recordLocked(Operation{Method: "DescribeAccount", Request: req, Result: described},
+		fmt.Sprintf("DescribeAccount(%s) -> %s", accountID, account.Status))
+	return &described, nil
+}
+
+// GetAccountParent returns the root or OU containing the account.
+func (m *AWSClient) GetAccountParent(ctx context.Context, accountID string) (string, error) {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "GetAccountParent", req); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return "", m.failLocked("GetAccountParent", req, fmt.Errorf("AWS account %s not found", accountID))
+	}
+
+	m.recordLocked(Operation{Method: "GetAccountParent", Request: req, Result: account.ParentID},
+		fmt.Sprintf("GetAccountParent(%s) -> %s", accountID, account.ParentID))
+	return account.ParentID, nil
+}
+
+// TagAccount simulates tagging an account in AWS Organizations.
+func (m *AWSClient) TagAccount(ctx context.Context, accountID string, tags map[string]string) error {
+	req := TagRequest{AccountID: accountID, Tags: maps.Clone(tags)}
+	if err := m.intercept(ctx, "TagAccount", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return m.failLocked("TagAccount", req, fmt.Errorf("AWS account %s not found", accountID))
+	}
+	if account.Tags == nil {
+		account.Tags = make(map[string]string)
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		account.Tags[key] = tags[key]
+		pairs = append(pairs, key+"="+tags[key])
+	}
+	m.recordLocked(Operation{Method: "TagAccount", Request: req}, fmt.Sprintf("TagAccount(%s, %s)", accountID, strings.Join(pairs, ", ")))
+	return nil
+}
+
+// GetAccountTags returns a copy of the account's tags.
+func (m *AWSClient) GetAccountTags(ctx context.Context, accountID string) (map[string]string, error) {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "GetAccountTags", req); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, m.failLocked("GetAccountTags", req, fmt.Errorf("AWS account %s not found", accountID))
+	}
+
+	tags := maps.Clone(account.Tags)
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	m.recordLocked(Operation{Method: "GetAccountTags", Request: req, Result: maps.Clone(tags)},
+		fmt.Sprintf("GetAccountTags(%s) -> %d tags", accountID, len(account.Tags)))
+	return tags, nil
+}
+
+// AccountTags returns a copy of an account's tags (test helper, not part of the port).
+func (m *AWSClient) AccountTags(accountID string) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil
+	}
+	return maps.Clone(account.Tags)
+}
+
+// MoveAccount simulates moving an AWS account between organizational units.
+func (m *AWSClient) MoveAccount(ctx context.Context, accountID, sourceParentID, destinationParentID string) error {
+	req := MoveRequest{AccountID: accountID, SourceParentID: sourceParentID, DestinationParentID: destinationParentID}
+	if err := m.intercept(ctx, "MoveAccount", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return m.failLocked("MoveAccount", req, fmt.Errorf("AWS account %s not found", accountID))
+	}
+	if account.ParentID != sourceParentID {
+		return m.failLocked("MoveAccount", req,
+			fmt.Errorf("AWS account %s is not in %s (current parent: %s)", accountID, sourceParentID, account.ParentID))
+	}
+	if err := m.ensureParentLocked(destinationParentID); err != nil {
+		return m.failLocked("MoveAccount", req, err)
+	}
+
+	account.ParentID = destinationParentID
+	m.recordLocked(Operation{Method: "MoveAccount", Request: req}, fmt.Sprintf("MoveAccount(%s, %s -> %s)", accountID, sourceParentID, destinationParentID))
+	return nil
+}
+
+// CloseAccount simulates closing an account.
+//
+// Real AWS passes through PENDING_CLOSURE; the mock suspends immediately.
+func (m *AWSClient) CloseAccount(ctx context.Context, accountID string) error {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "CloseAccount", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return m.failLocked("CloseAccount", req, fmt.Errorf("AWS account %s not found", accountID))
+	}
+	if account.Status != ports.AccountStatusActive {
+		return m.failLocked("CloseAccount", req,
+			ports.NewAWSError(ports.CodeAccountAlreadyClosed, fmt.Sprintf("account %s is %s", accountID, account.Status)))
+	}
+	if m.closed >= m.closeQuota {
+		err := ports.NewAWSError(ports.CodeCloseQuotaExceeded, fmt.Sprintf("closed %d of %d allowed accounts", m.closed, m.closeQuota))
+		m.recordLocked(Operation{Method: "CloseAccount", Request: req, Err: err}, fmt.Sprintf("CloseAccount(%s) -> quota exceeded", accountID))
+		return err
+	}
+
+	account.Status = ports.AccountStatusSuspended
+	m.closed++
+	m.recordLocked(Operation{Method: "CloseAccount", Request: req}, fmt.Sprintf("CloseAccount(%s) -> %s", accountID, account.Status))
+	return nil
+}
+
 // CreateOIDCProviderForGitHub simulates creating AWS IAM OIDC provider for GitHub Actions.
 func (m *AWSClient) CreateOIDCProviderForGitHub(ctx context.Context, accountID string) error {
-	m.logOperation(fmt.Sprintf("CreateOIDCProviderForGitHub(%s)", accountID))
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "CreateOIDCProviderForGitHub", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.oidcProviders[accountID] = true
+	m.recordLocked(Operation{Method: "CreateOIDCProviderForGitHub", Request: req}, fmt.Sprintf("CreateOIDCProviderForGitHub(%s)", accountID))
+	return nil
+}
+
+// GetOIDCProviderForGitHub returns the OIDC provider ARN if it was created.
+func (m *AWSClient) GetOIDCProviderForGitHub(ctx context.Context, accountID string) (string, error) {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "GetOIDCProviderForGitHub", req); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.oidcProviders[accountID] {
+		m.recordLocked(Operation{Method: "GetOIDCProviderForGitHub", Request: req, Result: ""},
+			fmt.Sprintf("GetOIDCProviderForGitHub(%s) -> not found", accountID))
+		return "", nil
+	}
+
+	arn := oidcProviderARN(accountID)
+	m.recordLocked(Operation{Method: "GetOIDCProviderForGitHub", Request: req, Result: arn},
+		fmt.Sprintf("GetOIDCProviderForGitHub(%s) -> %s", accountID, arn))
+	return arn, nil
+}
+
+// DeleteOIDCProviderForGitHub simulates deleting the GitHub OIDC provider.
+func (m *AWSClient) DeleteOIDCProviderForGitHub(ctx context.Context, accountID string) error {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "DeleteOIDCProviderForGitHub", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.oidcProviders[accountID] {
+		return m.failLocked("DeleteOIDCProviderForGitHub", req, fmt.Errorf("OIDC provider not found in AWS account %s", accountID))
+	}
+
+	delete(m.oidcProviders, accountID)
+	m.recordLocked(Operation{Method: "DeleteOIDCProviderForGitHub", Request: req}, fmt.Sprintf("DeleteOIDCProviderForGitHub(%s)", accountID))
 	return nil
 }
 
 // CreateGitHubActionsRole simulates creating an AWS IAM role for GitHub Actions.
 func (m *AWSClient) CreateGitHubActionsRole(ctx context.Context, req ports.AWSCreateRoleRequest) (string, error) {
-	roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", req.AccountID, req.RoleName)
-	m.logOperation(fmt.Sprintf("CreateGitHubActionsRole(%s, %s/%s) -> %s",
-		req.AccountID, req.GitHubOrg, req.GitHubRepo, roleARN))
+	if err := m.intercept(ctx, "CreateGitHubActionsRole", req); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	roleARN := roleARN(req.AccountID, req.RoleName)
+	m.roles[resourceKey(req.AccountID, req.RoleName)] = req
+	m.recordLocked(Operation{Method: "CreateGitHubActionsRole", Request: req, Result: roleARN},
+		fmt.Sprintf("CreateGitHubActionsRole(%s, %s/%s) -> %s", req.AccountID, req.GitHubOrg, req.GitHubRepo, roleARN))
 	return roleARN, nil
 }
 
+// GetRole returns the role ARN if it was created.
+func (m *AWSClient) GetRole(ctx context.Context, accountID, roleName string) (string, error) {
+	req := ResourceRequest{AccountID: accountID, Name: roleName}
+	if err := m.intercept(ctx, "GetRole", req); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.roles[resourceKey(accountID, roleName)]; !exists {
+		m.recordLocked(Operation{Method: "GetRole", Request: req, Result: ""}, fmt.Sprintf("GetRole(%s, %s) -> not found", accountID, roleName))
+		return "", nil
+	}
+
+	arn := roleARN(accountID, roleName)
+	m.recordLocked(Operation{Method: "GetRole", Request: req, Result: arn}, fmt.Sprintf("GetRole(%s, %s) -> %s", accountID, roleName, arn))
+	return arn, nil
+}
+
+// DeleteRole simulates deleting an IAM role.
+func (m *AWSClient) DeleteRole(ctx context.Context, accountID, roleName string) error {
+	req := ResourceRequest{AccountID: accountID, Name: roleName}
+	if err := m.intercept(ctx, "DeleteRole", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := resourceKey(accountID, roleName)
+	if _, exists := m.roles[key]; !exists {
+		return m.failLocked("DeleteRole", req, fmt.Errorf("IAM role %s not found in AWS account %s", roleName, accountID))
+	}
+
+	delete(m.roles, key)
+	m.recordLocked(Operation{Method: "DeleteRole", Request: req}, fmt.Sprintf("DeleteRole(%s, %s)", accountID, roleName))
+	return nil
+}
+
 // BootstrapCDK simulates AWS CDK bootstrap.
 func (m *AWSClient) BootstrapCDK(ctx context.Context, accountID, region, trustAccountID string) error {
-	m.logOperation(fmt.Sprintf("BootstrapCDK(%s, %s, trust=%s)", accountID, region, trustAccountID))
+	req := CDKRequest{AccountID: accountID, Region: region, TrustAccountID: trustAccountID}
+	if err := m.intercept(ctx, "BootstrapCDK", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cdkBootstraps[resourceKey(accountID, region)] = trustAccountID
+	m.recordLocked(Operation{Method: "BootstrapCDK", Request: req}, fmt.Sprintf("BootstrapCDK(%s, %s, trust=%s)", accountID, region, trustAccountID))
 	return nil
 }
 
+// IsCDKBootstrapped reports whether BootstrapCDK ran for the account and region.
+func (m *AWSClient) IsCDKBootstrapped(ctx context.Context, accountID, region string) (bool, error) {
+	req := CDKRequest{AccountID: accountID, Region: region}
+	if err := m.intercept(ctx, "IsCDKBootstrapped", req); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, exists := m.cdkBootstraps[resourceKey(accountID, region)]
+	m.recordLocked(Operation{Method: "IsCDKBootstrapped", Request: req, Result: exists},
+		fmt.Sprintf("IsCDKBootstrapped(%s, %s) -> %t", accountID, region, exists))
+	return exists, nil
+}
+
 // CreateBudget simulates creating an AWS Budget.
 func (m *AWSClient) CreateBudget(ctx context.Context, req ports.AWSCreateBudgetRequest) error {
-	m.logOperation(fmt.Sprintf("CreateBudget(%s, %s, limit=$%.2f, alert=$%.2f)",
+	if err := m.intercept(ctx, "CreateBudget", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.budgets[resourceKey(req.AccountID, req.BudgetName)] = budgetFromRequest(req)
+	m.recordLocked(Operation{Method: "CreateBudget", Request: req}, fmt.Sprintf("CreateBudget(%s, %s, limit=$%.2f, alert=$%.2f)",
 		req.AccountID, req.BudgetName, req.LimitAmount, req.AlertAmount))
 	return nil
 }
 
-// CreateBillingAlarm simulates creating an AWS CloudWatch billing alarm.
+// GetBudget returns the budget if it was created.
+func (m *AWSClient) GetBudget(ctx context.Context, accountID, budgetName string) (*ports.AWSBudget, error) {
+	req := ResourceRequest{AccountID: accountID, Name: budgetName}
+	if err := m.intercept(ctx, "GetBudget", req); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	budget, exists := m.budgets[resourceKey(accountID, budgetName)]
+	if !exists {
+		m.recordLocked(Operation{Method: "GetBudget", Request: req, Result: (*ports.AWSBudget)(nil)},
+			fmt.Sprintf("GetBudget(%s, %s) -> not found", accountID, budgetName))
+		return nil, nil
+	}
+
+	m.recordLocked(Operation{Method: "GetBudget", Request: req, Result: budget},
+		fmt.Sprintf("GetBudget(%s, %s) -> limit=$%.2f", accountID, budgetName, budget.LimitAmount))
+	budget.AlertPercents = append([]int(nil), budget.AlertPercents...)
+	return &budget, nil
+}
+
+// UpdateBudget simulates updating an existing AWS Budget.
+func (m *AWSClient) UpdateBudget(ctx context.Context, req ports.AWSCreateBudgetRequest) error {
+	if err := m.intercept(ctx, "UpdateBudget", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := resourceKey(req.AccountID, req.BudgetName)
+	if _, exists := m.budgets[key]; !exists {
+		return m.failLocked("UpdateBudget", req, fmt.Errorf("AWS budget %s not found in account %s", req.BudgetName, req.AccountID))
+	}
+
+	m.budgets[key] = budgetFromRequest(req)
+	m.recordLocked(Operation{Method: "UpdateBudget", Request: req}, fmt.Sprintf("UpdateBudget(%s, %s, limit=$%.2f, alert=$%.2f)",
+		req.AccountID, req.BudgetName, req.LimitAmount, req.AlertAmount))
+	return nil
+}
+
+// DeleteBudget simulates deleting an AWS Budget.
+func (m *AWSClient) DeleteBudget(ctx context.Context, accountID, budgetName string) error {
+	req := ResourceRequest{AccountID: accountID, Name: budgetName}
+	if err := m.intercept(ctx, "DeleteBudget", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := resourceKey(accountID, budgetName)
+	if _, exists := m.budgets[key]; !exists {
+		return m.failLocked("DeleteBudget", req, fmt.Errorf("AWS budget %s not found in account %s", budgetName, accountID))
+	}
+
+	delete(m.budgets, key)
+	m.recordLocked(Operation{Method: "DeleteBudget", Request: req}, fmt.Sprintf("DeleteBudget(%s, %s)", accountID, budgetName))
+	return nil
+}
+
+// CreateBillingAlarm simulates creating (or updating) an AWS CloudWatch billing alarm.
 func (m *AWSClient) CreateBillingAlarm(ctx context.Context, req ports.AWSCreateBillingAlarmRequest) error {
-	m.logOperation(fmt.Sprintf("CreateBillingAlarm(%s, %s, threshold=$%.2f)",
+	if err := m.intercept(ctx, "CreateBillingAlarm", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.alarms[resourceKey(req.AccountID, req.AlarmName)] = ports.AWSBillingAlarm{
+		AccountID: req.AccountID,
+		AlarmName: req.AlarmName,
+		Threshold: req.Threshold,
+		TopicARN:  req.TopicARN,
+	}
+	m.recordLocked(Operation{Method: "CreateBillingAlarm", Request: req}, fmt.Sprintf("CreateBillingAlarm(%s, %s, threshold=$%.2f)",
 		req.AccountID, req.AlarmName, req.Threshold))
 	return nil
 }
 
+// GetBillingAlarm returns the alarm if it was created.
+func (m *AWSClient) GetBillingAlarm(ctx context.Context, accountID, alarmName string) (*ports.AWSBillingAlarm, error) {
+	req := ResourceRequest{AccountID: accountID, Name: alarmName}
+	if err := m.intercept(ctx, "GetBillingAlarm", req); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alarm, exists := m.alarms[resourceKey(accountID, alarmName)]
+	if !exists {
+		m.recordLocked(Operation{Method: "GetBillingAlarm", Request: req, Result: (*ports.AWSBillingAlarm)(nil)},
+			fmt.Sprintf("GetBillingAlarm(%s, %s) -> not found", accountID, alarmName))
+		return nil, nil
+	}
+
+	m.recordLocked(Operation{Method: "GetBillingAlarm", Request: req, Result: alarm},
+		fmt.Sprintf("GetBillingAlarm(%s, %s) -> threshold=$%.2f", accountID, alarmName, alarm.Threshold))
+	return &alarm, nil
+}
+
+// DeleteBillingAlarm simulates deleting a CloudWatch billing alarm.
+func (m *AWSClient) DeleteBillingAlarm(ctx context.Context, accountID, alarmName string) error {
+	req := ResourceRequest{AccountID: accountID, Name: alarmName}
+	if err := m.intercept(ctx, "DeleteBillingAlarm", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := resourceKey(accountID, alarmName)
+	if _, exists := m.alarms[key]; !exists {
+		return m.failLocked("DeleteBillingAlarm", req, fmt.Errorf("CloudWatch alarm %s not found in AWS account %s", alarmName, accountID))
+	}
+
+	delete(m.alarms, key)
+	m.recordLocked(Operation{Method: "DeleteBillingAlarm", Request: req}, fmt.Sprintf("DeleteBillingAlarm(%s, %s)", accountID, alarmName))
+	return nil
+}
+
 // CreateSNSTopic simulates creating an AWS SNS topic.
 func (m *AWSClient) CreateSNSTopic(ctx context.Context, accountID, topicName string) (string, error) {
+	req := ResourceRequest{AccountID: accountID, Name: topicName}
+	if err := m.intercept(ctx, "CreateSNSTopic", req); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	topicARN := fmt.Sprintf("arn:aws:sns:us-east-1:%s:%s", accountID, topicName)
-	m.logOperation(fmt.Sprintf("CreateSNSTopic(%s, %s) -> %s", accountID, topicName, topicARN))
+	if _, exists := m.topics[topicARN]; !exists {
+		m.topics[topicARN] = nil
+	}
+	m.recordLocked(Operation{Method: "CreateSNSTopic", Request: req, Result: topicARN},
+		fmt.Sprintf("CreateSNSTopic(%s, %s) -> %s", accountID, topicName, topicARN))
 	return topicARN, nil
 }
 
 // SubscribeEmailToSNSTopic simulates subscribing an email to an AWS SNS topic.
 func (m *AWSClient) SubscribeEmailToSNSTopic(ctx context.Context, topicARN, email string) error {
-	m.logOperation(fmt.Sprintf("SubscribeEmailToSNSTopic(%s, %s)", topicARN, email))
+	req := TopicRequest{TopicARN: topicARN, Email: email}
+	if err := m.intercept(ctx, "SubscribeEmailToSNSTopic", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.topics[topicARN]; !exists {
+		return m.failLocked("SubscribeEmailToSNSTopic", req, fmt.Errorf("AWS SNS topic %s not found", topicARN))
+	}
+
+	if !slices.Contains(m.topics[topicARN], email) {
+		m.topics[topicARN] = append(m.topics[topicARN], email)
+	}
+	m.recordLocked(Operation{Method: "SubscribeEmailToSNSTopic", Request: req}, fmt.Sprintf("SubscribeEmailToSNSTopic(%s, %s)", topicARN, email))
+	return nil
+}
+
+// DeleteSNSTopic simulates deleting an SNS topic and its subscriptions.
+func (m *AWSClient) DeleteSNSTopic(ctx context.Context, topicARN string) error {
+	req := TopicRequest{TopicARN: topicARN}
+	if err := m.intercept(ctx, "DeleteSNSTopic", req); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// SNS treats deleting a missing topic as success
+	if _, exists := m.topics[topicARN]; !exists {
+		m.recordLocked(Operation{Method: "DeleteSNSTopic", Request: req}, fmt.Sprintf("DeleteSNSTopic(%s) - not found", topicARN))
+		return nil
+	}
+
+	delete(m.topics, topicARN)
+	m.recordLocked(Operation{Method: "DeleteSNSTopic", Request: req}, fmt.Sprintf("DeleteSNSTopic(%s)", topicARN))
 	return nil
 }
 
 // AssumeRole simulates AWS STS AssumeRole.
 func (m *AWSClient) AssumeRole(ctx context.Context, roleARN, sessionName string) (*ports.AWSCredentials, error) {
-	m.logOperation(fmt.Sprintf("AssumeRole(%s, %s)", roleARN, sessionName))
-	return &ports.AWSCredentials{
+	req := AssumeRoleRequest{RoleARN: roleARN, SessionName: sessionName}
+	if err := m.intercept(ctx, "AssumeRole", req); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	creds := ports.AWSCredentials{
 		AccessKeyID:     "ASIAMOCKEXAMPLEKEY123",
 		SecretAccessKey: "MockSecretAccessKey123456789012345678901234",
 		SessionToken:    "MockSessionToken" + sessionName,
-		Expiration:      time.Now().Add(time.Hour),
-	}, nil
+		Expiration:      m.clock.Now().Add(time.Hour),
+	}
+	m.recordLocked(Operation{Method: "AssumeRole", Request: req, Result: creds}, fmt.Sprintf("AssumeRole(%s, %s)", roleARN, sessionName))
+	return &creds, nil
 }
 
 // GetCallerIdentity simulates AWS STS GetCallerIdentity.
 func (m *AWSClient) GetCallerIdentity(ctx context.Context) (*ports.AWSCallerIdentity, error) {
-	m.logOperation("GetCallerIdentity()")
-	return &ports.AWSCallerIdentity{
+	if err := m.intercept(ctx, "GetCallerIdentity", nil); err != nil {
+		return nil, err
+	}
+
+	identity := ports.AWSCallerIdentity{
 		AccountID: "999999999999",
 		UserID:    "AIDAMOCKUSERID",
 		ARN:       "arn:aws:iam::999999999999:user/mock-user",
-	}, nil
+	}
+	m.record(Operation{Method: "GetCallerIdentity", Result: identity}, "GetCallerIdentity()")
+	return &identity, nil
+}
+
+func resourceKey(accountID, name string) string {
+	return accountID + "/" + name
+}
+
+func roleARN(accountID, roleName string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:role/%s", accountID, roleName)
+}
+
+func oidcProviderARN(accountID string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:oidc-provider/token.actions.githubusercontent.com", accountID)
+}
+
+func budgetFromRequest(req ports.AWSCreateBudgetRequest) ports.AWSBudget {
+	return ports.AWSBudget{
+		AccountID:     req.AccountID,
+		BudgetName:    req.BudgetName,
+		LimitAmount:   req.LimitAmount,
+		AlertAmount:   req.AlertAmount,
+		Email:         req.Email,
+		AlertPercents: append([]int(nil), req.AlertPercents...),
+	}
 }
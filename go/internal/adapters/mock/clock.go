@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a fake ports.Clock for testing code that waits.
+//
+// Time only moves when something waits on it: After advances the clock by
+// the requested duration and fires immediately. Every wait is recorded, so
+// tests can assert on backoff delays without actually sleeping.
+//
+// Usage:
+//
+//	clock := mock.NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+//	client := retry.New(mockAWS, retry.WithClock(clock))
+//	// ... run code ...
+//	clock.Waits() // [500ms 1s 2s]
+type Clock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+// NewClock creates a fake clock starting at start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the fake current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After advances the clock by d and returns a channel that is already ready.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waits = append(c.waits, d)
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Advance moves the clock forward without recording a wait.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Waits returns every duration passed to After, in order.
+func (c *Clock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	waits := make([]time.Duration, len(c.waits))
+	copy(waits, c.waits)
+	return waits
+}
@@ -0,0 +1,250 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// AnyMethod registers a fault for every AWSClient method (see AddFault).
+const AnyMethod = "*"
+
+// Call describes one call to a mock method, as seen by a Fault.
+type Call struct {
+	Method      string // Port method name, e.g. "CreateAccount"
+	N           int    // 1-based count of calls to Method so far, including this one
+	Request     any    // Same value the operation log records
+	AccountID   string // Account the call applies to, if known
+	AccountName string // Name of that account, or the name being created or looked up
+
+	clock ports.Clock
+}
+
+// Fault decides how one call to the mock behaves before it runs.
+//
+// Returning an error fails the call: the error is returned to the caller,
+// recorded in the operation log, and the call has no side effects.
+// Returning nil lets the call through. A fault may block (see Delay), but
+// must give up when ctx is done.
+type Fault func(ctx context.Context, call Call) error
+
+// AddFault attaches a fault to the named method, or to every method with
+// AnyMethod. Faults run in the order they were added, after errors queued
+// with InjectError; the first error wins.
+//
+// Usage:
+//
+//	mockAWS.AddFault("CreateAccount", mock.FailFor("TPA_STAGING", mock.AccountLimitError()))
+//	mockAWS.AddFault("WaitForAccountCreation", mock.Delay(2*time.Minute))
+//	mockAWS.AddFault(mock.AnyMethod, mock.FailFirst(2, mock.ThrottlingError()))
+func (m *AWSClient) AddFault(method string, fault Fault) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults[method] = append(m.faults[method], fault)
+}
+
+// ClearFaults removes every fault added with AddFault.
+func (m *AWSClient) ClearFaults() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.faults)
+}
+
+// SetClock replaces the clock the mock uses for operation timestamps and
+// for Delay. Defaults to the system clock. With a *Clock, delays advance
+// fake time instead of sleeping.
+func (m *AWSClient) SetClock(clock ports.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock
+}
+
+// intercept runs before every mock method: it finishes simulated account
+// creations that are due, pops a queued InjectError error, then runs the
+// method's faults. A failure is logged with req and
+// returned; the method must return it without side effects.
+func (m *AWSClient) intercept(ctx context.Context, method string, req any) error {
+	m.mu.Lock()
+	m.settleLocked()
+	m.calls[method]++
+	call := Call{
+		Method:    method,
+		N:         m.calls[method],
+		Request:   req,
+		AccountID: accountOf(req),
+		clock:     m.clock,
+	}
+	call.AccountName = m.accountNameLocked(req, call.AccountID)
+
+	var err error
+	if queue := m.injected[method]; len(queue) > 0 {
+		err = queue[0]
+		m.injected[method] = queue[1:]
+	}
+	var faults []Fault
+	faults = append(faults, m.faults[method]...)
+	faults = append(faults, m.faults[AnyMethod]...)
+	m.mu.Unlock()
+
+	// Faults run unlocked: they may block, and other calls must proceed
+	for _, fault := range faults {
+		if err != nil {
+			break
+		}
+		err = fault(ctx, call)
+	}
+	if err != nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.failLocked(method, req, err)
+	}
+	return err
+}
+
+// accountNameLocked returns the account name a request refers to.
+// MUST only be called when m.mu is already held.
+func (m *AWSClient) accountNameLocked(req any, accountID string) string {
+	switch r := req.(type) {
+	case ports.AWSCreateAccountRequest:
+		return r.Name
+	case NameRequest:
+		return r.Name
+	}
+	if account, exists := m.accounts[accountID]; exists {
+		return account.Name
+	}
+	if c, exists := m.creations[accountID]; exists {
+		return c.account.Name
+	}
+	return ""
+}
+
+// Fail fails every call with err.
+func Fail(err error) Fault {
+	return func(context.Context, Call) error {
+		return err
+	}
+}
+
+// FailWhen fails the calls matching match with err.
+func FailWhen(match func(Call) bool, err error) Fault {
+	return func(_ context.Context, call Call) error {
+		if match(call) {
+			return err
+		}
+		return nil
+	}
+}
+
+// FailFor fails every call concerning the named account: creating or
+// looking it up by name, and any call on its account ID once it exists.
+func FailFor(accountName string, err error) Fault {
+	return FailWhen(func(call Call) bool { return call.AccountName == accountName }, err)
+}
+
+// FailNth fails the nth call the fault sees (1-based) and lets every other
+// call through. For n < 1 no call is failed.
+func FailNth(n int, err error) Fault {
+	if n < 1 {
+		return Script()
+	}
+	return Script(append(make([]Fault, n-1), Fail(err))...)
+}
+
+// FailFirst fails the first n calls the fault sees, then lets calls
+// through. Use it to test that retries eventually succeed. For n < 1 no
+// call is failed.
+func FailFirst(n int, err error) Fault {
+	steps := make([]Fault, max(n, 0))
+	for i := range steps {
+		steps[i] = Fail(err)
+	}
+	return Script(steps...)
+}
+
+// Delay holds each call for d on the mock's clock, or until ctx is done,
+// in which case the call fails with ctx's error.
+func Delay(d time.Duration) Fault {
+	return func(ctx context.Context, call Call) error {
+		select {
+		case <-call.clock.After(d):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Hang blocks each call until ctx is done, then fails it with ctx's error.
+// Unlike Delay, it never completes on a fake clock, which makes timeout
+// tests deterministic.
+func Hang() Fault {
+	return func(ctx context.Context, _ Call) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+}
+
+// Chain runs faults in order on each call and returns the first error,
+// e.g. Chain(Delay(time.Second), Fail(err)) for a slow failure.
+func Chain(faults ...Fault) Fault {
+	return func(ctx context.Context, call Call) error {
+		for _, fault := range faults {
+			if fault == nil {
+				continue
+			}
+			if err := fault(ctx, call); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Script plays one step per call: the first call the fault sees runs
+// steps[0], the second steps[1], and so on. A nil step lets its call
+// through, and calls after the last step are let through.
+//
+// Usage:
+//
+//	// Throttled, then slow, then fine
+//	mockAWS.AddFault("MoveAccount", mock.Script(
+//	    mock.Fail(mock.ThrottlingError()),
+//	    mock.Delay(5*time.Second),
+//	))
+func Script(steps ...Fault) Fault {
+	var (
+		mu   sync.Mutex
+		next int
+	)
+	return func(ctx context.Context, call Call) error {
+		mu.Lock()
+		i := next
+		next++
+		mu.Unlock()
+
+		if i >= len(steps) || steps[i] == nil {
+			return nil
+		}
+		return steps[i](ctx, call)
+	}
+}
+
+// ThrottlingError returns the error AWS reports when rate limiting a call.
+func ThrottlingError() error {
+	return ports.NewAWSError(ports.CodeTooManyRequests, "Rate exceeded")
+}
+
+// AccountLimitError returns the error Organizations reports when the
+// organization has as many accounts as its quota allows.
+func AccountLimitError() error {
+	return ports.NewAWSError(ports.CodeAccountLimitExceeded, "The number of accounts in the organization has reached the limit")
+}
+
+// ConcurrentModificationError returns the error Organizations reports when
+// another request is changing the organization.
+func ConcurrentModificationError() error {
+	return ports.NewAWSError(ports.CodeConcurrentModification, "AWS Organizations can't complete your request because it conflicts with another attempt to modify the same entity")
+}
@@ -0,0 +1,150 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func TestFailNth(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+	m.AddFault("GetAccountByName", FailNth(2, ThrottlingError()))
+
+	for i, wantErr := range []bool{false, true, false} {
+		_, err := m.GetAccountByName(ctx, "TPA_DEV")
+		if (err != nil) != wantErr {
+			t.Errorf("call %d: error = %v, want failure %t", i+1, err, wantErr)
+		}
+		if wantErr && !errors.Is(err, ports.ErrThrottled) {
+			t.Errorf("call %d: errors.Is(%v, ErrThrottled) = false", i+1, err)
+		}
+	}
+}
+
+func TestFailCountsBelowOneFailNothing(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+	m.AddFault("GetCallerIdentity", FailNth(0, ThrottlingError()))
+	m.AddFault("GetCallerIdentity", FailFirst(-1, ThrottlingError()))
+
+	for i := range 2 {
+		if _, err := m.GetCallerIdentity(ctx); err != nil {
+			t.Errorf("call %d: error = %v, want nil", i+1, err)
+		}
+	}
+}
+
+func TestFailForAccountName(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+	prodID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_PROD"})
+	m.AddFault(AnyMethod, FailFor("TPA_PROD", AccountLimitError()))
+
+	if _, err := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"}); err != nil {
+		t.Errorf("CreateAccount(TPA_DEV) error = %v, want nil", err)
+	}
+	if _, err := m.GetAccountByName(ctx, "TPA_PROD"); !errors.Is(err, ports.ErrAccountLimitExceeded) {
+		t.Errorf("GetAccountByName(TPA_PROD) error = %v, want ErrAccountLimitExceeded", err)
+	}
+	// Calls by account ID match too
+	if err := m.TagAccount(ctx, prodID, map[string]string{"Project": "TPA"}); err == nil {
+		t.Error("TagAccount(prod) should fail")
+	}
+	if tags, _ := m.GetAccountTags(ctx, prodID); len(tags) != 0 {
+		t.Errorf("Failed TagAccount should have no side effects, got %v", tags)
+	}
+
+	m.ClearFaults()
+	if err := m.TagAccount(ctx, prodID, map[string]string{"Project": "TPA"}); err != nil {
+		t.Errorf("TagAccount() after ClearFaults error = %v", err)
+	}
+}
+
+func TestScript(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+	clock := NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	m.SetClock(clock)
+	m.AddFault("GetCallerIdentity", Script(
+		Fail(ConcurrentModificationError()),
+		nil,
+		Chain(Delay(3*time.Second), Fail(ThrottlingError())),
+	))
+
+	var got []error
+	for range 4 {
+		_, err := m.GetCallerIdentity(ctx)
+		got = append(got, err)
+	}
+	if !errors.Is(got[0], ports.ErrConcurrentModification) || got[1] != nil ||
+		!errors.Is(got[2], ports.ErrThrottled) || got[3] != nil {
+		t.Errorf("errors = %v, want concurrent modification, nil, throttled, nil", got)
+	}
+	if waits := clock.Waits(); len(waits) != 1 || waits[0] != 3*time.Second {
+		t.Errorf("waits = %v, want [3s]", waits)
+	}
+
+	// Operations are stamped with the injected clock
+	last, _ := m.Operations().Last()
+	if !last.Time.Equal(clock.Now()) {
+		t.Errorf("last.Time = %v, want %v", last.Time, clock.Now())
+	}
+	// So are assumed credentials
+	if creds, _ := m.AssumeRole(ctx, "arn:aws:iam::100000000001:role/OrganizationAccountAccessRole", "test"); !creds.Expiration.Equal(clock.Now().Add(time.Hour)) {
+		t.Errorf("Expiration = %v, want an hour after %v", creds.Expiration, clock.Now())
+	}
+}
+
+func TestDelayHonoursContext(t *testing.T) {
+	m := NewAWSClient()
+	m.AddFault("WaitForAccountCreation", Delay(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.WaitForAccountCreation(ctx, "100000000001"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForAccountCreation() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestHang(t *testing.T) {
+	m := NewAWSClient()
+	m.SetClock(NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	m.AddFault("ListAccounts", Hang())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := m.ListAccounts(ctx)
+		done <- err
+	}()
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("ListAccounts() error = %v, want Canceled", err)
+	}
+	if failed := m.Operations().Failed(); failed.Count() != 1 {
+		t.Errorf("Expected the cancelled call to be logged, got %v", failed.Strings())
+	}
+}
+
+func TestCallCounts(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+	var seen []int
+	m.AddFault("IsCDKBootstrapped", func(_ context.Context, call Call) error {
+		seen = append(seen, call.N)
+		return nil
+	})
+	m.InjectError("IsCDKBootstrapped", errors.New("queued"))
+
+	for range 3 {
+		_, _ = m.IsCDKBootstrapped(ctx, "100000000001", "us-east-1")
+	}
+	// The queued error fails the first call before faults run
+	if len(seen) != 2 || seen[0] != 2 || seen[1] != 3 {
+		t.Errorf("fault saw calls %v, want [2 3]", seen)
+	}
+}
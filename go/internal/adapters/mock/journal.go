@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// JournalStore is an in-memory implementation of ports.JournalStore for testing.
+//
+// Journals are deep-copied on save and load, so a test can keep one store
+// across several "runs" to simulate a restart after a failure.
+type JournalStore struct {
+	mu       sync.Mutex
+	journals map[string]ports.RunJournal
+	saves    int
+}
+
+// NewJournalStore creates an empty in-memory journal store.
+func NewJournalStore() *JournalStore {
+	return &JournalStore{
+		journals: make(map[string]ports.RunJournal),
+	}
+}
+
+// LoadJournal returns a copy of the stored journal, or nil if none exists.
+func (s *JournalStore) LoadJournal(ctx context.Context, runID string) (*ports.RunJournal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	journal, exists := s.journals[runID]
+	if !exists {
+		return nil, nil
+	}
+
+	journal.Entries = append([]ports.JournalEntry(nil), journal.Entries...)
+	return &journal, nil
+}
+
+// SaveJournal stores a copy of the journal.
+func (s *JournalStore) SaveJournal(ctx context.Context, journal *ports.RunJournal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *journal
+	stored.Entries = append([]ports.JournalEntry(nil), journal.Entries...)
+	s.journals[journal.RunID] = stored
+	s.saves++
+	return nil
+}
+
+// SaveCount returns how many times SaveJournal has been called.
+// Useful for verifying that every step is persisted as it completes.
+func (s *JournalStore) SaveCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saves
+}
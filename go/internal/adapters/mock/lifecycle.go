@@ -0,0 +1,241 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Create-account request states, as Organizations reports them in
+// CreateAccountStatus.State.
+const (
+	CreateStateInProgress = "IN_PROGRESS"
+	CreateStateSucceeded  = "SUCCEEDED"
+	CreateStateFailed     = "FAILED"
+)
+
+// DefaultCreationPollInterval is how often WaitForAccountCreation checks a
+// simulated creation when AccountCreation.PollInterval is zero.
+const DefaultCreationPollInterval = 5 * time.Second
+
+// AccountCreation configures the simulated create-account lifecycle (see
+// SimulateAccountCreation).
+type AccountCreation struct {
+	Duration     time.Duration // Time from CreateAccount until SUCCEEDED or FAILED (see SetCreationDuration)
+	PollInterval time.Duration // Delay between status checks in WaitForAccountCreation
+}
+
+// CreateAccountStatus describes a create-account request.
+type CreateAccountStatus struct {
+	AccountID     string
+	AccountName   string
+	State         string // CreateState*
+	FailureReason string // e.g. ports.CodeEmailAlreadyExists; empty unless State is FAILED
+}
+
+// creation is a create-account request in flight or finished.
+type creation struct {
+	account *mockAWSAccount
+	done    time.Time // When the request leaves IN_PROGRESS
+	state   string
+	reason  string
+}
+
+// SimulateAccountCreation makes account creation asynchronous, like real
+// Organizations. By default the mock creates accounts instantly.
+//
+// Once enabled:
+//   - CreateAccount returns the new account's ID, but the request stays
+//     IN_PROGRESS for the configured duration on the mock's clock (see
+//     SetClock); the account isn't visible to lookups until it succeeds
+//   - WaitForAccountCreation polls DescribeCreateAccountStatus every
+//     PollInterval until the request succeeds or fails
+//   - Calling CreateAccount again while a request is in flight starts a
+//     second request, as in AWS: lookups can't see the first one yet
+//   - Requests fail with EMAIL_ALREADY_EXISTS when another account or
+//     request in flight uses the email, with ACCOUNT_LIMIT_EXCEEDED past SetAccountLimit, or with
+//     the reason set by FailAccountCreation; WaitForAccountCreation then
+//     returns a *ports.AWSError with that code
+//
+// Usage:
+//
+//	clock := mock.NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+//	mockAWS.SetClock(clock)
+//	mockAWS.SimulateAccountCreation(mock.AccountCreation{Duration: 2 * time.Minute, PollInterval: 10 * time.Second})
+//	mockAWS.FailAccountCreation("TPA_PROD", ports.CodeAccountLimitExceeded)
+func (m *AWSClient) SimulateAccountCreation(c AccountCreation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c.PollInterval <= 0 {
+		c.PollInterval = DefaultCreationPollInterval
+	}
+	m.lifecycle = &c
+}
+
+// SetCreationDuration overrides how long creating the named account takes.
+func (m *AWSClient) SetCreationDuration(accountName string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.creationDurations[accountName] = d
+}
+
+// FailAccountCreation makes creating the named account fail with reason
+// (e.g. ports.CodeEmailAlreadyExists) once its duration has passed.
+func (m *AWSClient) FailAccountCreation(accountName, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.creationFailures[accountName] = reason
+}
+
+// SetAccountLimit caps how many accounts the organization may hold,
+// including requests in flight. Zero (the default) means no limit.
+func (m *AWSClient) SetAccountLimit(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accountLimit = n
+}
+
+// DescribeCreateAccountStatus reports the state of the request that
+// created accountID. Accounts created before SimulateAccountCreation was
+// enabled report SUCCEEDED.
+//
+// Not part of ports.AWSClient: real adapters call it inside
+// WaitForAccountCreation. Each call is logged and goes through faults, so
+// tests can throttle or count polls.
+func (m *AWSClient) DescribeCreateAccountStatus(ctx context.Context, accountID string) (*CreateAccountStatus, error) {
+	req := AccountRequest{AccountID: accountID}
+	if err := m.intercept(ctx, "DescribeCreateAccountStatus", req); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var status CreateAccountStatus
+	if c, exists := m.creations[accountID]; exists {
+		status = CreateAccountStatus{AccountID: accountID, AccountName: c.account.Name, State: c.state, FailureReason: c.reason}
+	} else if account, exists := m.accounts[accountID]; exists {
+		status = CreateAccountStatus{AccountID: accountID, AccountName: account.Name, State: CreateStateSucceeded}
+	} else {
+		return nil, m.failLocked("DescribeCreateAccountStatus", req, fmt.Errorf("create-account request for %s not found", accountID))
+	}
+
+	m.recordLocked(Operation{Method: "DescribeCreateAccountStatus", Request: req, Result: status},
+		fmt.Sprintf("DescribeCreateAccountStatus(%s) -> %s", accountID, status.State))
+	return &status, nil
+}
+
+// requestCreationLocked starts a simulated create-account request.
+// MUST only be called when m.mu is already held.
+func (m *AWSClient) requestCreationLocked(account *mockAWSAccount) {
+	now := m.clock.Now()
+	duration, ok := m.creationDurations[account.Name]
+	if !ok {
+		duration = m.lifecycle.Duration
+	}
+
+	c := &creation{account: account, done: now.Add(duration), state: CreateStateInProgress}
+	switch {
+	case m.creationFailures[account.Name] != "":
+		c.reason = m.creationFailures[account.Name]
+	case m.emailInUseLocked(account.Email):
+		c.reason = ports.CodeEmailAlreadyExists
+	case m.accountLimit > 0 && len(m.accounts)+m.inProgressLocked() >= m.accountLimit:
+		c.reason = ports.CodeAccountLimitExceeded
+	}
+	m.creations[account.ID] = c
+	m.settleLocked()
+}
+
+// settleLocked finishes the requests whose duration has passed: successful
+// ones become visible accounts in the root.
+// MUST only be called when m.mu is already held.
+func (m *AWSClient) settleLocked() {
+	now := m.clock.Now()
+	for id, c := range m.creations {
+		if c.state != CreateStateInProgress || now.Before(c.done) {
+			continue
+		}
+		if c.reason != "" {
+			c.state = CreateStateFailed
+			continue
+		}
+		c.state = CreateStateSucceeded
+		c.account.CreatedAt = c.done
+		m.accounts[id] = c.account
+		m.accountsByName[c.account.Name] = id
+	}
+}
+
+// emailInUseLocked reports whether an account or a live request uses email.
+// Closed accounts keep their email, as in AWS.
+// MUST only be called when m.mu is already held.
+func (m *AWSClient) emailInUseLocked(email string) bool {
+	if email == "" {
+		return false
+	}
+	for _, account := range m.accounts {
+		if account.Email == email {
+			return true
+		}
+	}
+	for _, c := range m.creations {
+		if c.state == CreateStateInProgress && c.reason == "" && c.account.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+// inProgressLocked counts requests that will become accounts.
+// MUST only be called when m.mu is already held.
+func (m *AWSClient) inProgressLocked() int {
+	n := 0
+	for _, c := range m.creations {
+		if c.state == CreateStateInProgress && c.reason == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// waitForCreation polls DescribeCreateAccountStatus on the mock's clock
+// until the request finishes or ctx is done.
+func (m *AWSClient) waitForCreation(ctx context.Context, req AccountRequest, interval time.Duration) error {
+	m.mu.Lock()
+	clock := m.clock
+	m.mu.Unlock()
+
+	for polls := 1; ; polls++ {
+		status, err := m.DescribeCreateAccountStatus(ctx, req.AccountID)
+		if err != nil {
+			return m.fail("WaitForAccountCreation", req, err)
+		}
+
+		switch status.State {
+		case CreateStateSucceeded:
+			m.record(Operation{Method: "WaitForAccountCreation", Request: req, Result: *status},
+				fmt.Sprintf("WaitForAccountCreation(%s) -> ready after %d polls", req.AccountID, polls))
+			return nil
+		case CreateStateFailed:
+			return m.fail("WaitForAccountCreation", req, ports.NewAWSError(status.FailureReason,
+				fmt.Sprintf("creating account %s failed", status.AccountName)))
+		}
+
+		select {
+		case <-clock.After(interval):
+		case <-ctx.Done():
+			return m.fail("WaitForAccountCreation", req, ctx.Err())
+		}
+	}
+}
+
+// fail records a call that failed with err and returns err.
+// This acquires the lock - use failLocked when already holding the lock.
+func (m *AWSClient) fail(method string, req any, err error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.failLocked(method, req, err)
+}
@@ -0,0 +1,166 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// newLifecycleClient returns a mock with asynchronous creation on a fake clock.
+func newLifecycleClient(c AccountCreation) (*AWSClient, *Clock) {
+	m := NewAWSClient()
+	clock := NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	m.SetClock(clock)
+	m.SimulateAccountCreation(c)
+	return m, clock
+}
+
+func TestSimulatedCreationLifecycle(t *testing.T) {
+	ctx := context.Background()
+	m, clock := newLifecycleClient(AccountCreation{Duration: 2 * time.Minute, PollInterval: 30 * time.Second})
+
+	devID, err := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV", Email: "user+tpa-dev@gmail.com"})
+	if err != nil {
+		t.Fatalf("CreateAccount() failed: %v", err)
+	}
+
+	status, err := m.DescribeCreateAccountStatus(ctx, devID)
+	if err != nil || status.State != CreateStateInProgress {
+		t.Fatalf("status = %+v, %v; want IN_PROGRESS", status, err)
+	}
+	if id, _ := m.GetAccountByName(ctx, "TPA_DEV"); id != "" {
+		t.Errorf("In-progress account should not be visible, got %s", id)
+	}
+	// Asking again while in flight starts a second request, which loses
+	// the email to the first
+	againID, err := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV", Email: "user+tpa-dev@gmail.com"})
+	if err != nil || againID == devID {
+		t.Fatalf("CreateAccount() while in progress = %s, %v; want a new request", againID, err)
+	}
+
+	if err := m.WaitForAccountCreation(ctx, devID); err != nil {
+		t.Fatalf("WaitForAccountCreation() failed: %v", err)
+	}
+
+	// Polled at 0s, 30s, 60s, 90s and 120s
+	if got := m.Operations().ForAccount(devID).Method("DescribeCreateAccountStatus").Count(); got != 6 {
+		t.Errorf("status checks = %d, want 6 (1 direct + 5 polls)", got)
+	}
+	if waits := clock.Waits(); len(waits) != 4 {
+		t.Errorf("waits = %v, want 4 poll intervals", waits)
+	}
+	if id, _ := m.GetAccountByName(ctx, "TPA_DEV"); id != devID {
+		t.Errorf("GetAccountByName() = %s, want %s once created", id, devID)
+	}
+	account, _ := m.DescribeAccount(ctx, devID)
+	if account == nil || account.Status != ports.AccountStatusActive {
+		t.Errorf("DescribeAccount() = %+v, want ACTIVE", account)
+	}
+	if err := m.WaitForAccountCreation(ctx, againID); !errors.Is(err, ports.ErrEmailAlreadyExists) {
+		t.Errorf("WaitForAccountCreation() of the second request = %v, want EMAIL_ALREADY_EXISTS", err)
+	}
+}
+
+func TestSimulatedCreationFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(m *AWSClient)
+		sentinel error
+	}{
+		{
+			name: "email already exists",
+			setup: func(m *AWSClient) {
+				m.CreateAccount(context.Background(), ports.AWSCreateAccountRequest{Name: "OTHER", Email: "user+tpa-dev@gmail.com"})
+			},
+			sentinel: ports.ErrEmailAlreadyExists,
+		},
+		{
+			name:     "account limit exceeded",
+			setup:    func(m *AWSClient) { m.SetAccountLimit(1) },
+			sentinel: ports.ErrAccountLimitExceeded,
+		},
+		{
+			name:     "scripted failure",
+			setup:    func(m *AWSClient) { m.FailAccountCreation("TPA_DEV", ports.CodeAccountLimitExceeded) },
+			sentinel: ports.ErrAccountLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m, _ := newLifecycleClient(AccountCreation{Duration: time.Minute})
+			m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_ROOT", Email: "root@example.com"})
+			tt.setup(m)
+
+			devID, err := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV", Email: "user+tpa-dev@gmail.com"})
+			if err != nil {
+				t.Fatalf("CreateAccount() error = %v; failures surface while waiting", err)
+			}
+			err = m.WaitForAccountCreation(ctx, devID)
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("WaitForAccountCreation() error = %v, want %v", err, tt.sentinel)
+			}
+
+			status, _ := m.DescribeCreateAccountStatus(ctx, devID)
+			if status.State != CreateStateFailed || status.FailureReason != ports.ErrorCode(err) {
+				t.Errorf("status = %+v, want FAILED with %s", status, ports.ErrorCode(err))
+			}
+			if id, _ := m.GetAccountByName(ctx, "TPA_DEV"); id != "" {
+				t.Errorf("Failed account should not exist, got %s", id)
+			}
+		})
+	}
+}
+
+func TestSimulatedCreationDurations(t *testing.T) {
+	ctx := context.Background()
+	m, clock := newLifecycleClient(AccountCreation{Duration: time.Minute, PollInterval: 10 * time.Second})
+	m.SetCreationDuration("TPA_PROD", 0)
+
+	prodID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_PROD"})
+	devID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+
+	if err := m.WaitForAccountCreation(ctx, prodID); err != nil || len(clock.Waits()) != 0 {
+		t.Errorf("prod: error = %v, waits = %v; want ready without polling", err, clock.Waits())
+	}
+
+	// Time passing elsewhere finishes dev without a wait
+	clock.Advance(time.Minute)
+	accounts, _ := m.ListAccounts(ctx)
+	if len(accounts) != 2 {
+		t.Errorf("ListAccounts() = %v, want both accounts", accounts)
+	}
+	if err := m.WaitForAccountCreation(ctx, devID); err != nil || len(clock.Waits()) != 0 {
+		t.Errorf("dev: error = %v, waits = %v; want ready without polling", err, clock.Waits())
+	}
+}
+
+func TestSimulatedCreationHonoursContext(t *testing.T) {
+	m := NewAWSClient()
+	m.SimulateAccountCreation(AccountCreation{Duration: time.Hour, PollInterval: time.Minute})
+	devID, _ := m.CreateAccount(context.Background(), ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.WaitForAccountCreation(ctx, devID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForAccountCreation() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestSimulatedCreationPollsThroughFaults(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newLifecycleClient(AccountCreation{Duration: time.Minute, PollInterval: 20 * time.Second})
+	m.AddFault("DescribeCreateAccountStatus", FailNth(3, ThrottlingError()))
+
+	devID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	if err := m.WaitForAccountCreation(ctx, devID); !errors.Is(err, ports.ErrThrottled) {
+		t.Fatalf("WaitForAccountCreation() error = %v, want ErrThrottled", err)
+	}
+	if err := m.WaitForAccountCreation(ctx, devID); err != nil {
+		t.Errorf("WaitForAccountCreation() retry error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// ObjectStore is an in-process stand-in for an S3 bucket, implementing
+// ports.ObjectStore.
+//
+// It honours conditional writes the way S3 does, so several stores (or
+// goroutines) sharing one ObjectStore behave like laptops and CI sharing
+// a bucket. ETags are sequence numbers, unique across the store.
+type ObjectStore struct {
+	mu      sync.Mutex
+	objects map[string]ports.Object
+	writes  int
+}
+
+// NewObjectStore creates an empty object store.
+func NewObjectStore() *ObjectStore {
+	return &ObjectStore{
+		objects: make(map[string]ports.Object),
+	}
+}
+
+// GetObject returns a copy of the object, or nil if it doesn't exist.
+func (s *ObjectStore) GetObject(ctx context.Context, key string) (*ports.Object, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, exists := s.objects[key]
+	if !exists {
+		return nil, nil
+	}
+	object.Data = slices.Clone(object.Data)
+	return &object, nil
+}
+
+// PutObject stores a copy of data if cond holds.
+func (s *ObjectStore) PutObject(ctx context.Context, key string, data []byte, cond ports.WriteCondition) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkLocked(key, cond); err != nil {
+		return "", err
+	}
+	s.writes++
+	etag := fmt.Sprintf(`"%d"`, s.writes)
+	s.objects[key] = ports.Object{Data: slices.Clone(data), ETag: etag}
+	return etag, nil
+}
+
+// DeleteObject removes the object if cond holds.
+func (s *ObjectStore) DeleteObject(ctx context.Context, key string, cond ports.WriteCondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkLocked(key, cond); err != nil {
+		return err
+	}
+	delete(s.objects, key)
+	return nil
+}
+
+// Keys returns the stored keys in order (test helper, not part of the port).
+func (s *ObjectStore) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// checkLocked evaluates a write condition. Caller must hold s.mu.
+func (s *ObjectStore) checkLocked(key string, cond ports.WriteCondition) error {
+	object, exists := s.objects[key]
+	if cond.IfNoneMatch && exists {
+		return fmt.Errorf("%w: %s already exists", ports.ErrPreconditionFailed, key)
+	}
+	if cond.IfMatch != "" && (!exists || object.ETag != cond.IfMatch) {
+		return fmt.Errorf("%w: %s is not at ETag %s", ports.ErrPreconditionFailed, key, cond.IfMatch)
+	}
+	return nil
+}
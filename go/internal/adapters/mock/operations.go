@@ -0,0 +1,231 @@
+package mock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Operation records one call to the mock AWSClient.
+//
+// Request holds the call's arguments: the port's own request struct where
+// it has one (ports.AWSCreateAccountRequest, ports.AWSCreateRoleRequest,
+// ...), otherwise one of the request types below. Result holds the
+// method's return value (account ID, ARN, *ports.AWSAccount, ...), or nil
+// for methods that only return an error.
+type Operation struct {
+	Seq       int       // 1-based position in the log
+	Time      time.Time // When the call happened
+	Method    string    // Port method name, e.g. "CreateAccount"
+	AccountID string    // Account the call applied to (for CreateAccount: the new account), if any
+	Request   any
+	Result    any
+	Err       error // Injected or simulated failure
+
+	text string
+}
+
+// String returns the human-readable form GetOperations reports, e.g.
+// "CreateAccount(TPA_DEV, ...) -> 100000000001". Meant for debugging;
+// assert on the typed fields instead.
+func (o Operation) String() string {
+	return o.text
+}
+
+// Request types for methods without a port request struct.
+type (
+	// AccountRequest is the argument of methods that take only an account ID.
+	AccountRequest struct {
+		AccountID string
+	}
+
+	// NameRequest is the argument of GetAccountByName.
+	NameRequest struct {
+		Name string
+	}
+
+	// TagRequest is the argument of TagAccount.
+	TagRequest struct {
+		AccountID string
+		Tags      map[string]string
+	}
+
+	// MoveRequest is the argument of MoveAccount.
+	MoveRequest struct {
+		AccountID           string
+		SourceParentID      string
+		DestinationParentID string
+	}
+
+	// ResourceRequest names a resource inside an account (role, budget,
+	// alarm or topic name).
+	ResourceRequest struct {
+		AccountID string
+		Name      string
+	}
+
+	// CDKRequest is the argument of BootstrapCDK and IsCDKBootstrapped.
+	CDKRequest struct {
+		AccountID      string
+		Region         string
+		TrustAccountID string // Empty for IsCDKBootstrapped
+	}
+
+	// TopicRequest is the argument of SNS topic methods.
+	TopicRequest struct {
+		TopicARN string
+		Email    string // Empty for DeleteSNSTopic
+	}
+
+	// AssumeRoleRequest is the argument of AssumeRole.
+	AssumeRoleRequest struct {
+		RoleARN     string
+		SessionName string
+	}
+
+	// OURequest is the argument of CreateOrganizationalUnit.
+	OURequest struct {
+		OUID     string
+		ParentID string
+	}
+)
+
+// accountOf extracts the account a request applies to.
+func accountOf(req any) string {
+	switch r := req.(type) {
+	case AccountRequest:
+		return r.AccountID
+	case TagRequest:
+		return r.AccountID
+	case MoveRequest:
+		return r.AccountID
+	case ResourceRequest:
+		return r.AccountID
+	case CDKRequest:
+		return r.AccountID
+	case TopicRequest:
+		return arnAccount(r.TopicARN)
+	case AssumeRoleRequest:
+		return arnAccount(r.RoleARN)
+	case ports.AWSCreateRoleRequest:
+		return r.AccountID
+	case ports.AWSCreateBudgetRequest:
+		return r.AccountID
+	case ports.AWSCreateBillingAlarmRequest:
+		return r.AccountID
+	}
+	return ""
+}
+
+// arnAccount returns the account field of an ARN ("arn:aws:sns:region:ACCOUNT:name").
+func arnAccount(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
+
+// OperationLog is a sequence of recorded operations with query helpers.
+//
+// Usage:
+//
+//	ops := mockAWS.Operations()
+//	ops.Method("CreateAccount").Count()             // accounts requested
+//	ops.ForAccount(devID).Methods()                 // everything done to dev
+//	ops.Since(mark).Failed()                        // failures after a checkpoint
+//	ops.InOrder("CreateAccount", "MoveAccount")     // nil if they happened in that order
+type OperationLog []Operation
+
+// Method returns the operations of the given methods.
+func (l OperationLog) Method(methods ...string) OperationLog {
+	return l.Where(func(op Operation) bool {
+		for _, m := range methods {
+			if op.Method == m {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ForAccount returns the operations that applied to accountID.
+func (l OperationLog) ForAccount(accountID string) OperationLog {
+	return l.Where(func(op Operation) bool { return op.AccountID == accountID })
+}
+
+// Failed returns the operations that returned an error.
+func (l OperationLog) Failed() OperationLog {
+	return l.Where(func(op Operation) bool { return op.Err != nil })
+}
+
+// Since returns the operations recorded after the one numbered seq (see
+// AWSClient.Mark).
+func (l OperationLog) Since(seq int) OperationLog {
+	return l.Where(func(op Operation) bool { return op.Seq > seq })
+}
+
+// Where returns the operations matching keep.
+func (l OperationLog) Where(keep func(Operation) bool) OperationLog {
+	var kept OperationLog
+	for _, op := range l {
+		if keep(op) {
+			kept = append(kept, op)
+		}
+	}
+	return kept
+}
+
+// Count returns the number of operations.
+func (l OperationLog) Count() int {
+	return len(l)
+}
+
+// Methods returns the method names in order.
+func (l OperationLog) Methods() []string {
+	methods := make([]string, len(l))
+	for i, op := range l {
+		methods[i] = op.Method
+	}
+	return methods
+}
+
+// Last returns the most recent operation, if any.
+func (l OperationLog) Last() (Operation, bool) {
+	if len(l) == 0 {
+		return Operation{}, false
+	}
+	return l[len(l)-1], true
+}
+
+// InOrder checks that calls to methods happened in the given order, with
+// any other calls in between. Each name matches the first call to it after
+// the previous match.
+//
+// Returns:
+//   - nil if the order holds
+//   - Error showing the expected order and the actual calls
+func (l OperationLog) InOrder(methods ...string) error {
+	i := 0
+	for _, method := range methods {
+		for i < len(l) && l[i].Method != method {
+			i++
+		}
+		if i == len(l) {
+			return fmt.Errorf("expected %s, got %v", strings.Join(methods, " -> "), l.Methods())
+		}
+		i++
+	}
+	return nil
+}
+
+// Strings returns the human-readable form of each operation.
+func (l OperationLog) Strings() []string {
+	texts := make([]string, len(l))
+	for i, op := range l {
+		texts[i] = op.text
+	}
+	return texts
+}
@@ -0,0 +1,109 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func TestOperationsAreTyped(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+
+	req := ports.AWSCreateAccountRequest{Name: "TPA_DEV", Email: "user+tpa-dev@gmail.com"}
+	devID, err := m.CreateAccount(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateAccount() failed: %v", err)
+	}
+	if err := m.TagAccount(ctx, devID, map[string]string{"Project": "TPA"}); err != nil {
+		t.Fatalf("TagAccount() failed: %v", err)
+	}
+	if err := m.WaitForAccountCreation(ctx, "000000000000"); err == nil {
+		t.Fatal("WaitForAccountCreation() on an unknown account should fail")
+	}
+
+	ops := m.Operations()
+	if ops.Count() != 3 {
+		t.Fatalf("Expected 3 operations, got %v", ops.Strings())
+	}
+
+	create := ops[0]
+	if create.Seq != 1 || create.Time.IsZero() || create.Method != "CreateAccount" {
+		t.Errorf("create = %+v", create)
+	}
+	if create.Request != req || create.Result != devID || create.AccountID != devID || create.Err != nil {
+		t.Errorf("create request/result = %+v", create)
+	}
+
+	tag, ok := ops[1].Request.(TagRequest)
+	if !ok || tag.AccountID != devID || tag.Tags["Project"] != "TPA" {
+		t.Errorf("tag request = %#v", ops[1].Request)
+	}
+
+	failed := ops.Failed()
+	if failed.Count() != 1 || failed[0].Method != "WaitForAccountCreation" || failed[0].AccountID != "000000000000" {
+		t.Errorf("Failed() = %v", failed.Strings())
+	}
+
+	// The string view is unchanged
+	if got := m.GetOperations()[1]; got != "TagAccount("+devID+", Project=TPA)" {
+		t.Errorf("GetOperations()[1] = %q", got)
+	}
+}
+
+func TestInjectedErrorsAreRecorded(t *testing.T) {
+	m := NewAWSClient()
+	busy := ports.NewAWSError(ports.CodeConcurrentModification, "busy")
+	m.InjectError("GetRole", busy)
+
+	if _, err := m.GetRole(context.Background(), "100000000001", "GitHubActionsRole"); !errors.Is(err, busy) {
+		t.Fatalf("GetRole() error = %v, want injected", err)
+	}
+
+	last, ok := m.Operations().Last()
+	if !ok || last.Err != busy || last.AccountID != "100000000001" {
+		t.Errorf("last = %+v, want the injected failure", last)
+	}
+	if last.Request != (ResourceRequest{AccountID: "100000000001", Name: "GitHubActionsRole"}) {
+		t.Errorf("last.Request = %#v", last.Request)
+	}
+}
+
+func TestOperationLogQueries(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+
+	devID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	mark := m.Mark()
+	prodID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_PROD"})
+	_ = m.WaitForAccountCreation(ctx, prodID)
+	_ = m.CreateOIDCProviderForGitHub(ctx, devID)
+	topicARN, _ := m.CreateSNSTopic(ctx, prodID, "billing-alerts")
+	_ = m.SubscribeEmailToSNSTopic(ctx, topicARN, "user@example.com")
+
+	ops := m.Operations()
+	if got := ops.Method("CreateAccount").Count(); got != 2 {
+		t.Errorf("CreateAccount count = %d, want 2", got)
+	}
+	if got := ops.ForAccount(devID).Methods(); len(got) != 2 || got[1] != "CreateOIDCProviderForGitHub" {
+		t.Errorf("dev operations = %v", got)
+	}
+	if got := ops.ForAccount(prodID).Count(); got != 4 {
+		t.Errorf("prod operations = %v, want create, wait and both topic calls", ops.ForAccount(prodID).Strings())
+	}
+	if got := ops.Since(mark); got.Count() != 5 || got[0].AccountID != prodID {
+		t.Errorf("Since(mark) = %v", got.Strings())
+	}
+
+	if err := ops.InOrder("CreateAccount", "WaitForAccountCreation", "SubscribeEmailToSNSTopic"); err != nil {
+		t.Errorf("InOrder() = %v", err)
+	}
+	if err := ops.InOrder("CreateSNSTopic", "CreateOIDCProviderForGitHub"); err == nil {
+		t.Error("InOrder() should reject out-of-order calls")
+	}
+	if err := ops.InOrder("DeleteRole"); err == nil {
+		t.Error("InOrder() should reject a method that never ran")
+	}
+}
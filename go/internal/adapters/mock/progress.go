@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"sync"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// ProgressRecorder is a ports.ProgressReporter that keeps every event, so
+// tests can assert on what orchestration reported.
+type ProgressRecorder struct {
+	mu     sync.Mutex
+	events []ports.ProgressEvent
+}
+
+// NewProgressRecorder creates an empty recorder.
+func NewProgressRecorder() *ProgressRecorder {
+	return &ProgressRecorder{}
+}
+
+// Report records the event.
+func (r *ProgressRecorder) Report(event ports.ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// Events returns every recorded event in order.
+func (r *ProgressRecorder) Events() []ports.ProgressEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]ports.ProgressEvent, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// EventsFor returns the events of one environment in order.
+func (r *ProgressRecorder) EventsFor(env string) []ports.ProgressEvent {
+	var events []ports.ProgressEvent
+	for _, event := range r.Events() {
+		if event.Environment == env {
+			events = append(events, event)
+		}
+	}
+	return events
+}
@@ -0,0 +1,361 @@
+package mock
+
+import (
+	"bytes"
+	"cmp"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// SnapshotVersion is the fixture format Snapshot writes and Restore reads.
+const SnapshotVersion = 1
+
+// Snapshot is the mock's organization and the resources inside it, in a
+// form that can be checked in as a JSON fixture:
+//
+//	{
+//	  "version": 1,
+//	  "organizationalUnits": [{"id": "ou-813y-8teevv2l", "parentId": "r-mock"}],
+//	  "accounts": [{"id": "100000000001", "name": "TPA_DEV", "email": "user+tpa-dev@gmail.com", "parentId": "ou-813y-8teevv2l"}],
+//	  "oidcProviders": ["100000000001"],
+//	  "budgets": [{"accountId": "100000000001", "budgetName": "TPA-dev-monthly-budget", "limitAmount": 25, ...}]
+//	}
+//
+// Omitted fields take the defaults the mock itself would use: accounts are
+// ACTIVE and sit in the root. Only state is captured - the operation log,
+// faults, quotas and in-flight creations (see SimulateAccountCreation) are
+// not. Lists are sorted so fixtures diff cleanly.
+type Snapshot struct {
+	Version             int               `json:"version"`
+	OrganizationalUnits []SnapshotOU      `json:"organizationalUnits,omitempty"`
+	Accounts            []SnapshotAccount `json:"accounts,omitempty"`
+	OIDCProviders       []string          `json:"oidcProviders,omitempty"` // Account IDs with the GitHub provider
+	Roles               []SnapshotRole    `json:"roles,omitempty"`
+	CDKBootstraps       []SnapshotCDK     `json:"cdkBootstraps,omitempty"`
+	Budgets             []SnapshotBudget  `json:"budgets,omitempty"`
+	BillingAlarms       []SnapshotAlarm   `json:"billingAlarms,omitempty"`
+	Topics              []SnapshotTopic   `json:"topics,omitempty"`
+}
+
+// SnapshotOU is an organizational unit.
+type SnapshotOU struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parentId"`
+}
+
+// SnapshotAccount is an account in the organization.
+type SnapshotAccount struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Email     string            `json:"email,omitempty"`
+	ParentID  string            `json:"parentId,omitempty"` // Default: RootID
+	Status    string            `json:"status,omitempty"`   // Default: ports.AccountStatusActive
+	Tags      map[string]string `json:"tags,omitempty"`
+	CreatedAt time.Time         `json:"createdAt,omitzero"`
+}
+
+// SnapshotRole is a GitHub Actions deploy role.
+type SnapshotRole struct {
+	AccountID        string   `json:"accountId"`
+	RoleName         string   `json:"roleName"`
+	GitHubOrg        string   `json:"gitHubOrg,omitempty"`
+	GitHubRepo       string   `json:"gitHubRepo,omitempty"`
+	PolicyARNs       []string `json:"policyArns,omitempty"`
+	AllowAllBranches bool     `json:"allowAllBranches,omitempty"`
+}
+
+// SnapshotCDK is a CDK bootstrap in one region.
+type SnapshotCDK struct {
+	AccountID      string `json:"accountId"`
+	Region         string `json:"region"`
+	TrustAccountID string `json:"trustAccountId,omitempty"`
+}
+
+// SnapshotBudget is a monthly budget.
+type SnapshotBudget struct {
+	AccountID     string  `json:"accountId"`
+	BudgetName    string  `json:"budgetName"`
+	LimitAmount   float64 `json:"limitAmount"`
+	AlertAmount   float64 `json:"alertAmount"`
+	Email         string  `json:"email,omitempty"`
+	AlertPercents []int   `json:"alertPercents,omitempty"`
+}
+
+// SnapshotAlarm is a CloudWatch billing alarm.
+type SnapshotAlarm struct {
+	AccountID string  `json:"accountId"`
+	AlarmName string  `json:"alarmName"`
+	Threshold float64 `json:"threshold"`
+	TopicARN  string  `json:"topicArn,omitempty"`
+}
+
+// SnapshotTopic is an SNS topic and its email subscriptions.
+type SnapshotTopic struct {
+	ARN           string   `json:"arn"`
+	Subscriptions []string `json:"subscriptions,omitempty"`
+}
+
+// Snapshot captures the mock's current state.
+func (m *AWSClient) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settleLocked()
+
+	s := Snapshot{Version: SnapshotVersion}
+	for _, id := range slices.Sorted(maps.Keys(m.ous)) {
+		s.OrganizationalUnits = append(s.OrganizationalUnits, SnapshotOU{ID: id, ParentID: m.ous[id]})
+	}
+	for _, id := range slices.Sorted(maps.Keys(m.accounts)) {
+		a := m.accounts[id]
+		s.Accounts = append(s.Accounts, SnapshotAccount{
+			ID:        a.ID,
+			Name:      a.Name,
+			Email:     a.Email,
+			ParentID:  a.ParentID,
+			Status:    a.Status,
+			Tags:      maps.Clone(a.Tags),
+			CreatedAt: a.CreatedAt.UTC(),
+		})
+	}
+	s.OIDCProviders = slices.Sorted(maps.Keys(m.oidcProviders))
+	for _, key := range slices.Sorted(maps.Keys(m.roles)) {
+		r := m.roles[key]
+		s.Roles = append(s.Roles, SnapshotRole{
+			AccountID:        r.AccountID,
+			RoleName:         r.RoleName,
+			GitHubOrg:        r.GitHubOrg,
+			GitHubRepo:       r.GitHubRepo,
+			PolicyARNs:       slices.Clone(r.PolicyARNs),
+			AllowAllBranches: r.AllowAllBranches,
+		})
+	}
+	for _, key := range slices.Sorted(maps.Keys(m.cdkBootstraps)) {
+		accountID, region, _ := strings.Cut(key, "/")
+		s.CDKBootstraps = append(s.CDKBootstraps, SnapshotCDK{AccountID: accountID, Region: region, TrustAccountID: m.cdkBootstraps[key]})
+	}
+	for _, key := range slices.Sorted(maps.Keys(m.budgets)) {
+		b := m.budgets[key]
+		s.Budgets = append(s.Budgets, SnapshotBudget{
+			AccountID:     b.AccountID,
+			BudgetName:    b.BudgetName,
+			LimitAmount:   b.LimitAmount,
+			AlertAmount:   b.AlertAmount,
+			Email:         b.Email,
+			AlertPercents: slices.Clone(b.AlertPercents),
+		})
+	}
+	for _, key := range slices.Sorted(maps.Keys(m.alarms)) {
+		a := m.alarms[key]
+		s.BillingAlarms = append(s.BillingAlarms, SnapshotAlarm{AccountID: a.AccountID, AlarmName: a.AlarmName, Threshold: a.Threshold, TopicARN: a.TopicARN})
+	}
+	for _, arn := range slices.Sorted(maps.Keys(m.topics)) {
+		s.Topics = append(s.Topics, SnapshotTopic{ARN: arn, Subscriptions: slices.Clone(m.topics[arn])})
+	}
+	return s
+}
+
+// Restore replaces the mock's state with s. The operation log, injected
+// errors and faults are kept. On error the mock is left unchanged.
+//
+// Returns:
+//   - nil if s was loaded
+//   - Error if s has another version, a duplicate account ID or name, a
+//     resource without an account ID or name, or a parent that isn't
+//     RootID or an OU (as in MoveAccount, unknown "ou-" IDs are created
+//     under the root)
+func (m *AWSClient) Restore(s Snapshot) error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported mock snapshot version %d (supported: %d)", s.Version, SnapshotVersion)
+	}
+
+	// Build into a scratch client so a bad snapshot changes nothing
+	r := NewAWSClient()
+	for _, ou := range s.OrganizationalUnits {
+		if !strings.HasPrefix(ou.ID, "ou-") {
+			return fmt.Errorf("organizational unit %q: ID must start with ou-", ou.ID)
+		}
+		if err := r.ensureParentLocked(cmp.Or(ou.ParentID, RootID)); err != nil {
+			return fmt.Errorf("organizational unit %s: %w", ou.ID, err)
+		}
+		r.ous[ou.ID] = cmp.Or(ou.ParentID, RootID)
+	}
+	for _, a := range s.Accounts {
+		if a.ID == "" || a.Name == "" {
+			return fmt.Errorf("account %q (%s): ID and name are required", a.Name, a.ID)
+		}
+		if _, exists := r.accounts[a.ID]; exists {
+			return fmt.Errorf("account %s appears more than once", a.ID)
+		}
+		if _, exists := r.accountsByName[a.Name]; exists {
+			return fmt.Errorf("account name %s appears more than once", a.Name)
+		}
+		parentID := cmp.Or(a.ParentID, RootID)
+		if err := r.ensureParentLocked(parentID); err != nil {
+			return fmt.Errorf("account %s: %w", a.ID, err)
+		}
+		r.accounts[a.ID] = &mockAWSAccount{
+			ID:        a.ID,
+			Name:      a.Name,
+			Email:     a.Email,
+			ParentID:  parentID,
+			Status:    cmp.Or(a.Status, ports.AccountStatusActive),
+			Tags:      maps.Clone(a.Tags),
+			CreatedAt: a.CreatedAt,
+		}
+		r.accountsByName[a.Name] = a.ID
+		if n, err := strconv.ParseInt(a.ID, 10, 64); err == nil && n >= r.nextAccountID {
+			r.nextAccountID = n + 1
+		}
+	}
+	for _, accountID := range s.OIDCProviders {
+		r.oidcProviders[accountID] = true
+	}
+	for _, role := range s.Roles {
+		if role.AccountID == "" || role.RoleName == "" {
+			return fmt.Errorf("role %q: accountId and roleName are required", role.RoleName)
+		}
+		r.roles[resourceKey(role.AccountID, role.RoleName)] = ports.AWSCreateRoleRequest{
+			AccountID:        role.AccountID,
+			RoleName:         role.RoleName,
+			GitHubOrg:        role.GitHubOrg,
+			GitHubRepo:       role.GitHubRepo,
+			PolicyARNs:       slices.Clone(role.PolicyARNs),
+			AllowAllBranches: role.AllowAllBranches,
+		}
+	}
+	for _, cdk := range s.CDKBootstraps {
+		if cdk.AccountID == "" || cdk.Region == "" {
+			return fmt.Errorf("CDK bootstrap %q: accountId and region are required", cdk.Region)
+		}
+		r.cdkBootstraps[resourceKey(cdk.AccountID, cdk.Region)] = cdk.TrustAccountID
+	}
+	for _, b := range s.Budgets {
+		if b.AccountID == "" || b.BudgetName == "" {
+			return fmt.Errorf("budget %q: accountId and budgetName are required", b.BudgetName)
+		}
+		r.budgets[resourceKey(b.AccountID, b.BudgetName)] = ports.AWSBudget{
+			AccountID:     b.AccountID,
+			BudgetName:    b.BudgetName,
+			LimitAmount:   b.LimitAmount,
+			AlertAmount:   b.AlertAmount,
+			Email:         b.Email,
+			AlertPercents: slices.Clone(b.AlertPercents),
+		}
+	}
+	for _, a := range s.BillingAlarms {
+		if a.AccountID == "" || a.AlarmName == "" {
+			return fmt.Errorf("billing alarm %q: accountId and alarmName are required", a.AlarmName)
+		}
+		r.alarms[resourceKey(a.AccountID, a.AlarmName)] = ports.AWSBillingAlarm{
+			AccountID: a.AccountID,
+			AlarmName: a.AlarmName,
+			Threshold: a.Threshold,
+			TopicARN:  a.TopicARN,
+		}
+	}
+	for _, topic := range s.Topics {
+		if arnAccount(topic.ARN) == "" {
+			return fmt.Errorf("topic %q: arn must be an SNS topic ARN", topic.ARN)
+		}
+		r.topics[topic.ARN] = slices.Clone(topic.Subscriptions)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts = r.accounts
+	m.accountsByName = r.accountsByName
+	m.nextAccountID = r.nextAccountID
+	m.ous = r.ous
+	m.oidcProviders = r.oidcProviders
+	m.roles = r.roles
+	m.cdkBootstraps = r.cdkBootstraps
+	m.budgets = r.budgets
+	m.alarms = r.alarms
+	m.topics = r.topics
+	m.creations = r.creations
+	return nil
+}
+
+// ReadSnapshot decodes a JSON snapshot. Unknown fields are rejected, so a
+// typo in a hand-written fixture fails loudly instead of being ignored.
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var s Snapshot
+	if err := dec.Decode(&s); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse mock snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// Write encodes the snapshot as indented JSON, ready to check in.
+func (s Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// LoadFixture creates a mock from the JSON snapshot at path.
+func LoadFixture(path string) (*AWSClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock fixture: %w", err)
+	}
+	return newFromSnapshot(path, data)
+}
+
+// SaveFixture writes the mock's current state to path as a JSON snapshot.
+func (m *AWSClient) SaveFixture(path string) error {
+	var buf bytes.Buffer
+	if err := m.Snapshot().Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write mock fixture: %w", err)
+	}
+	return nil
+}
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fixture creates a mock from one of the scenarios shipped in fixtures/,
+// shared by domain and CLI tests:
+//
+//   - "existing-org": five accounts - TPA_DEV and TPA_STAGING in the
+//     project OU, a hand-made legacy-prod account in the root, and Log
+//     Archive and Shared Services in a shared OU. TPA_DEV has the GitHub
+//     OIDC provider and its monthly budget.
+//
+// Usage:
+//
+//	mockAWS, err := mock.Fixture("existing-org")
+func Fixture(name string) (*AWSClient, error) {
+	data, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown mock fixture %q", name)
+	}
+	return newFromSnapshot(name, data)
+}
+
+func newFromSnapshot(source string, data []byte) (*AWSClient, error) {
+	s, err := ReadSnapshot(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	m := NewAWSClient()
+	if err := m.Restore(s); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return m, nil
+}
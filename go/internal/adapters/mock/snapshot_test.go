@@ -0,0 +1,144 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+	if err := m.CreateOrganizationalUnit("ou-813y-child001", "ou-813y-8teevv2l"); err != nil {
+		t.Fatal(err)
+	}
+	devID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV", Email: "user+tpa-dev@gmail.com"})
+	_ = m.MoveAccount(ctx, devID, RootID, "ou-813y-child001")
+	_ = m.TagAccount(ctx, devID, map[string]string{"Project": "TPA"})
+	oldID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_OLD"})
+	_ = m.CloseAccount(ctx, oldID)
+	_ = m.CreateOIDCProviderForGitHub(ctx, devID)
+	_, _ = m.CreateGitHubActionsRole(ctx, ports.AWSCreateRoleRequest{
+		AccountID: devID, RoleName: "GitHubActionsDeployRole", GitHubOrg: "myorg", GitHubRepo: "myrepo",
+		PolicyARNs: []string{"arn:aws:iam::aws:policy/AdministratorAccess"},
+	})
+	_ = m.BootstrapCDK(ctx, devID, "us-east-1", "999999999999")
+	_ = m.CreateBudget(ctx, ports.AWSCreateBudgetRequest{
+		AccountID: devID, BudgetName: "TPA-dev-monthly-budget", LimitAmount: 25, AlertAmount: 15, AlertPercents: []int{80, 100},
+	})
+	topicARN, _ := m.CreateSNSTopic(ctx, devID, "TPA-dev-billing-alerts")
+	_ = m.SubscribeEmailToSNSTopic(ctx, topicARN, "user@gmail.com")
+	_ = m.CreateBillingAlarm(ctx, ports.AWSCreateBillingAlarmRequest{
+		AccountID: devID, AlarmName: "TPA-dev-billing-alarm", Threshold: 15, TopicARN: topicARN,
+	})
+
+	var buf bytes.Buffer
+	want := m.Snapshot()
+	if err := want.Write(&buf); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	loaded, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() failed: %v", err)
+	}
+	restored := NewAWSClient()
+	if err := restored.Restore(loaded); err != nil {
+		t.Fatalf("Restore() failed: %v", err)
+	}
+	if got := restored.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Round trip changed the state:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	// Restoring doesn't count as operations, and lookups see the state
+	if restored.Operations().Count() != 0 {
+		t.Errorf("Restore() logged operations: %v", restored.GetOperations())
+	}
+	if arn, _ := restored.GetRole(ctx, devID, "GitHubActionsDeployRole"); arn == "" {
+		t.Error("Restored role not found")
+	}
+	if account, _ := restored.DescribeAccount(ctx, oldID); account.Status != ports.AccountStatusSuspended {
+		t.Errorf("Restored closed account status = %s", account.Status)
+	}
+}
+
+func TestFixture(t *testing.T) {
+	ctx := context.Background()
+	m, err := Fixture("existing-org")
+	if err != nil {
+		t.Fatalf("Fixture() failed: %v", err)
+	}
+
+	accounts, _ := m.ListAccounts(ctx)
+	if len(accounts) != 5 {
+		t.Errorf("existing-org has %d accounts, want 5", len(accounts))
+	}
+	if parent, _ := m.GetAccountParent(ctx, "100000000005"); parent != RootID {
+		t.Errorf("legacy-prod parent = %s, want root", parent)
+	}
+
+	// New accounts don't collide with the fixture's IDs
+	id, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_PROD"})
+	if id != "100000000006" {
+		t.Errorf("CreateAccount() = %s, want the next free ID", id)
+	}
+
+	if _, err := Fixture("no-such-scenario"); err == nil {
+		t.Error("Fixture() should reject unknown names")
+	}
+}
+
+func TestSaveAndLoadFixture(t *testing.T) {
+	ctx := context.Background()
+	m := NewAWSClient()
+	devID, _ := m.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	_ = m.CreateOIDCProviderForGitHub(ctx, devID)
+
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	if err := m.SaveFixture(path); err != nil {
+		t.Fatalf("SaveFixture() failed: %v", err)
+	}
+	loaded, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture() failed: %v", err)
+	}
+	if arn, _ := loaded.GetOIDCProviderForGitHub(ctx, devID); arn == "" {
+		t.Error("Loaded fixture lost the OIDC provider")
+	}
+}
+
+func TestRestoreRejectsInvalidSnapshots(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{name: "missing version", json: `{"accounts": []}`, wantErr: "version 0"},
+		{name: "unknown field", json: `{"version": 1, "acounts": []}`, wantErr: "unknown field"},
+		{name: "duplicate name", json: `{"version": 1, "accounts": [{"id": "1", "name": "A"}, {"id": "2", "name": "A"}]}`, wantErr: "more than once"},
+		{name: "bad parent", json: `{"version": 1, "accounts": [{"id": "1", "name": "A", "parentId": "nowhere"}]}`, wantErr: "not found"},
+		{name: "budget without account", json: `{"version": 1, "budgets": [{"budgetName": "b"}]}`, wantErr: "accountId"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAWSClient()
+			m.CreateAccount(context.Background(), ports.AWSCreateAccountRequest{Name: "KEEP"})
+
+			s, err := ReadSnapshot(strings.NewReader(tt.json))
+			if err == nil {
+				err = m.Restore(s)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if id, _ := m.GetAccountByName(context.Background(), "KEEP"); id == "" {
+				t.Error("A rejected snapshot should leave the mock unchanged")
+			}
+		})
+	}
+}
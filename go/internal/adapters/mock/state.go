@@ -0,0 +1,55 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// StateStore is an in-memory implementation of ports.StateStore for testing.
+//
+// States are stored in their JSON encoding, so a test exercises the same
+// schema the local file uses and can't mutate what was saved.
+type StateStore struct {
+	mu     sync.Mutex
+	states map[string][]byte
+}
+
+// NewStateStore creates an empty in-memory state store.
+func NewStateStore() *StateStore {
+	return &StateStore{
+		states: make(map[string][]byte),
+	}
+}
+
+// LoadState returns a copy of the stored state, or nil if none exists.
+func (s *StateStore) LoadState(ctx context.Context, projectCode string) (*ports.ProjectState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, exists := s.states[projectCode]
+	if !exists {
+		return nil, nil
+	}
+
+	var state ports.ProjectState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SaveState stores a copy of the state.
+func (s *StateStore) SaveState(ctx context.Context, state *ports.ProjectState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.ProjectCode] = data
+	return nil
+}
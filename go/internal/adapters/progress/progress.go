@@ -0,0 +1,216 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Terminal renders progress events as one line each, for a CLI:
+//
+//	[dev] TPA_DEV: create started
+//	[dev] TPA_DEV (100000000001): wait still waiting (45s)
+//	[dev] TPA_DEV (100000000001): wait done (2m10s)
+//	[staging] TPA_STAGING (100000000002): create reused
+type Terminal struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewTerminal creates a renderer writing to w (typically os.Stderr).
+func NewTerminal(w io.Writer) *Terminal {
+	return &Terminal{w: w}
+}
+
+// Report writes one line for the event. Write errors are ignored: losing
+// a progress line must not fail the run.
+func (t *Terminal) Report(event ports.ProgressEvent) {
+	subject := event.AccountName
+	if event.AccountID != "" {
+		subject += " (" + event.AccountID + ")"
+	}
+
+	var status string
+	switch event.Type {
+	case ports.EventStepStarted:
+		status = "started"
+	case ports.EventPolling:
+		status = fmt.Sprintf("still waiting (%s)", roundDuration(event.Duration))
+	case ports.EventStepSucceeded:
+		status = fmt.Sprintf("done (%s)", roundDuration(event.Duration))
+	case ports.EventStepFailed:
+		status = fmt.Sprintf("FAILED after %s: %v", roundDuration(event.Duration), event.Err)
+	case ports.EventReused:
+		status = "reused"
+	default:
+		status = string(event.Type)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.w, "[%s] %s: %s %s\n", event.Environment, subject, event.Step, status)
+}
+
+// roundDuration keeps terminal output readable (2m10s, not 2m10.004512s).
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Second)
+}
+
+// JSONLines writes each event as one line of JSON (NDJSON), for log
+// collectors and for tools driving the CLI:
+//
+//	{"type":"step-succeeded","time":"...","environment":"dev","step":"wait","accountName":"TPA_DEV","accountId":"100000000001","durationMs":130000}
+type JSONLines struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewJSONLines creates a reporter writing to w.
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{enc: json.NewEncoder(w)}
+}
+
+// jsonEvent is the wire form of ports.ProgressEvent.
+type jsonEvent struct {
+	Type        ports.ProgressEventType `json:"type"`
+	Time        time.Time               `json:"time"`
+	Environment string                  `json:"environment"`
+	Step        string                  `json:"step"`
+	AccountName string                  `json:"accountName,omitempty"`
+	AccountID   string                  `json:"accountId,omitempty"`
+	DurationMs  int64                   `json:"durationMs,omitempty"`
+	Error       string                  `json:"error,omitempty"`
+}
+
+// Report writes the event. The first write error is kept (see Err) and
+// later events are dropped.
+func (j *JSONLines) Report(event ports.ProgressEvent) {
+	line := jsonEvent{
+		Type:        event.Type,
+		Time:        event.Time.UTC(),
+		Environment: event.Environment,
+		Step:        event.Step,
+		AccountName: event.AccountName,
+		AccountID:   event.AccountID,
+		DurationMs:  event.Duration.Milliseconds(),
+	}
+	if event.Err != nil {
+		line.Error = event.Err.Error()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.err == nil {
+		j.err = j.enc.Encode(line)
+	}
+}
+
+// Err returns the first write error, if any.
+func (j *JSONLines) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Broadcaster fans events out to channel subscribers, for servers that
+// stream progress to clients (e.g., over server-sent events).
+//
+// Report never blocks: a subscriber whose buffer is full misses the event,
+// and Dropped counts how many it missed. Orchestration must not stall
+// because a browser tab stopped reading.
+type Broadcaster struct {
+	mu     sync.Mutex
+	subs   map[*subscriber]struct{}
+	closed bool
+}
+
+type subscriber struct {
+	ch      chan ports.ProgressEvent
+	dropped int
+}
+
+// NewBroadcaster creates a broadcaster with no subscribers.
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subs: make(map[*subscriber]struct{})}
+}
+
+// Subscription is one subscriber's view of a Broadcaster.
+type Subscription struct {
+	b   *Broadcaster
+	sub *subscriber
+}
+
+// Subscribe registers a subscriber with room for buffer pending events.
+// Events reported before Subscribe are not replayed. On a closed
+// broadcaster the channel is already closed.
+func (b *Broadcaster) Subscribe(buffer int) *Subscription {
+	sub := &subscriber{ch: make(chan ports.ProgressEvent, buffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub.ch)
+	} else {
+		b.subs[sub] = struct{}{}
+	}
+	return &Subscription{b: b, sub: sub}
+}
+
+// Events returns the channel events arrive on. It is closed by Cancel or
+// when the broadcaster closes.
+func (s *Subscription) Events() <-chan ports.ProgressEvent {
+	return s.sub.ch
+}
+
+// Dropped returns how many events didn't fit in the buffer.
+func (s *Subscription) Dropped() int {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	return s.sub.dropped
+}
+
+// Cancel unsubscribes and closes the channel. Safe to call more than once.
+func (s *Subscription) Cancel() {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if _, ok := s.b.subs[s.sub]; ok {
+		delete(s.b.subs, s.sub)
+		close(s.sub.ch)
+	}
+}
+
+// Report delivers the event to every subscriber with room for it.
+func (b *Broadcaster) Report(event ports.ProgressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			sub.dropped++
+		}
+	}
+}
+
+// Close closes every subscriber's channel, typically once orchestration
+// returns. Later events are discarded.
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subs {
+		close(sub.ch)
+		delete(b.subs, sub)
+	}
+}
@@ -0,0 +1,96 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+var events = []ports.ProgressEvent{
+	{Type: ports.EventStepStarted, Environment: "dev", Step: "create", AccountName: "TPA_DEV"},
+	{Type: ports.EventPolling, Environment: "dev", Step: "wait", AccountName: "TPA_DEV", AccountID: "100000000001", Duration: 45 * time.Second},
+	{Type: ports.EventStepSucceeded, Environment: "dev", Step: "wait", AccountName: "TPA_DEV", AccountID: "100000000001", Duration: 130*time.Second + 4*time.Millisecond},
+	{Type: ports.EventStepFailed, Environment: "prod", Step: "tag", AccountName: "TPA_PROD", Duration: 250 * time.Millisecond, Err: errors.New("AccessDenied")},
+	{Type: ports.EventReused, Environment: "staging", Step: "create", AccountName: "TPA_STAGING", AccountID: "100000000002"},
+}
+
+func TestTerminal(t *testing.T) {
+	var out bytes.Buffer
+	terminal := NewTerminal(&out)
+	for _, e := range events {
+		terminal.Report(e)
+	}
+
+	want := `[dev] TPA_DEV: create started
+[dev] TPA_DEV (100000000001): wait still waiting (45s)
+[dev] TPA_DEV (100000000001): wait done (2m10s)
+[prod] TPA_PROD: tag FAILED after 250ms: AccessDenied
+[staging] TPA_STAGING (100000000002): create reused
+`
+	if out.String() != want {
+		t.Errorf("Terminal output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	reporter := NewJSONLines(&out)
+	for _, e := range events {
+		reporter.Report(e)
+	}
+	if err := reporter.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(events) {
+		t.Fatalf("Expected %d lines, got %d", len(events), len(lines))
+	}
+	var failed map[string]any
+	if err := json.Unmarshal([]byte(lines[3]), &failed); err != nil {
+		t.Fatalf("Line is not JSON: %v", err)
+	}
+	if failed["type"] != "step-failed" || failed["error"] != "AccessDenied" || failed["durationMs"] != float64(250) {
+		t.Errorf("failed event = %v", failed)
+	}
+	if strings.Contains(lines[0], "accountId") || strings.Contains(lines[0], "durationMs") {
+		t.Errorf("started event should omit empty fields: %s", lines[0])
+	}
+}
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster()
+	fast := b.Subscribe(len(events))
+	slow := b.Subscribe(1)
+	gone := b.Subscribe(1)
+	gone.Cancel()
+	gone.Cancel()
+
+	for _, e := range events {
+		b.Report(e)
+	}
+	b.Close()
+
+	var received []ports.ProgressEvent
+	for e := range fast.Events() {
+		received = append(received, e)
+	}
+	if len(received) != len(events) || fast.Dropped() != 0 {
+		t.Errorf("fast subscriber got %d events, dropped %d; want all", len(received), fast.Dropped())
+	}
+
+	// A full buffer drops events instead of blocking Report
+	if got := len(slow.Events()); got != 1 || slow.Dropped() != len(events)-1 {
+		t.Errorf("slow subscriber buffered %d, dropped %d", got, slow.Dropped())
+	}
+
+	if _, open := <-b.Subscribe(1).Events(); open {
+		t.Error("Subscribing after Close should yield a closed channel")
+	}
+	slow.Cancel() // Harmless after Close
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// bucket is a token bucket shared by every goroutine calling one service.
+type bucket struct {
+	mu     sync.Mutex
+	clock  ports.Clock
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(limit Limit, clock ports.Clock) *bucket {
+	burst := float64(max(limit.Burst, 1))
+	return &bucket{
+		clock:  clock,
+		rate:   limit.RatePerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   clock.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *bucket) wait(ctx context.Context) error {
+	for {
+		delay := b.take()
+		if delay == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.clock.After(delay):
+		}
+	}
+}
+
+// take consumes a token if one is available and returns 0, otherwise it
+// returns how long until the next token is due.
+func (b *bucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return max(time.Duration((1-b.tokens)/b.rate*float64(time.Second)), time.Nanosecond)
+}
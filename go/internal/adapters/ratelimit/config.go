@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Service identifies an AWS service with its own API rate limits.
+type Service string
+
+const (
+	ServiceOrganizations  Service = "organizations"
+	ServiceIAM            Service = "iam"
+	ServiceSTS            Service = "sts"
+	ServiceBudgets        Service = "budgets"
+	ServiceCloudWatch     Service = "cloudwatch"
+	ServiceSNS            Service = "sns"
+	ServiceCloudFormation Service = "cloudformation" // CDK bootstrap stacks
+)
+
+// AllServices returns every service the limiter knows about.
+func AllServices() []Service {
+	return []Service{
+		ServiceOrganizations,
+		ServiceIAM,
+		ServiceSTS,
+		ServiceBudgets,
+		ServiceCloudWatch,
+		ServiceSNS,
+		ServiceCloudFormation,
+	}
+}
+
+// Limit is a token bucket: Burst calls can go through at once, after which
+// calls are spaced to RatePerSecond. A RatePerSecond of 0 means unlimited.
+type Limit struct {
+	RatePerSecond float64 `json:"ratePerSecond"`
+	Burst         int     `json:"burst"`
+}
+
+// Config holds the limit for each service. Services without an entry are
+// not limited.
+//
+// JSON form:
+//
+//	{
+//	  "organizations": {"ratePerSecond": 1, "burst": 2},
+//	  "iam": {"ratePerSecond": 10, "burst": 10}
+//	}
+type Config map[Service]Limit
+
+// DefaultConfig returns conservative limits that stay under the AWS quotas
+// for a single caller.
+//
+// Organizations is by far the strictest: CreateAccount and the
+// describe/list APIs are throttled at a handful of calls per second across
+// the whole organization.
+func DefaultConfig() Config {
+	return Config{
+		ServiceOrganizations:  {RatePerSecond: 1, Burst: 2},
+		ServiceIAM:            {RatePerSecond: 10, Burst: 10},
+		ServiceSTS:            {RatePerSecond: 10, Burst: 10},
+		ServiceBudgets:        {RatePerSecond: 2, Burst: 2},
+		ServiceCloudWatch:     {RatePerSecond: 5, Burst: 5},
+		ServiceSNS:            {RatePerSecond: 10, Burst: 10},
+		ServiceCloudFormation: {RatePerSecond: 2, Burst: 2},
+	}
+}
+
+// ParseConfig reads a JSON config and layers it over DefaultConfig, so a
+// file only needs to mention the services it changes.
+func ParseConfig(data []byte) (Config, error) {
+	var overrides Config
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit config: %w", err)
+	}
+	if err := overrides.Validate(); err != nil {
+		return nil, err
+	}
+
+	config := DefaultConfig()
+	for service, limit := range overrides {
+		config[service] = limit
+	}
+	return config, nil
+}
+
+// Validate checks that every service is known and every limit is non-negative.
+func (c Config) Validate() error {
+	known := make(map[Service]bool)
+	for _, s := range AllServices() {
+		known[s] = true
+	}
+
+	for service, limit := range c {
+		if !known[service] {
+			return fmt.Errorf("unknown service %q in rate limit config (valid: %v)", service, AllServices())
+		}
+		if limit.RatePerSecond < 0 {
+			return fmt.Errorf("%s: ratePerSecond must not be negative, got %v", service, limit.RatePerSecond)
+		}
+		if limit.Burst < 0 {
+			return fmt.Errorf("%s: burst must not be negative, got %d", service, limit.Burst)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Client is a ports.AWSClient decorator that throttles calls per AWS service.
+//
+// Each service gets one token bucket shared by every goroutine using the
+// client, so parallel provisioning stays under the account-wide AWS limits
+// instead of tripping them and relying on retries.
+//
+// Put the limiter inside the retry decorator so retried calls also wait
+// for a token:
+//
+//	client := retry.New(ratelimit.New(awsClient, ratelimit.DefaultConfig()))
+type Client struct {
+	next    ports.AWSClient
+	clock   ports.Clock
+	buckets map[Service]*bucket
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithClock sets the clock used to refill buckets and wait for tokens.
+// Tests pass a mock.Clock so waiting doesn't actually sleep.
+func WithClock(clock ports.Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// New wraps next with the limits in config.
+func New(next ports.AWSClient, config Config, opts ...Option) *Client {
+	c := &Client{
+		next:    next,
+		clock:   ports.SystemClock(),
+		buckets: make(map[Service]*bucket),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for service, limit := range config {
+		if limit.RatePerSecond > 0 {
+			c.buckets[service] = newBucket(limit, c.clock)
+		}
+	}
+	return c
+}
+
+// Name returns the wrapped client's name.
+func (c *Client) Name() string {
+	return c.next.Name()
+}
+
+// wait blocks until service has capacity. Unlimited services return at once.
+func (c *Client) wait(ctx context.Context, service Service) error {
+	b, ok := c.buckets[service]
+	if !ok {
+		return ctx.Err()
+	}
+	return b.wait(ctx)
+}
+
+func (c *Client) CreateAccount(ctx context.Context, req ports.AWSCreateAccountRequest) (string, error) {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return "", err
+	}
+	return c.next.CreateAccount(ctx, req)
+}
+
+func (c *Client) WaitForAccountCreation(ctx context.Context, accountID string) error {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return err
+	}
+	return c.next.WaitForAccountCreation(ctx, accountID)
+}
+
+func (c *Client) GetAccountByName(ctx context.Context, name string) (string, error) {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return "", err
+	}
+	return c.next.GetAccountByName(ctx, name)
+}
+
+func (c *Client) ListAccounts(ctx context.Context) ([]ports.AWSAccount, error) {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return nil, err
+	}
+	return c.next.ListAccounts(ctx)
+}
+
+func (c *Client) DescribeAccount(ctx context.Context, accountID string) (*ports.AWSAccount, error) {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return nil, err
+	}
+	return c.next.DescribeAccount(ctx, accountID)
+}
+
+func (c *Client) GetAccountParent(ctx context.Context, accountID string) (string, error) {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return "", err
+	}
+	return c.next.GetAccountParent(ctx, accountID)
+}
+
+func (c *Client) TagAccount(ctx context.Context, accountID string, tags map[string]string) error {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return err
+	}
+	return c.next.TagAccount(ctx, accountID, tags)
+}
+
+func (c *Client) GetAccountTags(ctx context.Context, accountID string) (map[string]string, error) {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return nil, err
+	}
+	return c.next.GetAccountTags(ctx, accountID)
+}
+
+func (c *Client) MoveAccount(ctx context.Context, accountID, sourceParentID, destinationParentID string) error {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return err
+	}
+	return c.next.MoveAccount(ctx, accountID, sourceParentID, destinationParentID)
+}
+
+func (c *Client) CloseAccount(ctx context.Context, accountID string) error {
+	if err := c.wait(ctx, ServiceOrganizations); err != nil {
+		return err
+	}
+	return c.next.CloseAccount(ctx, accountID)
+}
+
+func (c *Client) CreateOIDCProviderForGitHub(ctx context.Context, accountID string) error {
+	if err := c.wait(ctx, ServiceIAM); err != nil {
+		return err
+	}
+	return c.next.CreateOIDCProviderForGitHub(ctx, accountID)
+}
+
+func (c *Client) GetOIDCProviderForGitHub(ctx context.Context, accountID string) (string, error) {
+	if err := c.wait(ctx, ServiceIAM); err != nil {
+		return "", err
+	}
+	return c.next.GetOIDCProviderForGitHub(ctx, accountID)
+}
+
+func (c *Client) DeleteOIDCProviderForGitHub(ctx context.Context, accountID string) error {
+	if err := c.wait(ctx, ServiceIAM); err != nil {
+		return err
+	}
+	return c.next.DeleteOIDCProviderForGitHub(ctx, accountID)
+}
+
+func (c *Client) CreateGitHubActionsRole(ctx context.Context, req ports.AWSCreateRoleRequest) (string, error) {
+	if err := c.wait(ctx, ServiceIAM); err != nil {
+		return "", err
+	}
+	return c.next.CreateGitHubActionsRole(ctx, req)
+}
+
+func (c *Client) GetRole(ctx context.Context, accountID, roleName string) (string, error) {
+	if err := c.wait(ctx, ServiceIAM); err != nil {
+		return "", err
+	}
+	return c.next.GetRole(ctx, accountID, roleName)
+}
+
+func (c *Client) DeleteRole(ctx context.Context, accountID, roleName string) error {
+	if err := c.wait(ctx, ServiceIAM); err != nil {
+		return err
+	}
+	return c.next.DeleteRole(ctx, accountID, roleName)
+}
+
+func (c *Client) BootstrapCDK(ctx context.Context, accountID, region, trustAccountID string) error {
+	if err := c.wait(ctx, ServiceCloudFormation); err != nil {
+		return err
+	}
+	return c.next.BootstrapCDK(ctx, accountID, region, trustAccountID)
+}
+
+func (c *Client) IsCDKBootstrapped(ctx context.Context, accountID, region string) (bool, error) {
+	if err := c.wait(ctx, ServiceCloudFormation); err != nil {
+		return false, err
+	}
+	return c.next.IsCDKBootstrapped(ctx, accountID, region)
+}
+
+func (c *Client) CreateBudget(ctx context.Context, req ports.AWSCreateBudgetRequest) error {
+	if err := c.wait(ctx, ServiceBudgets); err != nil {
+		return err
+	}
+	return c.next.CreateBudget(ctx, req)
+}
+
+func (c *Client) GetBudget(ctx context.Context, accountID, budgetName string) (*ports.AWSBudget, error) {
+	if err := c.wait(ctx, ServiceBudgets); err != nil {
+		return nil, err
+	}
+	return c.next.GetBudget(ctx, accountID, budgetName)
+}
+
+func (c *Client) UpdateBudget(ctx context.Context, req ports.AWSCreateBudgetRequest) error {
+	if err := c.wait(ctx, ServiceBudgets); err != nil {
+		return err
+	}
+	return c.next.UpdateBudget(ctx, req)
+}
+
+func (c *Client) DeleteBudget(ctx context.Context, accountID, budgetName string) error {
+	if err := c.wait(ctx, ServiceBudgets); err != nil {
+		return err
+	}
+	return c.next.DeleteBudget(ctx, accountID, budgetName)
+}
+
+func (c *Client) CreateBillingAlarm(ctx context.Context, req ports.AWSCreateBillingAlarmRequest) error {
+	if err := c.wait(ctx, ServiceCloudWatch); err != nil {
+		return err
+	}
+	return c.next.CreateBillingAlarm(ctx, req)
+}
+
+func (c *Client) GetBillingAlarm(ctx context.Context, accountID, alarmName string) (*ports.AWSBillingAlarm, error) {
+	if err := c.wait(ctx, ServiceCloudWatch); err != nil {
+		return nil, err
+	}
+	return c.next.GetBillingAlarm(ctx, accountID, alarmName)
+}
+
+func (c *Client) DeleteBillingAlarm(ctx context.Context, accountID, alarmName string) error {
+	if err := c.wait(ctx, ServiceCloudWatch); err != nil {
+		return err
+	}
+	return c.next.DeleteBillingAlarm(ctx, accountID, alarmName)
+}
+
+func (c *Client) CreateSNSTopic(ctx context.Context, accountID, topicName string) (string, error) {
+	if err := c.wait(ctx, ServiceSNS); err != nil {
+		return "", err
+	}
+	return c.next.CreateSNSTopic(ctx, accountID, topicName)
+}
+
+func (c *Client) SubscribeEmailToSNSTopic(ctx context.Context, topicARN, email string) error {
+	if err := c.wait(ctx, ServiceSNS); err != nil {
+		return err
+	}
+	return c.next.SubscribeEmailToSNSTopic(ctx, topicARN, email)
+}
+
+func (c *Client) DeleteSNSTopic(ctx context.Context, topicARN string) error {
+	if err := c.wait(ctx, ServiceSNS); err != nil {
+		return err
+	}
+	return c.next.DeleteSNSTopic(ctx, topicARN)
+}
+
+func (c *Client) AssumeRole(ctx context.Context, roleARN, sessionName string) (*ports.AWSCredentials, error) {
+	if err := c.wait(ctx, ServiceSTS); err != nil {
+		return nil, err
+	}
+	return c.next.AssumeRole(ctx, roleARN, sessionName)
+}
+
+func (c *Client) GetCallerIdentity(ctx context.Context) (*ports.AWSCallerIdentity, error) {
+	if err := c.wait(ctx, ServiceSTS); err != nil {
+		return nil, err
+	}
+	return c.next.GetCallerIdentity(ctx)
+}
+
+// Compile-time check that Client implements ports.AWSClient.
+var _ ports.AWSClient = (*Client)(nil)
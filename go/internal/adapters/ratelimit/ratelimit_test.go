@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+var testStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestBurstThenSteadyRate(t *testing.T) {
+	clock := mock.NewClock(testStart)
+	client := New(mock.NewAWSClient(), Config{
+		ServiceOrganizations: {RatePerSecond: 2, Burst: 2},
+	}, WithClock(clock))
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.GetAccountByName(context.Background(), "TPA_DEV"); err != nil {
+			t.Fatalf("GetAccountByName() error = %v", err)
+		}
+	}
+
+	// Two calls fit in the burst, the next two wait half a second each
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}
+	if got := clock.Waits(); !slices.Equal(got, want) {
+		t.Errorf("waits = %v, want %v", got, want)
+	}
+}
+
+func TestServicesAreLimitedIndependently(t *testing.T) {
+	clock := mock.NewClock(testStart)
+	client := New(mock.NewAWSClient(), Config{
+		ServiceOrganizations: {RatePerSecond: 1, Burst: 1},
+		ServiceIAM:           {RatePerSecond: 1, Burst: 1},
+	}, WithClock(clock))
+	ctx := context.Background()
+
+	if _, err := client.GetAccountByName(ctx, "TPA_DEV"); err != nil {
+		t.Fatalf("GetAccountByName() error = %v", err)
+	}
+	// Organizations is exhausted, IAM is not
+	if _, err := client.GetRole(ctx, "123456789012", "GitHubActionsDeployRole"); err != nil {
+		t.Fatalf("GetRole() error = %v", err)
+	}
+	// STS has no limit configured
+	if _, err := client.GetCallerIdentity(ctx); err != nil {
+		t.Fatalf("GetCallerIdentity() error = %v", err)
+	}
+
+	if got := clock.Waits(); len(got) != 0 {
+		t.Errorf("waits = %v, want none", got)
+	}
+}
+
+func TestLimitSharedAcrossGoroutines(t *testing.T) {
+	clock := mock.NewClock(testStart)
+	client := New(mock.NewAWSClient(), Config{
+		ServiceOrganizations: {RatePerSecond: 1, Burst: 1},
+	}, WithClock(clock))
+
+	const calls = 10
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetAccountByName(context.Background(), "TPA_DEV"); err != nil {
+				t.Errorf("GetAccountByName() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// At 1 call/second, 10 calls need at least 9 seconds after the burst
+	if elapsed := clock.Now().Sub(testStart); elapsed < 9*time.Second {
+		t.Errorf("elapsed = %v, want at least 9s for %d calls at 1/s", elapsed, calls)
+	}
+}
+
+func TestCancelledWhileWaiting(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	client := New(mockAWS, Config{
+		ServiceOrganizations: {RatePerSecond: 1, Burst: 1},
+	}, WithClock(stuckClock{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := client.GetAccountByName(ctx, "TPA_DEV"); err != nil {
+		t.Fatalf("first GetAccountByName() error = %v", err)
+	}
+	cancel()
+
+	_, err := client.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateAccount() error = %v, want context.Canceled", err)
+	}
+	if got := len(mockAWS.GetOperations()); got != 1 {
+		t.Errorf("operations = %v, want only the first lookup", mockAWS.GetOperations())
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+		check   func(t *testing.T, c Config)
+	}{
+		{
+			name: "override merges with defaults",
+			json: `{"organizations": {"ratePerSecond": 0.5, "burst": 1}}`,
+			check: func(t *testing.T, c Config) {
+				if got := c[ServiceOrganizations]; got != (Limit{RatePerSecond: 0.5, Burst: 1}) {
+					t.Errorf("organizations = %+v, want override", got)
+				}
+				if got := c[ServiceIAM]; got != DefaultConfig()[ServiceIAM] {
+					t.Errorf("iam = %+v, want default", got)
+				}
+			},
+		},
+		{
+			name:    "unknown service",
+			json:    `{"ec2": {"ratePerSecond": 1, "burst": 1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "negative rate",
+			json:    `{"iam": {"ratePerSecond": -1, "burst": 1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			json:    `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig([]byte(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, config)
+			}
+		})
+	}
+}
+
+// stuckClock never fires, so only context cancellation ends a wait.
+type stuckClock struct{}
+
+func (stuckClock) Now() time.Time                         { return testStart }
+func (stuckClock) After(d time.Duration) <-chan time.Time { return nil }
@@ -0,0 +1,235 @@
+package remote
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Defaults for a StateStore.
+const (
+	DefaultPrefix        = "aws-bootstrap/"
+	DefaultLeaseDuration = time.Hour
+	DefaultPollInterval  = 5 * time.Second
+)
+
+// StateStore keeps project state in S3-compatible object storage, so every
+// machine running setup for a project shares one copy.
+//
+// Each project uses two objects under the prefix:
+//
+//	<prefix><projectCode>/account-ids.json  state, same schema as the local file
+//	<prefix><projectCode>/lock.json         held while a run is in progress
+//
+// Writes are optimistic: SaveState only succeeds if the state is still at
+// the ETag it was loaded with (ports.ErrStateConflict otherwise). On top of
+// that, LockState serialises whole runs. The lock is an object created with
+// If-None-Match, so only one run can create it; it carries a lease so a
+// run that crashed without unlocking doesn't block the project forever.
+type StateStore struct {
+	objects ports.ObjectStore
+	prefix  string
+	owner   string
+	lease   time.Duration
+	poll    time.Duration
+	clock   ports.Clock
+}
+
+// Option configures a StateStore.
+type Option func(*StateStore)
+
+// WithPrefix sets the key prefix (default DefaultPrefix), e.g. to share a
+// bucket with other tools.
+func WithPrefix(prefix string) Option {
+	return func(s *StateStore) {
+		s.prefix = prefix
+	}
+}
+
+// WithOwner sets the name recorded in locks this store takes, shown to
+// runs waiting for the lock (default: hostname and process ID).
+func WithOwner(owner string) Option {
+	return func(s *StateStore) {
+		s.owner = owner
+	}
+}
+
+// WithLeaseDuration sets how long a lock is honoured before another run
+// may break it (default DefaultLeaseDuration). It must outlast a full
+// setup run; locks are not renewed.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(s *StateStore) {
+		s.lease = d
+	}
+}
+
+// WithPollInterval sets how often LockState checks a held lock (default
+// DefaultPollInterval).
+func WithPollInterval(d time.Duration) Option {
+	return func(s *StateStore) {
+		s.poll = d
+	}
+}
+
+// WithClock sets the clock used for lease expiry and polling.
+// Tests pass a mock.Clock so waiting doesn't actually sleep.
+func WithClock(clock ports.Clock) Option {
+	return func(s *StateStore) {
+		s.clock = clock
+	}
+}
+
+// New creates a state store backed by objects.
+func New(objects ports.ObjectStore, opts ...Option) *StateStore {
+	s := &StateStore{
+		objects: objects,
+		prefix:  DefaultPrefix,
+		lease:   DefaultLeaseDuration,
+		poll:    DefaultPollInterval,
+		clock:   ports.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.owner == "" {
+		host, _ := os.Hostname()
+		s.owner = fmt.Sprintf("%s/%d", host, os.Getpid())
+	}
+	return s
+}
+
+// LoadState reads the project's state, or returns nil if none is stored.
+func (s *StateStore) LoadState(ctx context.Context, projectCode string) (*ports.ProjectState, error) {
+	key := s.stateKey(projectCode)
+	object, err := s.objects.GetObject(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state %s: %w", key, err)
+	}
+	if object == nil {
+		return nil, nil
+	}
+
+	var state ports.ProjectState
+	if err := json.Unmarshal(object.Data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", key, err)
+	}
+	if state.ProjectCode != "" && state.ProjectCode != projectCode {
+		return nil, fmt.Errorf("state %s is for project %s, not %s", key, state.ProjectCode, projectCode)
+	}
+	state.Revision = object.ETag
+	return &state, nil
+}
+
+// SaveState writes the state if nobody else saved it since it was loaded.
+// New state (empty Revision) is only written if none exists yet.
+func (s *StateStore) SaveState(ctx context.Context, state *ports.ProjectState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	cond := ports.WriteCondition{IfMatch: state.Revision, IfNoneMatch: state.Revision == ""}
+	key := s.stateKey(state.ProjectCode)
+	etag, err := s.objects.PutObject(ctx, key, append(data, '\n'), cond)
+	if errors.Is(err, ports.ErrPreconditionFailed) {
+		return fmt.Errorf("%w: %s", ports.ErrStateConflict, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write state %s: %w", key, err)
+	}
+	state.Revision = etag
+	return nil
+}
+
+// lockInfo is the content of a lock object.
+type lockInfo struct {
+	ID         string    `json:"id"`
+	Owner      string    `json:"owner"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// LockState waits until this store holds the project's lock, breaking
+// locks whose lease has expired.
+func (s *StateStore) LockState(ctx context.Context, projectCode string) (func(context.Context) error, error) {
+	key := s.lockKey(projectCode)
+	for {
+		now := s.clock.Now().UTC()
+		mine := lockInfo{ID: newLockID(), Owner: s.owner, AcquiredAt: now, ExpiresAt: now.Add(s.lease)}
+		data, err := json.Marshal(mine)
+		if err != nil {
+			return nil, err
+		}
+
+		etag, err := s.objects.PutObject(ctx, key, data, ports.WriteCondition{IfNoneMatch: true})
+		if err == nil {
+			return s.unlock(key, etag), nil
+		}
+		if !errors.Is(err, ports.ErrPreconditionFailed) {
+			return nil, fmt.Errorf("failed to create lock %s: %w", key, err)
+		}
+
+		held, err := s.objects.GetObject(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lock %s: %w", key, err)
+		}
+		if held == nil {
+			continue // Released in between
+		}
+
+		// An unreadable lock can't expire, so it's treated as stale
+		var holder lockInfo
+		if err := json.Unmarshal(held.Data, &holder); err != nil || !now.Before(holder.ExpiresAt) {
+			err := s.objects.DeleteObject(ctx, key, ports.WriteCondition{IfMatch: held.ETag})
+			if err != nil && !errors.Is(err, ports.ErrPreconditionFailed) {
+				return nil, fmt.Errorf("failed to break expired lock %s: %w", key, err)
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %s held by %s since %s: %w",
+				ports.ErrStateLocked, projectCode, holder.Owner, holder.AcquiredAt.Format(time.RFC3339), ctx.Err())
+		case <-s.clock.After(s.poll):
+		}
+	}
+}
+
+// unlock releases the lock created with etag. If the lease expired and
+// another run took over, the lock is left alone and the loss reported.
+func (s *StateStore) unlock(key, etag string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		err := s.objects.DeleteObject(ctx, key, ports.WriteCondition{IfMatch: etag})
+		if errors.Is(err, ports.ErrPreconditionFailed) {
+			return fmt.Errorf("%w: lease on %s expired before unlock", ports.ErrStateLocked, key)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", key, err)
+		}
+		return nil
+	}
+}
+
+func (s *StateStore) stateKey(projectCode string) string {
+	return s.prefix + projectCode + "/account-ids.json"
+}
+
+func (s *StateStore) lockKey(projectCode string) string {
+	return s.prefix + projectCode + "/lock.json"
+}
+
+// newLockID returns a random ID, so two runs with the same owner name
+// still hold distinct locks.
+func newLockID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
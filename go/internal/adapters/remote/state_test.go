@@ -0,0 +1,156 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func TestStateStoreOptimisticWrites(t *testing.T) {
+	ctx := context.Background()
+	bucket := mock.NewObjectStore()
+	laptop, ci := New(bucket), New(bucket)
+
+	if state, err := laptop.LoadState(ctx, "TPA"); err != nil || state != nil {
+		t.Fatalf("LoadState() on empty bucket = %+v, %v; want nil, nil", state, err)
+	}
+
+	state := &ports.ProjectState{ProjectCode: "TPA", Environments: map[string]ports.EnvironmentState{
+		"dev": {AccountID: "100000000001"},
+	}}
+	if err := laptop.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState() failed: %v", err)
+	}
+	if err := ci.SaveState(ctx, &ports.ProjectState{ProjectCode: "TPA"}); !errors.Is(err, ports.ErrStateConflict) {
+		t.Errorf("Creating state twice = %v, want ErrStateConflict", err)
+	}
+
+	// Both load the same revision; only the first save wins
+	fromLaptop, _ := laptop.LoadState(ctx, "TPA")
+	fromCI, _ := ci.LoadState(ctx, "TPA")
+	if fromCI.Environments["dev"].AccountID != "100000000001" || fromCI.Revision == "" {
+		t.Fatalf("LoadState() = %+v", fromCI)
+	}
+	fromLaptop.OUID = "ou-813y-8teevv2l"
+	if err := laptop.SaveState(ctx, fromLaptop); err != nil {
+		t.Fatalf("SaveState() failed: %v", err)
+	}
+	fromCI.OUID = "ou-813y-elsewhere"
+	if err := ci.SaveState(ctx, fromCI); !errors.Is(err, ports.ErrStateConflict) {
+		t.Errorf("Stale SaveState() = %v, want ErrStateConflict", err)
+	}
+
+	// The winner can keep saving with its updated revision
+	fromLaptop.ManagementAccountID = "123456789012"
+	if err := laptop.SaveState(ctx, fromLaptop); err != nil {
+		t.Errorf("Second SaveState() failed: %v", err)
+	}
+
+	if _, err := New(bucket, WithPrefix("other/")).LoadState(ctx, "TPA"); err != nil {
+		t.Errorf("LoadState() under another prefix failed: %v", err)
+	}
+}
+
+func TestStateStoreLockSerialisesRuns(t *testing.T) {
+	ctx := context.Background()
+	bucket := mock.NewObjectStore()
+
+	// Each run adds its environment with a read-modify-write
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := New(bucket, WithOwner(fmt.Sprintf("run-%d", i)), WithPollInterval(time.Millisecond))
+			unlock, err := store.LockState(ctx, "TPA")
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer unlock(ctx)
+
+			state, err := store.LoadState(ctx, "TPA")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if state == nil {
+				state = &ports.ProjectState{ProjectCode: "TPA", Environments: map[string]ports.EnvironmentState{}}
+			}
+			state.Environments[fmt.Sprintf("env%d", i)] = ports.EnvironmentState{AccountID: fmt.Sprintf("10000000000%d", i)}
+			errs <- store.SaveState(ctx, state)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	}
+
+	state, _ := New(bucket).LoadState(ctx, "TPA")
+	if len(state.Environments) != 5 {
+		t.Errorf("Environments = %v, want all 5 runs recorded", state.Environments)
+	}
+	if keys := bucket.Keys(); len(keys) != 1 || keys[0] != "aws-bootstrap/TPA/account-ids.json" {
+		t.Errorf("Bucket keys = %v, want only the state (locks released)", keys)
+	}
+}
+
+func TestStateStoreLockWaitAndExpiry(t *testing.T) {
+	bucket := mock.NewObjectStore()
+	holder := New(bucket, WithOwner("laptop"))
+	if _, err := holder.LockState(context.Background(), "TPA"); err != nil {
+		t.Fatalf("LockState() failed: %v", err)
+	}
+
+	// A held lock makes others wait until their context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := New(bucket, WithPollInterval(time.Millisecond)).LockState(ctx, "TPA")
+	if !errors.Is(err, ports.ErrStateLocked) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockState() on held lock = %v, want ErrStateLocked", err)
+	}
+
+	// Once the lease runs out (the fake clock advances while polling), the
+	// lock is broken and the old holder learns it lost it
+	clock := mock.NewClock(time.Now())
+	ci := New(bucket, WithClock(clock), WithPollInterval(10*time.Minute))
+	unlock, err := ci.LockState(context.Background(), "TPA")
+	if err != nil {
+		t.Fatalf("LockState() after lease expiry failed: %v", err)
+	}
+	if waited := len(clock.Waits()); waited == 0 {
+		t.Error("LockState() should have polled before breaking the lock")
+	}
+	if err := unlock(context.Background()); err != nil {
+		t.Errorf("unlock() failed: %v", err)
+	}
+}
+
+func TestStateStoreLockLost(t *testing.T) {
+	ctx := context.Background()
+	bucket := mock.NewObjectStore()
+	clock := mock.NewClock(time.Now())
+
+	unlock, _ := New(bucket, WithClock(clock), WithLeaseDuration(time.Minute)).LockState(ctx, "TPA")
+	clock.Advance(2 * time.Minute)
+	if _, err := New(bucket, WithClock(clock)).LockState(ctx, "TPA"); err != nil {
+		t.Fatalf("LockState() on expired lock failed: %v", err)
+	}
+
+	if err := unlock(ctx); !errors.Is(err, ports.ErrStateLocked) {
+		t.Errorf("unlock() after losing the lock = %v, want ErrStateLocked", err)
+	}
+	if keys := bucket.Keys(); len(keys) != 1 {
+		t.Errorf("Bucket keys = %v, want the new holder's lock kept", keys)
+	}
+}
@@ -0,0 +1,496 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Policy controls how often and how patiently a method is retried.
+type Policy struct {
+	MaxAttempts int           // Total attempts including the first (1 = no retries)
+	BaseDelay   time.Duration // Delay before the first retry
+	MaxDelay    time.Duration // Upper bound for any single delay
+	Multiplier  float64       // Growth factor between retries (e.g., 2 doubles the delay)
+	Jitter      float64       // Fraction of each delay that is randomized (0 to 1)
+}
+
+// DefaultPolicy returns the policy used for methods without their own.
+//
+// Five attempts with delays of roughly 0.5s, 1s, 2s and 4s ride out the
+// throttling Organizations and IAM apply during bulk account setup.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 5,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.5,
+	}
+}
+
+// Delay returns how long to wait after the given failed attempt (1-based).
+//
+// r is a random number in [0, 1) that removes up to Jitter of the delay,
+// so concurrent callers don't retry in lockstep.
+func (p Policy) Delay(attempt int, r float64) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	d -= d * p.Jitter * r
+	return time.Duration(d)
+}
+
+// Client is a ports.AWSClient decorator that retries transient failures.
+//
+// Every call is retried with exponential backoff and jitter while the
+// classifier says the error is retryable, the method's attempt budget
+// isn't used up and the context allows it.
+//
+// Calls that aren't idempotent are never blindly replayed.
+// MoveAccount, CreateOIDCProviderForGitHub, CreateGitHubActionsRole and
+// CreateBudget may have succeeded even though AWS reported an error.
+// Before replaying, the client checks whether the change already happened
+// and, if so, returns success. If that check fails, the original error is
+// returned instead of risking a duplicate. CreateAccount can't be checked
+// that way (a creation in progress is invisible to lookups), so it is only
+// replayed after errors showing AWS rejected the request.
+//
+// Usage:
+//
+//	client := retry.New(awsClient,
+//	    retry.WithMethodPolicy("WaitForAccountCreation", retry.Policy{MaxAttempts: 3, ...}),
+//	)
+//	accounts, err := account.CreateAllAccounts(ctx, client, config)
+type Client struct {
+	next     ports.AWSClient
+	policy   Policy
+	methods  map[string]Policy
+	classify func(error) bool
+	clock    ports.Clock
+	random   func() float64
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithPolicy replaces the default policy used by all methods.
+func WithPolicy(p Policy) Option {
+	return func(c *Client) {
+		c.policy = p
+	}
+}
+
+// WithMethodPolicy sets the policy for a single method, by its name in
+// ports.AWSClient (e.g., "CreateAccount").
+func WithMethodPolicy(method string, p Policy) Option {
+	return func(c *Client) {
+		c.methods[method] = p
+	}
+}
+
+// WithClassifier replaces the function deciding whether an error is worth
+// retrying. The default is ports.IsRetryable. Context cancellation is
+// never retried regardless of the classifier.
+func WithClassifier(classify func(error) bool) Option {
+	return func(c *Client) {
+		c.classify = classify
+	}
+}
+
+// WithClock sets the clock used to wait between attempts.
+// Tests pass a mock.Clock so backoff doesn't actually sleep. Context
+// deadlines are still measured in real time, as context does.
+func WithClock(clock ports.Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// WithRandom sets the source of jitter, returning values in [0, 1).
+func WithRandom(random func() float64) Option {
+	return func(c *Client) {
+		c.random = random
+	}
+}
+
+// New wraps next with retries.
+func New(next ports.AWSClient, opts ...Option) *Client {
+	c := &Client{
+		next:     next,
+		policy:   DefaultPolicy(),
+		methods:  make(map[string]Policy),
+		classify: ports.IsRetryable,
+		clock:    ports.SystemClock(),
+		random:   rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Name returns the wrapped client's name.
+func (c *Client) Name() string {
+	return c.next.Name()
+}
+
+func (c *Client) policyFor(method string) Policy {
+	p, ok := c.methods[method]
+	if !ok {
+		p = c.policy
+	}
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	return p
+}
+
+func (c *Client) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return c.classify(err)
+}
+
+// do runs op until it succeeds or retrying stops being worthwhile.
+//
+// reconcile is optional. It runs before each replay and reports whether
+// the previous attempt actually took effect, in which case do returns
+// success without calling op again.
+func (c *Client) do(ctx context.Context, method string, op func() error, reconcile func() (bool, error)) error {
+	p := c.policyFor(method)
+
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		var final *finalError
+		if errors.As(err, &final) {
+			return giveUp(method, attempt, final.err)
+		}
+		if attempt >= p.MaxAttempts || !c.retryable(err) {
+			return giveUp(method, attempt, err)
+		}
+
+		delay := p.Delay(attempt, c.random())
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return giveUp(method, attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s abandoned after %d attempts: %w (last error: %w)", method, attempt, ctx.Err(), err)
+		case <-c.clock.After(delay):
+		}
+
+		if reconcile != nil {
+			done, lookupErr := reconcile()
+			if lookupErr != nil {
+				// Can't tell whether the last attempt worked - don't replay it.
+				return giveUp(method, attempt, err)
+			}
+			if done {
+				return nil
+			}
+		}
+	}
+}
+
+// finalError marks an error op must not be replayed after, whatever the
+// classifier says. do returns the wrapped error.
+type finalError struct {
+	err error
+}
+
+func (e *finalError) Error() string {
+	return e.err.Error()
+}
+
+func (e *finalError) Unwrap() error {
+	return e.err
+}
+
+func giveUp(method string, attempts int, err error) error {
+	if attempts == 1 {
+		return err
+	}
+	return fmt.Errorf("%s failed after %d attempts: %w", method, attempts, err)
+}
+
+// CreateAccount creates an AWS account, replaying only rejected requests.
+//
+// Throttling and concurrent modification errors mean Organizations turned
+// the request away. Any other failure (a service error, a lost response)
+// may have started a creation that GetAccountByName can't see until it
+// finishes, so replaying could request a second account.
+func (c *Client) CreateAccount(ctx context.Context, req ports.AWSCreateAccountRequest) (string, error) {
+	var accountID string
+	err := c.do(ctx, "CreateAccount", func() (err error) {
+		accountID, err = c.next.CreateAccount(ctx, req)
+		if err != nil && !errors.Is(err, ports.ErrThrottled) && !errors.Is(err, ports.ErrConcurrentModification) {
+			return &finalError{err: err}
+		}
+		return err
+	}, nil)
+	return accountID, err
+}
+
+func (c *Client) WaitForAccountCreation(ctx context.Context, accountID string) error {
+	return c.do(ctx, "WaitForAccountCreation", func() error {
+		return c.next.WaitForAccountCreation(ctx, accountID)
+	}, nil)
+}
+
+func (c *Client) GetAccountByName(ctx context.Context, name string) (string, error) {
+	var accountID string
+	err := c.do(ctx, "GetAccountByName", func() (err error) {
+		accountID, err = c.next.GetAccountByName(ctx, name)
+		return err
+	}, nil)
+	return accountID, err
+}
+
+func (c *Client) ListAccounts(ctx context.Context) ([]ports.AWSAccount, error) {
+	var accounts []ports.AWSAccount
+	err := c.do(ctx, "ListAccounts", func() (err error) {
+		accounts, err = c.next.ListAccounts(ctx)
+		return err
+	}, nil)
+	return accounts, err
+}
+
+func (c *Client) DescribeAccount(ctx context.Context, accountID string) (*ports.AWSAccount, error) {
+	var account *ports.AWSAccount
+	err := c.do(ctx, "DescribeAccount", func() (err error) {
+		account, err = c.next.DescribeAccount(ctx, accountID)
+		return err
+	}, nil)
+	return account, err
+}
+
+func (c *Client) GetAccountParent(ctx context.Context, accountID string) (string, error) {
+	var parentID string
+	err := c.do(ctx, "GetAccountParent", func() (err error) {
+		parentID, err = c.next.GetAccountParent(ctx, accountID)
+		return err
+	}, nil)
+	return parentID, err
+}
+
+func (c *Client) TagAccount(ctx context.Context, accountID string, tags map[string]string) error {
+	return c.do(ctx, "TagAccount", func() error {
+		return c.next.TagAccount(ctx, accountID, tags)
+	}, nil)
+}
+
+func (c *Client) GetAccountTags(ctx context.Context, accountID string) (map[string]string, error) {
+	var tags map[string]string
+	err := c.do(ctx, "GetAccountTags", func() (err error) {
+		tags, err = c.next.GetAccountTags(ctx, accountID)
+		return err
+	}, nil)
+	return tags, err
+}
+
+// MoveAccount moves the account, checking its parent before any replay.
+func (c *Client) MoveAccount(ctx context.Context, accountID, sourceParentID, destinationParentID string) error {
+	return c.do(ctx, "MoveAccount",
+		func() error {
+			return c.next.MoveAccount(ctx, accountID, sourceParentID, destinationParentID)
+		},
+		func() (bool, error) {
+			parentID, err := c.next.GetAccountParent(ctx, accountID)
+			return parentID == destinationParentID, err
+		})
+}
+
+// CloseAccount closes the account, checking its status before any replay.
+func (c *Client) CloseAccount(ctx context.Context, accountID string) error {
+	return c.do(ctx, "CloseAccount",
+		func() error {
+			return c.next.CloseAccount(ctx, accountID)
+		},
+		func() (bool, error) {
+			account, err := c.next.DescribeAccount(ctx, accountID)
+			return account != nil && account.Status != ports.AccountStatusActive, err
+		})
+}
+
+// CreateOIDCProviderForGitHub creates the provider, checking for it before any replay.
+func (c *Client) CreateOIDCProviderForGitHub(ctx context.Context, accountID string) error {
+	return c.do(ctx, "CreateOIDCProviderForGitHub",
+		func() error {
+			return c.next.CreateOIDCProviderForGitHub(ctx, accountID)
+		},
+		func() (bool, error) {
+			arn, err := c.next.GetOIDCProviderForGitHub(ctx, accountID)
+			return arn != "", err
+		})
+}
+
+func (c *Client) GetOIDCProviderForGitHub(ctx context.Context, accountID string) (string, error) {
+	var arn string
+	err := c.do(ctx, "GetOIDCProviderForGitHub", func() (err error) {
+		arn, err = c.next.GetOIDCProviderForGitHub(ctx, accountID)
+		return err
+	}, nil)
+	return arn, err
+}
+
+func (c *Client) DeleteOIDCProviderForGitHub(ctx context.Context, accountID string) error {
+	return c.do(ctx, "DeleteOIDCProviderForGitHub", func() error {
+		return c.next.DeleteOIDCProviderForGitHub(ctx, accountID)
+	}, nil)
+}
+
+// CreateGitHubActionsRole creates the role, checking for it before any replay.
+func (c *Client) CreateGitHubActionsRole(ctx context.Context, req ports.AWSCreateRoleRequest) (string, error) {
+	var arn string
+	err := c.do(ctx, "CreateGitHubActionsRole",
+		func() (err error) {
+			arn, err = c.next.CreateGitHubActionsRole(ctx, req)
+			return err
+		},
+		func() (bool, error) {
+			existingARN, err := c.next.GetRole(ctx, req.AccountID, req.RoleName)
+			if err != nil || existingARN == "" {
+				return false, err
+			}
+			arn = existingARN
+			return true, nil
+		})
+	return arn, err
+}
+
+func (c *Client) GetRole(ctx context.Context, accountID, roleName string) (string, error) {
+	var arn string
+	err := c.do(ctx, "GetRole", func() (err error) {
+		arn, err = c.next.GetRole(ctx, accountID, roleName)
+		return err
+	}, nil)
+	return arn, err
+}
+
+func (c *Client) DeleteRole(ctx context.Context, accountID, roleName string) error {
+	return c.do(ctx, "DeleteRole", func() error {
+		return c.next.DeleteRole(ctx, accountID, roleName)
+	}, nil)
+}
+
+func (c *Client) BootstrapCDK(ctx context.Context, accountID, region, trustAccountID string) error {
+	return c.do(ctx, "BootstrapCDK", func() error {
+		return c.next.BootstrapCDK(ctx, accountID, region, trustAccountID)
+	}, nil)
+}
+
+func (c *Client) IsCDKBootstrapped(ctx context.Context, accountID, region string) (bool, error) {
+	var bootstrapped bool
+	err := c.do(ctx, "IsCDKBootstrapped", func() (err error) {
+		bootstrapped, err = c.next.IsCDKBootstrapped(ctx, accountID, region)
+		return err
+	}, nil)
+	return bootstrapped, err
+}
+
+// CreateBudget creates the budget, checking for it before any replay.
+func (c *Client) CreateBudget(ctx context.Context, req ports.AWSCreateBudgetRequest) error {
+	return c.do(ctx, "CreateBudget",
+		func() error {
+			return c.next.CreateBudget(ctx, req)
+		},
+		func() (bool, error) {
+			budget, err := c.next.GetBudget(ctx, req.AccountID, req.BudgetName)
+			return budget != nil, err
+		})
+}
+
+func (c *Client) GetBudget(ctx context.Context, accountID, budgetName string) (*ports.AWSBudget, error) {
+	var budget *ports.AWSBudget
+	err := c.do(ctx, "GetBudget", func() (err error) {
+		budget, err = c.next.GetBudget(ctx, accountID, budgetName)
+		return err
+	}, nil)
+	return budget, err
+}
+
+func (c *Client) UpdateBudget(ctx context.Context, req ports.AWSCreateBudgetRequest) error {
+	return c.do(ctx, "UpdateBudget", func() error {
+		return c.next.UpdateBudget(ctx, req)
+	}, nil)
+}
+
+func (c *Client) DeleteBudget(ctx context.Context, accountID, budgetName string) error {
+	return c.do(ctx, "DeleteBudget", func() error {
+		return c.next.DeleteBudget(ctx, accountID, budgetName)
+	}, nil)
+}
+
+func (c *Client) CreateBillingAlarm(ctx context.Context, req ports.AWSCreateBillingAlarmRequest) error {
+	return c.do(ctx, "CreateBillingAlarm", func() error {
+		return c.next.CreateBillingAlarm(ctx, req)
+	}, nil)
+}
+
+func (c *Client) GetBillingAlarm(ctx context.Context, accountID, alarmName string) (*ports.AWSBillingAlarm, error) {
+	var alarm *ports.AWSBillingAlarm
+	err := c.do(ctx, "GetBillingAlarm", func() (err error) {
+		alarm, err = c.next.GetBillingAlarm(ctx, accountID, alarmName)
+		return err
+	}, nil)
+	return alarm, err
+}
+
+func (c *Client) DeleteBillingAlarm(ctx context.Context, accountID, alarmName string) error {
+	return c.do(ctx, "DeleteBillingAlarm", func() error {
+		return c.next.DeleteBillingAlarm(ctx, accountID, alarmName)
+	}, nil)
+}
+
+func (c *Client) CreateSNSTopic(ctx context.Context, accountID, topicName string) (string, error) {
+	var arn string
+	err := c.do(ctx, "CreateSNSTopic", func() (err error) {
+		arn, err = c.next.CreateSNSTopic(ctx, accountID, topicName)
+		return err
+	}, nil)
+	return arn, err
+}
+
+func (c *Client) SubscribeEmailToSNSTopic(ctx context.Context, topicARN, email string) error {
+	return c.do(ctx, "SubscribeEmailToSNSTopic", func() error {
+		return c.next.SubscribeEmailToSNSTopic(ctx, topicARN, email)
+	}, nil)
+}
+
+func (c *Client) DeleteSNSTopic(ctx context.Context, topicARN string) error {
+	return c.do(ctx, "DeleteSNSTopic", func() error {
+		return c.next.DeleteSNSTopic(ctx, topicARN)
+	}, nil)
+}
+
+func (c *Client) AssumeRole(ctx context.Context, roleARN, sessionName string) (*ports.AWSCredentials, error) {
+	var creds *ports.AWSCredentials
+	err := c.do(ctx, "AssumeRole", func() (err error) {
+		creds, err = c.next.AssumeRole(ctx, roleARN, sessionName)
+		return err
+	}, nil)
+	return creds, err
+}
+
+func (c *Client) GetCallerIdentity(ctx context.Context) (*ports.AWSCallerIdentity, error) {
+	var identity *ports.AWSCallerIdentity
+	err := c.do(ctx, "GetCallerIdentity", func() (err error) {
+		identity, err = c.next.GetCallerIdentity(ctx)
+		return err
+	}, nil)
+	return identity, err
+}
+
+// Compile-time check that Client implements ports.AWSClient.
+var _ ports.AWSClient = (*Client)(nil)
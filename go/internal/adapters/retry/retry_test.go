@@ -0,0 +1,303 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func throttled() error {
+	return ports.NewAWSError(ports.CodeTooManyRequests, "rate exceeded")
+}
+
+// newTestClient wraps the mock with a fake clock and no jitter.
+func newTestClient(mockAWS *mock.AWSClient, opts ...Option) (*Client, *mock.Clock) {
+	clock := mock.NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts = append([]Option{WithClock(clock), WithRandom(func() float64 { return 0 })}, opts...)
+	return New(mockAWS, opts...), clock
+}
+
+// countOps counts logged operations starting with prefix.
+func countOps(mockAWS *mock.AWSClient, prefix string) int {
+	n := 0
+	for _, op := range mockAWS.GetOperations() {
+		if strings.HasPrefix(op, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// lostResponseAWS creates the account but reports a service error, like a
+// response lost after AWS accepted the request.
+type lostResponseAWS struct {
+	*mock.AWSClient
+	lost int
+}
+
+func (l *lostResponseAWS) CreateAccount(ctx context.Context, req ports.AWSCreateAccountRequest) (string, error) {
+	id, err := l.AWSClient.CreateAccount(ctx, req)
+	if err == nil && l.lost > 0 {
+		l.lost--
+		return "", ports.NewAWSError(ports.CodeServiceException, "internal failure")
+	}
+	return id, err
+}
+
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2, Jitter: 0.5}
+
+	tests := []struct {
+		attempt int
+		random  float64
+		want    time.Duration
+	}{
+		{1, 0, time.Second},
+		{2, 0, 2 * time.Second},
+		{3, 0, 4 * time.Second},
+		{4, 0, 5 * time.Second}, // capped
+		{1, 0.5, 750 * time.Millisecond},
+		{4, 0.99, 2525 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := p.Delay(tt.attempt, tt.random); got != tt.want {
+			t.Errorf("Delay(%d, %v) = %v, want %v", tt.attempt, tt.random, got, tt.want)
+		}
+	}
+}
+
+func TestRetriesTransientErrors(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("GetRole", throttled(), ports.NewAWSError(ports.CodeConcurrentModification, "busy"))
+	client, clock := newTestClient(mockAWS)
+
+	if _, err := client.GetRole(context.Background(), "123456789012", "GitHubActionsDeployRole"); err != nil {
+		t.Fatalf("GetRole() error = %v, want nil", err)
+	}
+
+	if got := countOps(mockAWS, "GetRole"); got != 3 {
+		t.Errorf("GetRole attempts = %d, want 3", got)
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second}
+	if got := clock.Waits(); !slices.Equal(got, want) {
+		t.Errorf("waits = %v, want %v", got, want)
+	}
+}
+
+func TestRetriesThrottledCallsOnFlakyAccount(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	devID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	prodID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_PROD"})
+	mockAWS.AddFault(mock.AnyMethod, mock.FailWhen(
+		func(call mock.Call) bool { return call.AccountName == "TPA_PROD" && call.N <= 2 },
+		mock.ThrottlingError(),
+	))
+	client, clock := newTestClient(mockAWS)
+
+	for _, id := range []string{devID, prodID} {
+		if err := client.TagAccount(ctx, id, map[string]string{"Project": "TPA"}); err != nil {
+			t.Fatalf("TagAccount(%s) error = %v, want nil", id, err)
+		}
+	}
+
+	// N counts every TagAccount call: prod's first attempt is call 2 and is
+	// throttled, its retry is call 3 and goes through
+	ops := mockAWS.Operations().Method("TagAccount")
+	if got := ops.ForAccount(prodID).Count(); got != 2 {
+		t.Errorf("prod TagAccount attempts = %d, want 2", got)
+	}
+	if got := ops.Failed().Count(); got != 1 {
+		t.Errorf("failed attempts = %v, want 1", ops.Failed().Strings())
+	}
+	if got := clock.Waits(); len(got) != 1 {
+		t.Errorf("waits = %v, want one backoff", got)
+	}
+}
+
+func TestGivesUpWhenBudgetExhausted(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("BootstrapCDK", throttled(), throttled(), throttled(), throttled())
+	client, _ := newTestClient(mockAWS, WithMethodPolicy("BootstrapCDK", Policy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		Multiplier:  2,
+	}))
+
+	err := client.BootstrapCDK(context.Background(), "123456789012", "us-east-1", "999999999999")
+	if err == nil {
+		t.Fatal("BootstrapCDK() error = nil, want error")
+	}
+	if !errors.Is(err, ports.ErrThrottled) {
+		t.Errorf("errors.Is(%v, ErrThrottled) = false, want true", err)
+	}
+	if ports.ErrorCode(err) != ports.CodeTooManyRequests {
+		t.Errorf("ErrorCode() = %q, want %q", ports.ErrorCode(err), ports.CodeTooManyRequests)
+	}
+	if got := countOps(mockAWS, "BootstrapCDK"); got != 3 {
+		t.Errorf("BootstrapCDK attempts = %d, want 3", got)
+	}
+}
+
+func TestDoesNotRetryPermanentErrors(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("CreateAccount", ports.NewAWSError(ports.CodeEmailAlreadyExists, "in use"))
+	client, clock := newTestClient(mockAWS)
+
+	_, err := client.CreateAccount(context.Background(), ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	if !errors.Is(err, ports.ErrEmailAlreadyExists) {
+		t.Fatalf("CreateAccount() error = %v, want ErrEmailAlreadyExists", err)
+	}
+	if len(clock.Waits()) != 0 {
+		t.Errorf("waits = %v, want none", clock.Waits())
+	}
+}
+
+func TestCustomClassifier(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("GetBudget", errors.New("connection reset"))
+	client, _ := newTestClient(mockAWS, WithClassifier(func(err error) bool {
+		return strings.Contains(err.Error(), "connection reset")
+	}))
+
+	if _, err := client.GetBudget(context.Background(), "123456789012", "TPA-dev-monthly-budget"); err != nil {
+		t.Fatalf("GetBudget() error = %v, want nil", err)
+	}
+}
+
+func TestCreateAccountNotReplayedAfterAmbiguousFailure(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	// The creation is still in progress, so a lookup wouldn't find it
+	mockAWS.SimulateAccountCreation(mock.AccountCreation{Duration: time.Hour})
+	aws := &lostResponseAWS{AWSClient: mockAWS, lost: 1}
+	client, _ := newTestClient(mockAWS)
+	client.next = aws
+
+	_, err := client.CreateAccount(context.Background(), ports.AWSCreateAccountRequest{
+		Name:      "TPA_DEV",
+		Email:     "test+tpa-dev@gmail.com",
+		OrgUnitID: "ou-test-12345678",
+	})
+	if ports.ErrorCode(err) != ports.CodeServiceException {
+		t.Fatalf("CreateAccount() error = %v, want the original service error", err)
+	}
+	if got := mockAWS.Operations().Method("CreateAccount").Count(); got != 1 {
+		t.Errorf("CreateAccount calls = %d, want 1 (replaying could start a second account)\nOperations: %v", got, mockAWS.GetOperations())
+	}
+}
+
+func TestCreateAccountReplaysRejectedRequests(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("CreateAccount", throttled(), ports.NewAWSError(ports.CodeConcurrentModification, "busy"))
+	client, _ := newTestClient(mockAWS)
+
+	accountID, err := client.CreateAccount(context.Background(), ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	if err != nil {
+		t.Fatalf("CreateAccount() error = %v, want nil", err)
+	}
+	if accountID == "" {
+		t.Error("CreateAccount() returned empty account ID")
+	}
+	if got := mockAWS.Operations().Method("CreateAccount").Count(); got != 3 {
+		t.Errorf("CreateAccount calls = %d, want 3\nOperations: %v", got, mockAWS.GetOperations())
+	}
+}
+
+func TestMoveAccountReconcilesBeforeReplay(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	accountID, _ := mockAWS.CreateAccount(context.Background(), ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	aws := &lostMoveAWS{AWSClient: mockAWS, lost: 1}
+	client, _ := newTestClient(mockAWS)
+	client.next = aws
+
+	if err := client.MoveAccount(context.Background(), accountID, mock.RootID, "ou-test-12345678"); err != nil {
+		t.Fatalf("MoveAccount() error = %v, want nil", err)
+	}
+
+	// Replaying would fail: the account is no longer in the root
+	if got := countOps(mockAWS, "MoveAccount("); got != 1 {
+		t.Errorf("MoveAccount calls = %d, want 1\nOperations: %v", got, mockAWS.GetOperations())
+	}
+	if got := countOps(mockAWS, "GetAccountParent("+accountID+") -> ou-test-12345678"); got != 1 {
+		t.Errorf("expected a parent check before replaying MoveAccount\nOperations: %v", mockAWS.GetOperations())
+	}
+}
+
+// lostMoveAWS moves the account but reports a concurrent modification error.
+type lostMoveAWS struct {
+	*mock.AWSClient
+	lost int
+}
+
+func (l *lostMoveAWS) MoveAccount(ctx context.Context, accountID, sourceParentID, destinationParentID string) error {
+	err := l.AWSClient.MoveAccount(ctx, accountID, sourceParentID, destinationParentID)
+	if err == nil && l.lost > 0 {
+		l.lost--
+		return ports.NewAWSError(ports.CodeConcurrentModification, "busy")
+	}
+	return err
+}
+
+func TestRespectsContextDeadline(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("GetCallerIdentity", throttled(), throttled())
+	client, clock := newTestClient(mockAWS)
+
+	// The first backoff (500ms) doesn't fit in the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetCallerIdentity(ctx); !errors.Is(err, ports.ErrThrottled) {
+		t.Fatalf("GetCallerIdentity() error = %v, want ErrThrottled", err)
+	}
+	if len(clock.Waits()) != 0 {
+		t.Errorf("waits = %v, want none", clock.Waits())
+	}
+}
+
+func TestDeadlineIgnoresFakeClock(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("GetCallerIdentity", throttled(), throttled())
+	// The fake clock is far behind real time; the deadline is real
+	client, clock := newTestClient(mockAWS)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	if _, err := client.GetCallerIdentity(ctx); err != nil {
+		t.Fatalf("GetCallerIdentity() error = %v, want nil", err)
+	}
+	if waits := clock.Waits(); !slices.Equal(waits, []time.Duration{500 * time.Millisecond, time.Second}) {
+		t.Errorf("waits = %v, want [500ms 1s]", waits)
+	}
+}
+
+func TestCancelledContextStopsRetries(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("GetRole", throttled(), throttled())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A clock that never fires: only ctx.Done() can end the wait
+	client := New(mockAWS, WithClock(stuckClock{}))
+	_, err := client.GetRole(ctx, "123456789012", "GitHubActionsDeployRole")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("errors.Is(%v, context.Canceled) = false, want true", err)
+	}
+	if !errors.Is(err, ports.ErrThrottled) {
+		t.Errorf("errors.Is(%v, ErrThrottled) = false, want true", err)
+	}
+}
+
+type stuckClock struct{}
+
+func (stuckClock) Now() time.Time                         { return time.Time{} }
+func (stuckClock) After(d time.Duration) <-chan time.Time { return nil }
@@ -0,0 +1,293 @@
+package account
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Naming conventions.
+//
+// Every name the tool creates or looks up (account, budget, alarm, SNS
+// topic, deploy role) is rendered from a template, so projects whose
+// existing resources follow another scheme can still be found and reused.
+//
+// Templates are plain text with placeholders:
+//   - {project}: the project code as configured (e.g., "TPA")
+//   - {env}:     the environment name (e.g., "dev")
+//   - {org}:     NamingConvention.Org
+//   - {sep}:     NamingConvention.Separator (default "-")
+//
+// {project}, {env} and {org} take an optional case modifier:
+// {env:upper} -> "DEV", {project:lower} -> "tpa".
+//
+// This is DOMAIN LOGIC - the default preset reproduces the v1 bash scripts.
+
+// Built-in naming presets.
+const (
+	// NamingPresetV1 reproduces the v1 bash scripts: accounts are
+	// "TPA_DEV" (create-project-accounts.sh), resources "TPA-dev-..."
+	// (setup-billing-alerts.sh), the role "GitHubActionsDeployRole"
+	// (setup-github-cicd.sh). This is the default.
+	NamingPresetV1 = "v1"
+
+	// NamingPresetHyphenated names accounts "TPA-dev", the form used in
+	// the port documentation; resources are named as in v1.
+	NamingPresetHyphenated = "hyphenated"
+)
+
+// NamingConvention describes how resource names are built.
+//
+// Preset selects a built-in convention; any template set here replaces the
+// preset's. Prefix and Suffix wrap every rendered name and may use
+// placeholders too, e.g. Org "acme" with Prefix "{org}{sep}" gives
+// "acme-TPA_DEV".
+type NamingConvention struct {
+	Preset    string `json:"preset,omitempty"`    // Built-in convention to start from (default: v1)
+	Separator string `json:"separator,omitempty"` // Value of {sep} (default: "-")
+	Org       string `json:"org,omitempty"`       // Value of {org}
+	Prefix    string `json:"prefix,omitempty"`    // Prepended to every name
+	Suffix    string `json:"suffix,omitempty"`    // Appended to every name
+
+	Account string `json:"account,omitempty"` // AWS account name
+	Budget  string `json:"budget,omitempty"`  // AWS Budget name
+	Alarm   string `json:"alarm,omitempty"`   // CloudWatch billing alarm name
+	Topic   string `json:"topic,omitempty"`   // SNS billing topic name
+	Role    string `json:"role,omitempty"`    // GitHub Actions deploy role name
+}
+
+// namingPresets holds the built-in conventions by name.
+var namingPresets = map[string]NamingConvention{
+	NamingPresetV1: {
+		Separator: "-",
+		Account:   "{project}_{env:upper}",
+		Budget:    "{project}{sep}{env}{sep}monthly-budget",
+		Alarm:     "{project}{sep}{env}{sep}billing-alarm",
+		Topic:     "{project}{sep}{env}{sep}billing-alerts",
+		Role:      "GitHubActionsDeployRole",
+	},
+	NamingPresetHyphenated: {
+		Separator: "-",
+		Account:   "{project}{sep}{env}",
+		Budget:    "{project}{sep}{env}{sep}monthly-budget",
+		Alarm:     "{project}{sep}{env}{sep}billing-alarm",
+		Topic:     "{project}{sep}{env}{sep}billing-alerts",
+		Role:      "GitHubActionsDeployRole",
+	},
+}
+
+// NamingPresets returns the names of the built-in conventions.
+func NamingPresets() []string {
+	return slices.Sorted(maps.Keys(namingPresets))
+}
+
+// Resolve returns the convention with every unset template filled in from
+// its preset.
+//
+// Returns:
+//   - Error if Preset names no built-in convention
+func (n NamingConvention) Resolve() (NamingConvention, error) {
+	presetName := n.Preset
+	if presetName == "" {
+		presetName = NamingPresetV1
+	}
+	preset, ok := namingPresets[presetName]
+	if !ok {
+		return NamingConvention{}, &ValidationError{
+			Field:      "naming.preset",
+			Code:       CodeUnknown,
+			Message:    fmt.Sprintf("unknown naming preset %q", n.Preset),
+			Value:      n.Preset,
+			Suggestion: "use one of: " + strings.Join(NamingPresets(), ", "),
+		}
+	}
+
+	resolved := n
+	resolved.Preset = presetName
+	for _, f := range []struct{ dst, src *string }{
+		{&resolved.Separator, &preset.Separator},
+		{&resolved.Account, &preset.Account},
+		{&resolved.Budget, &preset.Budget},
+		{&resolved.Alarm, &preset.Alarm},
+		{&resolved.Topic, &preset.Topic},
+		{&resolved.Role, &preset.Role},
+	} {
+		if *f.dst == "" {
+			*f.dst = *f.src
+		}
+	}
+	return resolved, nil
+}
+
+// ResourceNames holds the names of one environment's resources.
+type ResourceNames struct {
+	Account string
+	Budget  string
+	Alarm   string
+	Topic   string
+	Role    string
+}
+
+// Names renders every resource name for env. The convention should be
+// resolved first (see Resolve); unset templates render as empty names.
+func (n NamingConvention) Names(projectCode string, env Environment) ResourceNames {
+	return ResourceNames{
+		Account: n.render(n.Account, projectCode, env),
+		Budget:  n.render(n.Budget, projectCode, env),
+		Alarm:   n.render(n.Alarm, projectCode, env),
+		Topic:   n.render(n.Topic, projectCode, env),
+		Role:    n.render(n.Role, projectCode, env),
+	}
+}
+
+// placeholderRegex matches {name} and {name:modifier}.
+var placeholderRegex = regexp.MustCompile(`\{([a-z]+)(?::([a-z]+))?\}`)
+
+// render expands template, wrapped in Prefix and Suffix.
+func (n NamingConvention) render(template, projectCode string, env Environment) string {
+	if template == "" {
+		return ""
+	}
+	values := map[string]string{
+		"project": projectCode,
+		"env":     string(env),
+		"org":     n.Org,
+		"sep":     n.Separator,
+	}
+	return placeholderRegex.ReplaceAllStringFunc(n.Prefix+template+n.Suffix, func(match string) string {
+		parts := placeholderRegex.FindStringSubmatch(match)
+		value, ok := values[parts[1]]
+		if !ok {
+			return match
+		}
+		switch parts[2] {
+		case "upper":
+			return strings.ToUpper(value)
+		case "lower":
+			return strings.ToLower(value)
+		}
+		return value
+	})
+}
+
+// AWS naming rules for each resource type.
+var (
+	// Organizations account names: printable ASCII, max 50
+	accountNameRegex = regexp.MustCompile(`^[\x20-\x7E]{1,50}$`)
+
+	// Budget names: no ':' or '\', max 100
+	budgetNameRegex = regexp.MustCompile(`^[^:\\]{1,100}$`)
+
+	// CloudWatch alarm names: printable ASCII, max 255
+	alarmNameRegex = regexp.MustCompile(`^[\x20-\x7E]{1,255}$`)
+
+	// SNS topic names: alphanumerics, hyphens and underscores, max 256
+	topicNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+	// IAM role names: alphanumerics and +=,.@_-, max 64
+	roleNameRegex = regexp.MustCompile(`^[\w+=,.@-]{1,64}$`)
+)
+
+// validate checks the convention's templates and the names they render
+// for projectCode in every environment. n must be resolved.
+func (n NamingConvention) validate(report *ValidationReport, projectCode string, envs []Environment) {
+	templates := []struct {
+		field, template string
+	}{
+		{"naming.prefix", n.Prefix},
+		{"naming.suffix", n.Suffix},
+		{"naming.account", n.Account},
+		{"naming.budget", n.Budget},
+		{"naming.alarm", n.Alarm},
+		{"naming.topic", n.Topic},
+		{"naming.role", n.Role},
+	}
+	before := len(report.Errors)
+	for _, t := range templates {
+		for _, parts := range placeholderRegex.FindAllStringSubmatch(t.template, -1) {
+			switch {
+			case !slices.Contains([]string{"project", "env", "org", "sep"}, parts[1]):
+				report.Add(&ValidationError{
+					Field:      t.field,
+					Code:       CodeUnknown,
+					Message:    fmt.Sprintf("unknown placeholder %s", parts[0]),
+					Value:      t.template,
+					Suggestion: "use {project}, {env}, {org} or {sep}",
+				})
+			case parts[2] != "" && parts[2] != "upper" && parts[2] != "lower":
+				report.Add(&ValidationError{
+					Field:      t.field,
+					Code:       CodeUnknown,
+					Message:    fmt.Sprintf("unknown modifier in %s", parts[0]),
+					Value:      t.template,
+					Suggestion: fmt.Sprintf("use {%s:upper} or {%s:lower}", parts[1], parts[1]),
+				})
+			case parts[1] == "org" && n.Org == "":
+				report.Add(&ValidationError{
+					Field:      t.field,
+					Code:       CodeRequired,
+					Message:    "uses {org} but naming.org is not set",
+					Value:      t.template,
+					Suggestion: "set naming.org",
+				})
+			}
+		}
+	}
+
+	// Account names are how existing accounts are found, so each
+	// environment needs its own
+	if !strings.Contains(n.Prefix+n.Account+n.Suffix, "{env") {
+		report.Add(&ValidationError{
+			Field:      "naming.account",
+			Code:       CodeInvalid,
+			Message:    "must include {env} so each environment gets its own account",
+			Value:      n.Account,
+			Suggestion: n.Account + "{sep}{env}",
+		})
+	}
+
+	// Rendered names are only meaningful once the templates are valid
+	if len(report.Errors) > before {
+		return
+	}
+
+	reported := make(map[string]bool)
+	for _, env := range envs {
+		names := n.Names(projectCode, env)
+		checks := []struct {
+			field, name string
+			rule        *regexp.Regexp
+			maxLen      int
+			rules       string
+		}{
+			{"naming.account", names.Account, accountNameRegex, 50, "printable ASCII, max 50 characters"},
+			{"naming.budget", names.Budget, budgetNameRegex, 100, "no ':' or '\\', max 100 characters"},
+			{"naming.alarm", names.Alarm, alarmNameRegex, 255, "printable ASCII, max 255 characters"},
+			{"naming.topic", names.Topic, topicNameRegex, 256, "letters, digits, '-' and '_', max 256 characters"},
+			{"naming.role", names.Role, roleNameRegex, 64, "letters, digits and +=,.@_-, max 64 characters"},
+		}
+		for _, c := range checks {
+			// One report per template is enough
+			if reported[c.field] || c.rule.MatchString(c.name) {
+				continue
+			}
+			reported[c.field] = true
+
+			code := CodeInvalidFormat
+			if len(c.name) > c.maxLen {
+				code = CodeTooLong
+			}
+			report.Add(&ValidationError{
+				Field:   c.field,
+				Code:    code,
+				Message: fmt.Sprintf("renders invalid name %q for %s (%s)", c.name, env, c.rules),
+				Value:   c.name,
+			})
+		}
+	}
+}
+
+// v1Naming is the resolved default convention.
+var v1Naming, _ = NamingConvention{}.Resolve()
@@ -0,0 +1,150 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func namingConfig() Config {
+	return Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+	}
+}
+
+func TestNamingConventionNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		naming NamingConvention
+		env    Environment
+		want   ResourceNames
+	}{
+		{
+			// Must match the v1 bash scripts exactly so v2 finds their resources
+			name:   "v1 default",
+			naming: NamingConvention{},
+			env:    EnvironmentDev,
+			want: ResourceNames{
+				Account: "TPA_DEV",
+				Budget:  "TPA-dev-monthly-budget",
+				Alarm:   "TPA-dev-billing-alarm",
+				Topic:   "TPA-dev-billing-alerts",
+				Role:    "GitHubActionsDeployRole",
+			},
+		},
+		{
+			name:   "hyphenated",
+			naming: NamingConvention{Preset: NamingPresetHyphenated},
+			env:    EnvironmentStaging,
+			want: ResourceNames{
+				Account: "TPA-staging",
+				Budget:  "TPA-staging-monthly-budget",
+				Alarm:   "TPA-staging-billing-alarm",
+				Topic:   "TPA-staging-billing-alerts",
+				Role:    "GitHubActionsDeployRole",
+			},
+		},
+		{
+			name: "org prefix, separator and case",
+			naming: NamingConvention{
+				Preset:    NamingPresetHyphenated,
+				Separator: "_",
+				Org:       "Acme",
+				Prefix:    "{org:lower}{sep}",
+				Account:   "{project:lower}{sep}{env}",
+				Role:      "{project}-deploy",
+			},
+			env: EnvironmentProd,
+			want: ResourceNames{
+				Account: "acme_tpa_prod",
+				Budget:  "acme_TPA_prod_monthly-budget",
+				Alarm:   "acme_TPA_prod_billing-alarm",
+				Topic:   "acme_TPA_prod_billing-alerts",
+				Role:    "acme_TPA-deploy",
+			},
+		},
+		{
+			name:   "suffix",
+			naming: NamingConvention{Suffix: "-v2"},
+			env:    EnvironmentDev,
+			want: ResourceNames{
+				Account: "TPA_DEV-v2",
+				Budget:  "TPA-dev-monthly-budget-v2",
+				Alarm:   "TPA-dev-billing-alarm-v2",
+				Topic:   "TPA-dev-billing-alerts-v2",
+				Role:    "GitHubActionsDeployRole-v2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{ProjectCode: "TPA", Naming: tt.naming}
+			if got := config.ResourceNames(tt.env); got != tt.want {
+				t.Errorf("ResourceNames() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamingConventionValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		naming NamingConvention
+		field  string
+	}{
+		{name: "unknown preset", naming: NamingConvention{Preset: "v3"}, field: "naming.preset"},
+		{name: "unknown placeholder", naming: NamingConvention{Account: "{project}-{stage}"}, field: "naming.account"},
+		{name: "unknown modifier", naming: NamingConvention{Budget: "{env:title}-budget"}, field: "naming.budget"},
+		{name: "org without value", naming: NamingConvention{Prefix: "{org}-"}, field: "naming.prefix"},
+		{name: "account without env", naming: NamingConvention{Account: "{project}"}, field: "naming.account"},
+		{name: "account too long", naming: NamingConvention{Org: strings.Repeat("a", 45), Prefix: "{org}-"}, field: "naming.account"},
+		{name: "topic with dots", naming: NamingConvention{Topic: "{project}.{env}.alerts"}, field: "naming.topic"},
+		{name: "role with spaces", naming: NamingConvention{Role: "Deploy Role"}, field: "naming.role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := namingConfig()
+			config.Naming = tt.naming
+
+			var validationErr *ValidationError
+			if err := config.Validate(); !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want ValidationError", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("Field = %s, want %s (%v)", validationErr.Field, tt.field, validationErr)
+			}
+		})
+	}
+}
+
+func TestCreateAllAccountsReusesHyphenatedAccounts(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	existing, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA-dev"})
+
+	config := namingConfig()
+	config.Naming = NamingConvention{Preset: NamingPresetHyphenated}
+
+	accounts, err := CreateAllAccounts(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("CreateAllAccounts() failed: %v", err)
+	}
+
+	if accounts[0].Name != "TPA-dev" || accounts[0].AccountID != existing || accounts[0].Created {
+		t.Errorf("dev account = %+v, want reused TPA-dev (%s)", accounts[0], existing)
+	}
+	if accounts[2].Name != "TPA-prod" || !accounts[2].Created {
+		t.Errorf("prod account = %+v, want newly created TPA-prod", accounts[2])
+	}
+	if summary := GenerateSummary(config); !strings.Contains(summary, "TPA-staging") {
+		t.Errorf("Summary should use the configured convention:\n%s", summary)
+	}
+}
@@ -0,0 +1,230 @@
+package account
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Account root email strategies.
+//
+// Every AWS account needs its own root email address. How those addresses
+// are produced depends on the mail setup:
+//   - plus:      user+tpa-dev@example.com, delivered to user@example.com
+//     (Gmail, Google Workspace, Microsoft 365, Fastmail, ...). The default,
+//     on gmail.com unless EmailPrefix or Domain names another domain.
+//   - subdomain: tpa-dev@aws.example.com, for domains with a catch-all
+//     mailbox.
+//   - explicit:  one address per environment, listed in Addresses.
+//
+// AWS limits root email addresses to 64 characters.
+
+// EmailScheme selects how account root emails are generated.
+type EmailScheme string
+
+const (
+	EmailSchemePlus      EmailScheme = "plus"
+	EmailSchemeSubdomain EmailScheme = "subdomain"
+	EmailSchemeExplicit  EmailScheme = "explicit"
+)
+
+// DefaultEmailDomain is the domain plus addressing uses when none is given.
+const DefaultEmailDomain = "gmail.com"
+
+// MaxAccountEmailLength is the AWS limit on account root email addresses.
+const MaxAccountEmailLength = 64
+
+// EmailStrategy configures account root email generation.
+type EmailStrategy struct {
+	Scheme    EmailScheme            `json:"scheme,omitempty"`    // default: plus
+	Domain    string                 `json:"domain,omitempty"`    // Mail domain for plus and subdomain schemes
+	Addresses map[Environment]string `json:"addresses,omitempty"` // Explicit scheme: address per environment
+}
+
+// Consumer mail providers can't host catch-all mailboxes, so the
+// subdomain scheme makes no sense on them.
+var consumerEmailDomains = []string{
+	"gmail.com", "googlemail.com", "outlook.com", "hotmail.com", "live.com",
+	"yahoo.com", "icloud.com", "me.com", "proton.me", "protonmail.com",
+}
+
+var (
+	// Email domain: dot-separated labels ending in an alphabetic TLD
+	emailDomainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+)
+
+// scheme returns the configured scheme, defaulting to plus addressing.
+func (s EmailStrategy) scheme() EmailScheme {
+	if s.Scheme == "" {
+		return EmailSchemePlus
+	}
+	return s.Scheme
+}
+
+// Address returns the root email for env.
+//
+// Parameters:
+//   - emailPrefix: Config.EmailPrefix ("user" or "user@example.com"); only
+//     the plus scheme uses it
+//   - projectCode: 3-character project identifier
+//   - env: Environment name
+//
+// Returns:
+//   - Email address, or "" for an explicit scheme without an address for env
+func (s EmailStrategy) Address(emailPrefix string, projectCode string, env Environment) string {
+	tag := fmt.Sprintf("%s-%s", strings.ToLower(projectCode), strings.ToLower(string(env)))
+
+	switch s.scheme() {
+	case EmailSchemeSubdomain:
+		return fmt.Sprintf("%s@%s", tag, strings.ToLower(s.Domain))
+	case EmailSchemeExplicit:
+		return s.Addresses[env]
+	}
+
+	local, domain := splitEmail(emailPrefix)
+	if domain == "" {
+		domain = cmp.Or(strings.ToLower(s.Domain), DefaultEmailDomain)
+	}
+	return fmt.Sprintf("%s+%s@%s", local, tag, domain)
+}
+
+// Mailbox returns the deliverable address behind emailPrefix: the prefix
+// itself if it is a full address, the plus-addressing mailbox otherwise.
+// Returns "" if there is none (e.g., subdomain scheme without a prefix).
+func (s EmailStrategy) Mailbox(emailPrefix string) string {
+	local, domain := splitEmail(emailPrefix)
+	switch {
+	case domain != "":
+		return local + "@" + domain
+	case local == "" || s.scheme() != EmailSchemePlus:
+		return ""
+	}
+	return local + "@" + cmp.Or(strings.ToLower(s.Domain), DefaultEmailDomain)
+}
+
+// String describes the strategy for summaries.
+func (s EmailStrategy) String() string {
+	switch s.scheme() {
+	case EmailSchemeSubdomain:
+		return fmt.Sprintf("subdomain catch-all (@%s)", s.Domain)
+	case EmailSchemeExplicit:
+		return "explicit per-environment addresses"
+	}
+	return fmt.Sprintf("plus addressing (@%s)", cmp.Or(s.Domain, DefaultEmailDomain))
+}
+
+// validate checks the strategy on its own; the generated addresses are
+// checked by validateOverrides once overrides are applied.
+func (s EmailStrategy) validate(report *ValidationReport, emailPrefix string, registry *EnvironmentRegistry) {
+	if s.Domain != "" && !emailDomainRegex.MatchString(strings.ToLower(s.Domain)) {
+		report.Add(&ValidationError{
+			Field:   "email.domain",
+			Code:    CodeInvalidFormat,
+			Message: fmt.Sprintf("invalid mail domain %q", s.Domain),
+			Value:   s.Domain,
+		})
+	}
+
+	switch s.scheme() {
+	case EmailSchemePlus:
+		if _, domain := splitEmail(emailPrefix); domain != "" && s.Domain != "" && !strings.EqualFold(domain, s.Domain) {
+			report.Add(&ValidationError{
+				Field:      "email.domain",
+				Code:       CodeConflict,
+				Message:    fmt.Sprintf("conflicts with the emailPrefix domain %s", domain),
+				Value:      s.Domain,
+				Suggestion: "drop email.domain or the domain in emailPrefix",
+			})
+		}
+
+	case EmailSchemeSubdomain:
+		if s.Domain == "" {
+			report.Add(&ValidationError{
+				Field:      "email.domain",
+				Code:       CodeRequired,
+				Message:    "is required for the subdomain scheme",
+				Suggestion: "set the catch-all domain, e.g. aws.example.com",
+			})
+		} else if slices.Contains(consumerEmailDomains, strings.ToLower(s.Domain)) {
+			report.Add(&ValidationError{
+				Field:      "email.domain",
+				Code:       CodeInvalid,
+				Message:    fmt.Sprintf("%s can't receive catch-all mail", s.Domain),
+				Value:      s.Domain,
+				Suggestion: "use the plus scheme instead",
+			})
+		}
+
+	case EmailSchemeExplicit:
+		for _, env := range registry.Environments() {
+			if s.Addresses[env] == "" {
+				report.Add(&ValidationError{
+					Field:      "email.addresses." + string(env),
+					Code:       CodeRequired,
+					Message:    "is required for the explicit scheme",
+					Suggestion: fmt.Sprintf("add a root email address for %s", env),
+				})
+			}
+		}
+		for _, env := range slices.Sorted(maps.Keys(s.Addresses)) {
+			if err := registry.Validate(env); err != nil {
+				report.Add(&ValidationError{
+					Field:   "email.addresses." + string(env),
+					Code:    CodeUnknown,
+					Message: fmt.Sprintf("unknown environment (declared: %v)", registry.Environments()),
+					Value:   string(env),
+				})
+			}
+		}
+
+	default:
+		report.Add(&ValidationError{
+			Field:      "email.scheme",
+			Code:       CodeUnknown,
+			Message:    fmt.Sprintf("unknown scheme %q", s.Scheme),
+			Value:      string(s.Scheme),
+			Suggestion: "use plus, subdomain or explicit",
+		})
+	}
+}
+
+// ValidateAccountEmail checks a generated or configured root email address:
+// full address, valid domain and within the AWS length limit.
+func ValidateAccountEmail(field, email string) error {
+	if !fullEmailRegex.MatchString(email) {
+		return &ValidationError{
+			Field:   field,
+			Code:    CodeInvalidFormat,
+			Message: fmt.Sprintf("%q must be a full email address", email),
+			Value:   email,
+		}
+	}
+	if _, domain := splitEmail(email); !emailDomainRegex.MatchString(strings.ToLower(domain)) {
+		return &ValidationError{
+			Field:   field,
+			Code:    CodeInvalidFormat,
+			Message: fmt.Sprintf("invalid mail domain %q", domain),
+			Value:   email,
+		}
+	}
+	if len(email) > MaxAccountEmailLength {
+		return &ValidationError{
+			Field:      field,
+			Code:       CodeTooLong,
+			Message:    fmt.Sprintf("%s is %d characters (AWS allows at most %d)", email, len(email), MaxAccountEmailLength),
+			Value:      email,
+			Suggestion: "use a shorter email prefix or the subdomain scheme",
+		}
+	}
+	return nil
+}
+
+// splitEmail splits "local@domain" (domain lowercased); a bare local part
+// returns an empty domain.
+func splitEmail(email string) (local, domain string) {
+	local, domain, _ = strings.Cut(email, "@")
+	return local, strings.ToLower(domain)
+}
@@ -0,0 +1,194 @@
+package account
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEmailStrategyAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		strategy    EmailStrategy
+		emailPrefix string
+		env         Environment
+		want        string
+	}{
+		{
+			name:        "default is gmail plus addressing",
+			emailPrefix: "user",
+			env:         EnvironmentDev,
+			want:        "user+tpa-dev@gmail.com",
+		},
+		{
+			name:        "plus addressing on the prefix's domain",
+			emailPrefix: "jane.doe@Example.com",
+			env:         EnvironmentProd,
+			want:        "jane.doe+tpa-prod@example.com",
+		},
+		{
+			name:        "plus addressing on a configured domain",
+			strategy:    EmailStrategy{Domain: "corp.example.com"},
+			emailPrefix: "cloud",
+			env:         EnvironmentStaging,
+			want:        "cloud+tpa-staging@corp.example.com",
+		},
+		{
+			name:     "subdomain catch-all",
+			strategy: EmailStrategy{Scheme: EmailSchemeSubdomain, Domain: "aws.example.com"},
+			env:      EnvironmentDev,
+			want:     "tpa-dev@aws.example.com",
+		},
+		{
+			name: "explicit address",
+			strategy: EmailStrategy{Scheme: EmailSchemeExplicit, Addresses: map[Environment]string{
+				EnvironmentProd: "aws-prod@example.com",
+			}},
+			env:  EnvironmentProd,
+			want: "aws-prod@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.strategy.Address(tt.emailPrefix, "TPA", tt.env); got != tt.want {
+				t.Errorf("Address() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailStrategyMailbox(t *testing.T) {
+	tests := []struct {
+		strategy    EmailStrategy
+		emailPrefix string
+		want        string
+	}{
+		{EmailStrategy{}, "user", "user@gmail.com"},
+		{EmailStrategy{Domain: "example.com"}, "user", "user@example.com"},
+		{EmailStrategy{Scheme: EmailSchemeSubdomain, Domain: "aws.example.com"}, "ops@example.com", "ops@example.com"},
+		{EmailStrategy{Scheme: EmailSchemeSubdomain, Domain: "aws.example.com"}, "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.strategy.Mailbox(tt.emailPrefix); got != tt.want {
+			t.Errorf("Mailbox(%q) with %v = %q, want %q", tt.emailPrefix, tt.strategy, got, tt.want)
+		}
+	}
+}
+
+func TestConfigValidateEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		field  string // empty for a valid config
+	}{
+		{
+			name:   "workspace domain prefix",
+			modify: func(c *Config) { c.EmailPrefix = "cloud@example.com" },
+		},
+		{
+			name: "subdomain without prefix",
+			modify: func(c *Config) {
+				c.EmailPrefix = ""
+				c.Email = EmailStrategy{Scheme: EmailSchemeSubdomain, Domain: "aws.example.com"}
+			},
+		},
+		{
+			name:   "invalid prefix domain",
+			modify: func(c *Config) { c.EmailPrefix = "user@example" },
+			field:  "emailPrefix",
+		},
+		{
+			name: "subdomain without domain",
+			modify: func(c *Config) {
+				c.Email = EmailStrategy{Scheme: EmailSchemeSubdomain}
+			},
+			field: "email.domain",
+		},
+		{
+			name: "subdomain on a consumer domain",
+			modify: func(c *Config) {
+				c.Email = EmailStrategy{Scheme: EmailSchemeSubdomain, Domain: "gmail.com"}
+			},
+			field: "email.domain",
+		},
+		{
+			name: "domain conflicts with prefix",
+			modify: func(c *Config) {
+				c.EmailPrefix = "user@example.com"
+				c.Email = EmailStrategy{Domain: "other.com"}
+			},
+			field: "email.domain",
+		},
+		{
+			name: "explicit missing an environment",
+			modify: func(c *Config) {
+				c.Email = EmailStrategy{Scheme: EmailSchemeExplicit, Addresses: map[Environment]string{
+					EnvironmentDev:  "dev@example.com",
+					EnvironmentProd: "prod@example.com",
+				}}
+			},
+			field: "email.addresses.staging",
+		},
+		{
+			name: "explicit address is not an email",
+			modify: func(c *Config) {
+				c.Email = EmailStrategy{Scheme: EmailSchemeExplicit, Addresses: map[Environment]string{
+					EnvironmentDev:     "dev@example.com",
+					EnvironmentStaging: "staging",
+					EnvironmentProd:    "prod@example.com",
+				}}
+			},
+			field: "email.addresses.staging",
+		},
+		{
+			name:   "unknown scheme",
+			modify: func(c *Config) { c.Email = EmailStrategy{Scheme: "alias"} },
+			field:  "email.scheme",
+		},
+		{
+			name:   "generated address too long",
+			modify: func(c *Config) { c.EmailPrefix = strings.Repeat("a", 45) + "@example.com" },
+			field:  "email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{ProjectCode: "TPA", EmailPrefix: "user", OUID: "ou-813y-8teevv2l"}
+			tt.modify(&config)
+
+			err := config.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want ValidationError", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("Field = %s, want %s (%v)", validationErr.Field, tt.field, validationErr)
+			}
+		})
+	}
+}
+
+func TestGenerateSummaryEmailScheme(t *testing.T) {
+	config := Config{
+		ProjectCode: "TPA",
+		OUID:        "ou-813y-8teevv2l",
+		Email:       EmailStrategy{Scheme: EmailSchemeSubdomain, Domain: "aws.example.com"},
+	}
+
+	summary := GenerateSummary(config)
+	for _, want := range []string{"subdomain catch-all (@aws.example.com)", "tpa-prod@aws.example.com"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, summary)
+		}
+	}
+}
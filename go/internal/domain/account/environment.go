@@ -0,0 +1,152 @@
+package account
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Environment registry.
+//
+// Projects aren't limited to dev/staging/prod: a config can declare its own
+// environments (qa, sandbox, perf, ...). The registry holds those
+// definitions and is the single source of truth for which environments
+// exist, the order they are provisioned in and their per-environment
+// defaults. dev/staging/prod remain the built-in registry.
+
+// EnvironmentSpec defines one environment a project can provision.
+type EnvironmentSpec struct {
+	Name        Environment         `json:"name"`                  // Short name used in account names and emails (e.g., "qa")
+	DisplayName string              `json:"displayName,omitempty"` // Human-friendly name (e.g., "Quality Assurance")
+	Order       int                 `json:"order"`                 // Provisioning and display order (lowest first)
+	Production  bool                `json:"production,omitempty"`  // True for environments serving real users
+	Defaults    EnvironmentSettings `json:"defaults,omitempty"`    // Settings that differ from the project-wide ones
+}
+
+// Label returns the display name, falling back to the environment name.
+func (s EnvironmentSpec) Label() string {
+	if s.DisplayName != "" {
+		return s.DisplayName
+	}
+	return string(s.Name)
+}
+
+// DefaultEnvironmentSpecs returns the built-in dev/staging/prod definitions.
+func DefaultEnvironmentSpecs() []EnvironmentSpec {
+	return []EnvironmentSpec{
+		{Name: EnvironmentDev, DisplayName: "Development", Order: 10},
+		{Name: EnvironmentStaging, DisplayName: "Staging", Order: 20},
+		{Name: EnvironmentProd, DisplayName: "Production", Order: 30, Production: true},
+	}
+}
+
+// Environment names end up in account names, emails and resource names,
+// so they are restricted to lowercase letters, digits and hyphens.
+var environmentNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]{0,15}$`)
+
+// EnvironmentRegistry is an ordered set of environment definitions.
+type EnvironmentRegistry struct {
+	specs  []EnvironmentSpec
+	byName map[Environment]EnvironmentSpec
+}
+
+// NewEnvironmentRegistry creates a registry from specs, sorted by Order
+// (ties keep their declared order).
+//
+// Returns:
+//   - Error if specs is empty, a name is invalid or a name is declared twice
+//     (a *ValidationReport listing every bad spec)
+func NewEnvironmentRegistry(specs ...EnvironmentSpec) (*EnvironmentRegistry, error) {
+	if len(specs) == 0 {
+		return nil, &ValidationError{
+			Field:      "environmentSpecs",
+			Code:       CodeRequired,
+			Message:    "must declare at least one environment",
+			Suggestion: "declare environments or omit environmentSpecs for dev/staging/prod",
+		}
+	}
+
+	r := &EnvironmentRegistry{
+		specs:  make([]EnvironmentSpec, len(specs)),
+		byName: make(map[Environment]EnvironmentSpec, len(specs)),
+	}
+	copy(r.specs, specs)
+
+	report := &ValidationReport{}
+	for i, spec := range r.specs {
+		field := fmt.Sprintf("environmentSpecs[%d].name", i)
+		if !environmentNameRegex.MatchString(string(spec.Name)) {
+			report.Add(&ValidationError{
+				Field:      field,
+				Code:       CodeInvalidFormat,
+				Message:    fmt.Sprintf("invalid environment name %q (lowercase letters, digits and hyphens, max 16 characters)", spec.Name),
+				Value:      string(spec.Name),
+				Suggestion: "use a short lowercase name such as qa",
+			})
+			continue
+		}
+		if _, dup := r.byName[spec.Name]; dup {
+			report.Add(&ValidationError{
+				Field:   field,
+				Code:    CodeDuplicate,
+				Message: fmt.Sprintf("environment %q is declared more than once", spec.Name),
+				Value:   string(spec.Name),
+			})
+			continue
+		}
+		r.byName[spec.Name] = spec
+	}
+	if err := report.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(r.specs, func(a, b EnvironmentSpec) int {
+		return cmp.Compare(a.Order, b.Order)
+	})
+	return r, nil
+}
+
+// DefaultEnvironmentRegistry returns the built-in dev/staging/prod registry.
+func DefaultEnvironmentRegistry() *EnvironmentRegistry {
+	r, err := NewEnvironmentRegistry(DefaultEnvironmentSpecs()...)
+	if err != nil {
+		panic(err) // The built-in specs are always valid
+	}
+	return r
+}
+
+// Environments returns every registered environment in order.
+func (r *EnvironmentRegistry) Environments() []Environment {
+	envs := make([]Environment, len(r.specs))
+	for i, spec := range r.specs {
+		envs[i] = spec.Name
+	}
+	return envs
+}
+
+// Specs returns every environment definition in order.
+func (r *EnvironmentRegistry) Specs() []EnvironmentSpec {
+	specs := make([]EnvironmentSpec, len(r.specs))
+	copy(specs, r.specs)
+	return specs
+}
+
+// Lookup returns the definition of env.
+func (r *EnvironmentRegistry) Lookup(env Environment) (EnvironmentSpec, bool) {
+	spec, ok := r.byName[env]
+	return spec, ok
+}
+
+// Validate checks that env is registered.
+func (r *EnvironmentRegistry) Validate(env Environment) error {
+	if _, ok := r.byName[env]; ok {
+		return nil
+	}
+	return &ValidationError{
+		Field:   "environment",
+		Code:    CodeUnknown,
+		Message: fmt.Sprintf("must be one of: %v", r.Environments()),
+		Value:   string(env),
+	}
+}
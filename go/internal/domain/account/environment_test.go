@@ -0,0 +1,186 @@
+package account
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+)
+
+func customSpecs() []EnvironmentSpec {
+	return []EnvironmentSpec{
+		{Name: "perf", DisplayName: "Performance", Order: 30},
+		{Name: "qa", DisplayName: "Quality Assurance", Order: 10},
+		{Name: "sandbox", Order: 20},
+		{Name: "prod", DisplayName: "Production", Order: 40, Production: true},
+	}
+}
+
+func TestNewEnvironmentRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []EnvironmentSpec
+		want    []Environment
+		wantErr bool
+	}{
+		{
+			name:  "sorted by order",
+			specs: customSpecs(),
+			want:  []Environment{"qa", "sandbox", "perf", "prod"},
+		},
+		{
+			name: "ties keep declared order",
+			specs: []EnvironmentSpec{
+				{Name: "b"},
+				{Name: "a"},
+			},
+			want: []Environment{"b", "a"},
+		},
+		{
+			name:    "empty",
+			specs:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate name",
+			specs:   []EnvironmentSpec{{Name: "qa"}, {Name: "qa"}},
+			wantErr: true,
+		},
+		{
+			name:    "uppercase name",
+			specs:   []EnvironmentSpec{{Name: "QA"}},
+			wantErr: true,
+		},
+		{
+			name:    "name with underscore",
+			specs:   []EnvironmentSpec{{Name: "load_test"}},
+			wantErr: true,
+		},
+		{
+			name:    "name too long",
+			specs:   []EnvironmentSpec{{Name: "a-very-long-environment"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := NewEnvironmentRegistry(tt.specs...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewEnvironmentRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := registry.Environments(); !slices.Equal(got, tt.want) {
+				t.Errorf("Environments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultEnvironmentRegistry(t *testing.T) {
+	registry := DefaultEnvironmentRegistry()
+
+	if got := registry.Environments(); !slices.Equal(got, AllEnvironments()) {
+		t.Errorf("Environments() = %v, want %v", got, AllEnvironments())
+	}
+
+	prod, ok := registry.Lookup(EnvironmentProd)
+	if !ok || !prod.Production || prod.Label() != "Production" {
+		t.Errorf("Lookup(prod) = %+v, %v", prod, ok)
+	}
+	if dev, _ := registry.Lookup(EnvironmentDev); dev.Production {
+		t.Error("dev should not be a production environment")
+	}
+}
+
+func TestConfigWithCustomEnvironments(t *testing.T) {
+	config := Config{
+		ProjectCode:      "TPA",
+		EmailPrefix:      "user",
+		OUID:             "ou-813y-8teevv2l",
+		EnvironmentSpecs: customSpecs(),
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	envs, err := config.TargetEnvironments()
+	if err != nil {
+		t.Fatalf("TargetEnvironments() error = %v", err)
+	}
+	if want := []Environment{"qa", "sandbox", "perf", "prod"}; !slices.Equal(envs, want) {
+		t.Errorf("TargetEnvironments() = %v, want %v", envs, want)
+	}
+
+	// staging is built in, but this config doesn't declare it
+	config.Environments = []Environment{"qa", EnvironmentStaging}
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() should reject environments the config doesn't declare")
+	}
+}
+
+func TestCreateAllAccountsCustomEnvironments(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	config := Config{
+		ProjectCode:      "TPA",
+		EmailPrefix:      "user",
+		OUID:             "ou-813y-8teevv2l",
+		EnvironmentSpecs: customSpecs(),
+	}
+
+	accounts, err := CreateAllAccounts(context.Background(), mockAWS, config)
+	if err != nil {
+		t.Fatalf("CreateAllAccounts() failed: %v", err)
+	}
+
+	var names []string
+	for _, acc := range accounts {
+		names = append(names, acc.Name)
+	}
+	if want := []string{"TPA_QA", "TPA_SANDBOX", "TPA_PERF", "TPA_PROD"}; !slices.Equal(names, want) {
+		t.Errorf("account names = %v, want %v", names, want)
+	}
+	if accounts[0].Email != "user+tpa-qa@gmail.com" {
+		t.Errorf("qa email = %s, want user+tpa-qa@gmail.com", accounts[0].Email)
+	}
+
+	// CreateSingleAccount honours the same registry
+	if _, err := CreateSingleAccount(context.Background(), mockAWS, config, "sandbox"); err != nil {
+		t.Errorf("CreateSingleAccount(sandbox) failed: %v", err)
+	}
+	if _, err := CreateSingleAccount(context.Background(), mockAWS, config, EnvironmentDev); err == nil {
+		t.Error("CreateSingleAccount(dev) should fail when dev isn't declared")
+	}
+}
+
+func TestGenerateSummaryCustomEnvironments(t *testing.T) {
+	config := Config{
+		ProjectCode:      "TPA",
+		EmailPrefix:      "user",
+		OUID:             "ou-813y-8teevv2l",
+		EnvironmentSpecs: customSpecs(),
+	}
+
+	summary := GenerateSummary(config)
+
+	for _, expected := range []string{
+		"TPA_QA",
+		"user+tpa-qa@gmail.com (Quality Assurance)",
+		"user+tpa-sandbox@gmail.com (sandbox)",
+		"user+tpa-prod@gmail.com (Production) [production]",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("GenerateSummary() missing %q:\n%s", expected, summary)
+		}
+	}
+
+	// Registry order, not declaration order
+	if strings.Index(summary, "TPA_QA") > strings.Index(summary, "TPA_PERF") {
+		t.Errorf("qa should be listed before perf:\n%s", summary)
+	}
+}
@@ -0,0 +1,86 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Phase identifies which step of orchestration failed.
+type Phase string
+
+const (
+	PhaseLookup Phase = "lookup" // Checking whether the account already exists
+	PhaseCreate Phase = "create" // Requesting the account from AWS Organizations
+	PhaseWait   Phase = "wait"   // Waiting for Organizations to finish creating it
+	PhasePlace  Phase = "place"  // Checking or moving the account into its OU
+	PhaseTag    Phase = "tag"    // Applying account tags
+)
+
+// OperationError describes a failed orchestration step.
+//
+// Callers can inspect it with errors.As instead of matching error strings:
+//
+//	var opErr *account.OperationError
+//	if errors.As(err, &opErr) && opErr.Retryable {
+//	    // safe to re-run this environment
+//	}
+//
+// The underlying AWS error stays reachable through errors.Is, so checks
+// like errors.Is(err, ports.ErrEmailAlreadyExists) work too.
+type OperationError struct {
+	Environment Environment // Environment being provisioned
+	AccountName string      // Account name (e.g., "TPA_DEV")
+	AccountID   string      // AWS Account ID, if known at the time of failure
+	Phase       Phase       // Step that failed
+	Retryable   bool        // True if AWS reported a transient failure
+	Code        string      // AWS error code, if any (e.g., "EMAIL_ALREADY_EXISTS")
+	Err         error       // Underlying error
+}
+
+// NewOperationError wraps err with orchestration context.
+//
+// Retryable and Code are derived from err (see ports.IsRetryable and
+// ports.ErrorCode).
+func NewOperationError(env Environment, accountName, accountID string, phase Phase, err error) *OperationError {
+	return &OperationError{
+		Environment: env,
+		AccountName: accountName,
+		AccountID:   accountID,
+		Phase:       phase,
+		Retryable:   ports.IsRetryable(err),
+		Code:        ports.ErrorCode(err),
+		Err:         err,
+	}
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("%s: %s failed for AWS account %s: %v", e.Environment, e.Phase, e.AccountName, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// ErrAccountNotActive means an account with the expected name exists but
+// can't be reused because it is suspended or being closed.
+var ErrAccountNotActive = errors.New("account is not active")
+
+// InactiveAccountError reports an existing account that isn't ACTIVE, e.g.
+// one a decommission closed. AWS keeps closed accounts (and their names)
+// around for 90 days, so provisioning must not mistake one for a reusable
+// account.
+type InactiveAccountError struct {
+	AccountID string // AWS Account ID of the existing account
+	Status    string // Its status (e.g., ports.AccountStatusSuspended)
+}
+
+func (e *InactiveAccountError) Error() string {
+	return fmt.Sprintf("%s: account %s is %s", ErrAccountNotActive, e.AccountID, e.Status)
+}
+
+// Is makes errors.Is(err, ErrAccountNotActive) work.
+func (e *InactiveAccountError) Is(target error) bool {
+	return target == ErrAccountNotActive
+}
@@ -25,12 +25,16 @@ const (
 	EnvironmentProd    Environment = "prod"
 )
 
-// AllEnvironments returns all supported environments in order.
+// AllEnvironments returns the built-in environments in order.
+//
+// Configs may declare their own environments; use Config.TargetEnvironments
+// to get the environments a particular config provisions.
 func AllEnvironments() []Environment {
-	return []Environment{EnvironmentDev, EnvironmentStaging, EnvironmentProd}
+	return DefaultEnvironmentRegistry().Environments()
 }
 
-// GenerateAccountName generates a cloud account name following our naming convention.
+// GenerateAccountName generates a cloud account name following the v1
+// naming convention. Use Config.ResourceNames to honor a config's convention.
 //
 // Convention: ${PROJECT_CODE}_${ENV_UPPER}
 // Example: "TPA_DEV", "TPA_STAGING", "TPA_PROD"
@@ -42,31 +46,25 @@ func AllEnvironments() []Environment {
 // Returns:
 //   - Account name as a string
 func GenerateAccountName(projectCode string, env Environment) string {
-	envUpper := strings.ToUpper(string(env))
-	return fmt.Sprintf("%s_%s", projectCode, envUpper)
+	return v1Naming.Names(projectCode, env).Account
 }
 
 // GenerateAccountEmail generates an email address for an account using plus addressing.
 //
-// Convention: ${email_prefix}+${project_code_lower}-${env}@gmail.com
-// Example: "user+tpa-dev@gmail.com"
+// Convention: ${email_prefix}+${project_code_lower}-${env}@${domain}
+// Example: "user+tpa-dev@gmail.com", "jane+tpa-dev@example.com"
+//
+// Use Config.SettingsFor to honor a config's EmailStrategy.
 //
 // Parameters:
-//   - emailPrefix: Email address (can include or exclude @gmail.com)
+//   - emailPrefix: Email address or local part (the domain defaults to gmail.com)
 //   - projectCode: 3-character project identifier
 //   - env: Environment name
 //
 // Returns:
 //   - Email address as a string
 func GenerateAccountEmail(emailPrefix string, projectCode string, env Environment) string {
-	// Strip @gmail.com if present
-	prefix := strings.TrimSuffix(emailPrefix, "@gmail.com")
-
-	// Lowercase project code and environment
-	projectLower := strings.ToLower(projectCode)
-	envLower := strings.ToLower(string(env))
-
-	return fmt.Sprintf("%s+%s-%s@gmail.com", prefix, projectLower, envLower)
+	return EmailStrategy{}.Address(emailPrefix, projectCode, env)
 }
 
 // GetOrganizationAccessRoleName returns the standard role name for cross-account access.
@@ -76,29 +74,50 @@ func GetOrganizationAccessRoleName() string {
 	return "OrganizationAccountAccessRole"
 }
 
+// GetGitHubActionsRoleName returns the default IAM role name GitHub Actions assumes.
+//
+// Matches the role created by the v1 setup-github-cicd.sh script.
+func GetGitHubActionsRoleName() string {
+	return v1Naming.Names("", "").Role
+}
+
+// GenerateBudgetName generates the v1 AWS Budget name for an environment.
+//
+// Convention: ${PROJECT_CODE}-${env}-monthly-budget
+// Example: "TPA-dev-monthly-budget"
+func GenerateBudgetName(projectCode string, env Environment) string {
+	return v1Naming.Names(projectCode, env).Budget
+}
+
+// GenerateBillingAlarmName generates the v1 CloudWatch billing alarm name for an environment.
+//
+// Convention: ${PROJECT_CODE}-${env}-billing-alarm
+// Example: "TPA-dev-billing-alarm"
+func GenerateBillingAlarmName(projectCode string, env Environment) string {
+	return v1Naming.Names(projectCode, env).Alarm
+}
+
+// GenerateBillingTopicName generates the v1 SNS topic name for billing alerts.
+//
+// Convention: ${PROJECT_CODE}-${env}-billing-alerts
+// Example: "TPA-dev-billing-alerts"
+func GenerateBillingTopicName(projectCode string, env Environment) string {
+	return v1Naming.Names(projectCode, env).Topic
+}
+
 // Validation
 
 var (
 	// ProjectCode must be exactly 3 alphanumeric characters
 	projectCodeRegex = regexp.MustCompile(`^[A-Z0-9]{3}$`)
 
-	// Email basic validation
-	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+(@gmail\.com)?$`)
+	// Email prefix: a local part, optionally with its domain
+	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+(@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,})?$`)
 
 	// Organizational Unit ID format
 	ouIDRegex = regexp.MustCompile(`^ou-[a-z0-9]+-[a-z0-9]+$`)
 )
 
-// ValidationError represents a validation failure with a descriptive message.
-type ValidationError struct {
-	Field   string
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return fmt.Sprintf("%s: %s", e.Field, e.Message)
-}
-
 // ValidateProjectCode validates a project code according to business rules.
 //
 // Rules:
@@ -113,15 +132,21 @@ func (e *ValidationError) Error() string {
 func ValidateProjectCode(projectCode string) error {
 	if len(projectCode) != 3 {
 		return &ValidationError{
-			Field:   "projectCode",
-			Message: "must be exactly 3 characters",
+			Field:      "projectCode",
+			Code:       CodeInvalidFormat,
+			Message:    "must be exactly 3 characters",
+			Value:      projectCode,
+			Suggestion: "use a 3-character code such as TPA",
 		}
 	}
 
 	if !projectCodeRegex.MatchString(projectCode) {
 		return &ValidationError{
-			Field:   "projectCode",
-			Message: "must contain only uppercase letters or numbers",
+			Field:      "projectCode",
+			Code:       CodeInvalidFormat,
+			Message:    "must contain only uppercase letters or numbers",
+			Value:      projectCode,
+			Suggestion: fmt.Sprintf("use %q", strings.ToUpper(projectCode)),
 		}
 	}
 
@@ -132,7 +157,8 @@ func ValidateProjectCode(projectCode string) error {
 //
 // Rules:
 //   - Must be a valid email format
-//   - Can optionally include @gmail.com suffix
+//   - Can optionally include its domain (any domain, e.g. @gmail.com or a
+//     Google Workspace / Microsoft 365 domain)
 //
 // Parameters:
 //   - email: The email prefix to validate
@@ -140,10 +166,31 @@ func ValidateProjectCode(projectCode string) error {
 // Returns:
 //   - Error if invalid, nil if valid
 func ValidateEmailPrefix(email string) error {
+	if email == "" {
+		return &ValidationError{
+			Field:      "emailPrefix",
+			Code:       CodeRequired,
+			Message:    "must be a valid email format",
+			Suggestion: "use your mailbox, e.g. user@gmail.com or jane@example.com",
+		}
+	}
+
 	if !emailRegex.MatchString(email) {
+		return &ValidationError{
+			Field:      "emailPrefix",
+			Code:       CodeInvalidFormat,
+			Message:    "must be a valid email format",
+			Value:      email,
+			Suggestion: "use a mailbox such as user or user@example.com",
+		}
+	}
+
+	if _, domain := splitEmail(email); domain != "" && !emailDomainRegex.MatchString(domain) {
 		return &ValidationError{
 			Field:   "emailPrefix",
-			Message: "must be a valid email format",
+			Code:    CodeInvalidFormat,
+			Message: fmt.Sprintf("invalid mail domain %q", domain),
+			Value:   email,
 		}
 	}
 
@@ -164,22 +211,30 @@ func ValidateEmailPrefix(email string) error {
 func ValidateOUID(ouID string) error {
 	if !strings.HasPrefix(ouID, "ou-") {
 		return &ValidationError{
-			Field:   "ouID",
-			Message: "must start with 'ou-'",
+			Field:      "ouID",
+			Code:       CodeInvalidFormat,
+			Message:    "must start with 'ou-'",
+			Value:      ouID,
+			Suggestion: "copy the OU ID from the AWS Organizations console (ou-xxxx-xxxxxxxx)",
 		}
 	}
 
 	if !ouIDRegex.MatchString(ouID) {
 		return &ValidationError{
-			Field:   "ouID",
-			Message: "invalid format (expected: ou-xxxx-xxxxxxxx)",
+			Field:      "ouID",
+			Code:       CodeInvalidFormat,
+			Message:    "invalid format (expected: ou-xxxx-xxxxxxxx)",
+			Value:      ouID,
+			Suggestion: "copy the OU ID from the AWS Organizations console (ou-xxxx-xxxxxxxx)",
 		}
 	}
 
 	return nil
 }
 
-// ValidateEnvironment validates an environment name against supported values.
+// ValidateEnvironment validates an environment name against the built-in
+// environments. Use Config.EnvironmentRegistry to validate against the
+// environments a config declares.
 //
 // Parameters:
 //   - env: The environment to validate
@@ -187,16 +242,7 @@ func ValidateOUID(ouID string) error {
 // Returns:
 //   - Error if invalid, nil if valid
 func ValidateEnvironment(env Environment) error {
-	for _, validEnv := range AllEnvironments() {
-		if env == validEnv {
-			return nil
-		}
-	}
-
-	return &ValidationError{
-		Field:   "environment",
-		Message: fmt.Sprintf("must be one of: %v", AllEnvironments()),
-	}
+	return DefaultEnvironmentRegistry().Validate(env)
 }
 
 // Config represents the configuration for creating multi-account setup.
@@ -204,42 +250,116 @@ func ValidateEnvironment(env Environment) error {
 // This is a value object in Domain-Driven Design terms - it holds
 // the business data needed for account creation.
 type Config struct {
-	ProjectCode  string        // 3-character project identifier
-	EmailPrefix  string        // Email address prefix
-	OUID         string        // Organizational unit ID
-	Environments []Environment // Environments to create (default: dev, staging, prod)
+	ProjectCode      string            // 3-character project identifier
+	EmailPrefix      string            // Email address prefix (plus addressing; optional for other email schemes)
+	OUID             string            // Organizational unit ID
+	Environments     []Environment     // Environments to create (default: all declared environments)
+	EnvironmentSpecs []EnvironmentSpec // Declared environments (default: dev, staging, prod)
+
+	// Tags applied to every account (environment settings can add to them)
+	Tags map[string]string
+
+	// Overrides customizes individual environments (e.g., prod in its own
+	// OU with a higher budget). See SettingsFor for the merge order.
+	Overrides map[Environment]EnvironmentSettings
+
+	// Email selects how account root emails are generated (default: plus
+	// addressing on EmailPrefix)
+	Email EmailStrategy
+
+	// Naming selects how accounts and resources are named (default: the
+	// v1 bash conventions, see NamingPresetV1)
+	Naming NamingConvention
+}
+
+// ResourceNames returns the names of env's account and resources under
+// the config's naming convention. An invalid convention (rejected by
+// Validate) falls back to v1 naming.
+func (c *Config) ResourceNames(env Environment) ResourceNames {
+	naming, err := c.Naming.Resolve()
+	if err != nil {
+		naming = v1Naming
+	}
+	return naming.Names(c.ProjectCode, env)
+}
+
+// EnvironmentRegistry returns the environments this config declares, or the
+// built-in dev/staging/prod registry if it declares none.
+func (c *Config) EnvironmentRegistry() (*EnvironmentRegistry, error) {
+	if len(c.EnvironmentSpecs) == 0 {
+		return DefaultEnvironmentRegistry(), nil
+	}
+	return NewEnvironmentRegistry(c.EnvironmentSpecs...)
+}
+
+// TargetEnvironments returns the environments to provision: Environments
+// if set, otherwise every declared environment in registry order.
+func (c *Config) TargetEnvironments() ([]Environment, error) {
+	if len(c.Environments) > 0 {
+		return c.Environments, nil
+	}
+	registry, err := c.EnvironmentRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return registry.Environments(), nil
 }
 
 // Validate validates all fields in the configuration.
 //
 // Returns:
-//   - Error if any validation fails, nil if all valid
+//   - Error if any validation fails, nil if all valid. The error is a
+//     *ValidationReport listing every problem; errors.As also finds the
+//     first *ValidationError.
 func (c *Config) Validate() error {
-	if err := ValidateProjectCode(c.ProjectCode); err != nil {
-		return err
-	}
+	return c.ValidateAll().Err()
+}
 
-	if err := ValidateEmailPrefix(c.EmailPrefix); err != nil {
-		return err
-	}
+// ValidateAll checks every field and returns all problems found.
+//
+// Checks that depend on a broken field (e.g., generated emails when the
+// email prefix is invalid) are skipped, so each problem is reported once.
+func (c *Config) ValidateAll() *ValidationReport {
+	report := &ValidationReport{}
 
-	if err := ValidateOUID(c.OUID); err != nil {
-		return err
+	report.Add(ValidateProjectCode(c.ProjectCode))
+
+	if c.Email.scheme() == EmailSchemePlus || c.EmailPrefix != "" {
+		report.Add(ValidateEmailPrefix(c.EmailPrefix))
 	}
 
-	// Validate environments (or use default)
-	envs := c.Environments
-	if len(envs) == 0 {
-		envs = AllEnvironments()
+	report.Add(ValidateOUID(c.OUID))
+
+	// Validate environments against the declared (or built-in) registry
+	registry, err := c.EnvironmentRegistry()
+	if err != nil {
+		// Everything below is checked per declared environment
+		report.Add(err)
+		return report
 	}
 
-	for _, env := range envs {
-		if err := ValidateEnvironment(env); err != nil {
-			return err
+	for i, env := range c.Environments {
+		if err := registry.Validate(env); err != nil {
+			report.Add(&ValidationError{
+				Field:      fmt.Sprintf("environments[%d]", i),
+				Code:       CodeUnknown,
+				Message:    err.(*ValidationError).Message,
+				Value:      string(env),
+				Suggestion: "declare it in environmentSpecs or remove it",
+			})
 		}
 	}
 
-	return nil
+	c.Email.validate(report, c.EmailPrefix, registry)
+	c.validateOverrides(report, registry)
+
+	if naming, err := c.Naming.Resolve(); err != nil {
+		report.Add(err)
+	} else {
+		naming.validate(report, c.ProjectCode, registry.Environments())
+	}
+
+	return report
 }
 
 // AccountInfo represents information about a created account.
@@ -250,6 +370,8 @@ type AccountInfo struct {
 	Email       string
 	AccountID   string
 	Environment Environment
+	OUID        string // OU the account was placed in
+	Created     bool   // True if this tool created the account (possibly in an earlier, journaled run), false if it was reused
 }
 
 // GenerateSummary generates a human-readable summary of accounts to be created.
@@ -262,9 +384,13 @@ type AccountInfo struct {
 // Returns:
 //   - Summary string
 func GenerateSummary(config Config) string {
-	envs := config.Environments
-	if len(envs) == 0 {
-		envs = AllEnvironments()
+	registry, err := config.EnvironmentRegistry()
+	if err != nil {
+		registry = DefaultEnvironmentRegistry()
+	}
+	envs, err := config.TargetEnvironments()
+	if err != nil {
+		envs = registry.Environments()
 	}
 
 	var summary strings.Builder
@@ -272,13 +398,31 @@ func GenerateSummary(config Config) string {
 	summary.WriteString("===========================\n")
 	summary.WriteString(fmt.Sprintf("Project Code: %s\n", config.ProjectCode))
 	summary.WriteString(fmt.Sprintf("Email Prefix: %s\n", config.EmailPrefix))
+	if config.Email.scheme() != EmailSchemePlus || config.Email.Domain != "" {
+		summary.WriteString(fmt.Sprintf("Emails:       %s\n", config.Email))
+	}
 	summary.WriteString(fmt.Sprintf("OU ID:        %s\n", config.OUID))
 	summary.WriteString("\nAccounts to be created:\n")
 
+	base := config.SettingsFor("", EnvironmentSettings{})
 	for _, env := range envs {
-		name := GenerateAccountName(config.ProjectCode, env)
-		email := GenerateAccountEmail(config.EmailPrefix, config.ProjectCode, env)
-		summary.WriteString(fmt.Sprintf("  %-15s -> %s\n", name, email))
+		name := config.ResourceNames(env).Account
+		settings := config.SettingsFor(env, EnvironmentSettings{})
+		email := settings.Email
+
+		label := fmt.Sprintf("(%s)", env)
+		if spec, ok := registry.Lookup(env); ok {
+			label = fmt.Sprintf("(%s)", spec.Label())
+			if spec.Production {
+				label += " [production]"
+			}
+		}
+		summary.WriteString(fmt.Sprintf("  %-15s -> %s %s\n", name, email, label))
+
+		base.Email = settings.Email
+		if details := settings.describe(base); len(details) > 0 {
+			summary.WriteString(fmt.Sprintf("  %-15s    %s\n", "", strings.Join(details, "; ")))
+		}
 	}
 
 	return summary.String()
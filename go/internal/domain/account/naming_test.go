@@ -348,6 +348,23 @@ func TestGetOrganizationAccessRoleName(t *testing.T) {
 	}
 }
 
+func TestGenerateResourceNames(t *testing.T) {
+	// These must match the v1 setup-billing-alerts.sh names so v2 finds
+	// resources created by the bash scripts.
+	if got := GenerateBudgetName("TPA", EnvironmentDev); got != "TPA-dev-monthly-budget" {
+		t.Errorf("GenerateBudgetName() = %v, want TPA-dev-monthly-budget", got)
+	}
+	if got := GenerateBillingAlarmName("TPA", EnvironmentStaging); got != "TPA-staging-billing-alarm" {
+		t.Errorf("GenerateBillingAlarmName() = %v, want TPA-staging-billing-alarm", got)
+	}
+	if got := GenerateBillingTopicName("TPA", EnvironmentProd); got != "TPA-prod-billing-alerts" {
+		t.Errorf("GenerateBillingTopicName() = %v, want TPA-prod-billing-alerts", got)
+	}
+	if got := GetGitHubActionsRoleName(); got != "GitHubActionsDeployRole" {
+		t.Errorf("GetGitHubActionsRoleName() = %v, want GitHubActionsDeployRole", got)
+	}
+}
+
 // Benchmark tests to ensure performance is good
 func BenchmarkGenerateAccountName(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -2,11 +2,118 @@ package account
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/progress"
 	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
 )
 
+// ErrorMode controls how CreateAllAccounts reacts when an environment fails.
+type ErrorMode int
+
+const (
+	// FailFast stops scheduling new environments after the first failure and
+	// cancels the ones still in flight. This is the default.
+	FailFast ErrorMode = iota
+
+	// CollectAllErrors lets every environment run to completion and reports
+	// all failures together (joined with errors.Join).
+	CollectAllErrors
+)
+
+// Option configures account orchestration.
+type Option func(*options)
+
+type options struct {
+	concurrency int
+	errorMode   ErrorMode
+	journal     *journal.Journal
+	progress    *progress.Tracker
+	preflight   bool
+	recorded    map[Environment]RecordedAccount
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		concurrency: 1,
+		errorMode:   FailFast,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithConcurrency sets how many environments are provisioned in parallel.
+//
+// AWS Organizations takes minutes to create each account, so provisioning
+// dev/staging/prod concurrently cuts the wall-clock time considerably.
+// Values below 1 are treated as 1 (sequential, the default).
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.concurrency = n
+	}
+}
+
+// WithErrorMode selects fail-fast (default) or collect-all-errors semantics.
+func WithErrorMode(mode ErrorMode) Option {
+	return func(o *options) {
+		o.errorMode = mode
+	}
+}
+
+// WithJournal records each completed step in j and skips steps that a
+// previous run already recorded.
+//
+// Accounts marked ready are reused without touching AWS. Accounts whose
+// creation was requested but never confirmed are resumed by waiting on the
+// recorded account ID rather than issuing a second CreateAccount.
+func WithJournal(j *journal.Journal) Option {
+	return func(o *options) {
+		o.journal = j
+	}
+}
+
+// WithProgress reports each step (create, wait, place, tag) to reporter as
+// it starts and ends, plus a polling heartbeat while AWS creates the
+// account. Accounts that already exist are reported as reused.
+func WithProgress(reporter ports.ProgressReporter) Option {
+	return func(o *options) {
+		o.progress = progress.New(reporter)
+	}
+}
+
+// WithPreflight runs Preflight before creating anything and stops with the
+// *PreflightReport as the error if it finds a blocking issue.
+func WithPreflight() Option {
+	return func(o *options) {
+		o.preflight = true
+	}
+}
+
+// RecordedAccount is the account project state records for an environment.
+type RecordedAccount struct {
+	AccountID string
+	Name      string // Actual account name; may differ from the naming convention (adopted accounts)
+}
+
+// WithRecordedAccounts reuses the recorded account of each environment in
+// accounts instead of looking one up by its convention name, so accounts
+// adopted under another name are found without a journal. A recorded
+// account that no longer exists fails the environment rather than being
+// replaced. Journal entries take precedence.
+func WithRecordedAccounts(accounts map[Environment]RecordedAccount) Option {
+	return func(o *options) {
+		o.recorded = accounts
+	}
+}
+
 // CreateAllAccounts orchestrates the creation of AWS accounts for all environments.
 //
 // This is DOMAIN LOGIC - pure business orchestration.
@@ -18,77 +125,131 @@ import (
 //
 // Hexagonal Architecture for TESTING, not for false multi-cloud abstraction.
 //
+// Environments are provisioned sequentially unless WithConcurrency is given.
+// Either way the result is ordered by environment, not by completion time.
+//
+// Every account, new or reused, ends up directly in its environment's OU
+// (config.OUID unless overridden): accounts found elsewhere (including the
+// root, where AWS creates them) are moved. An existing account that isn't
+// ACTIVE (e.g., closed by DecommissionProject) is never reused: the
+// environment fails with an *InactiveAccountError.
+//
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
 //   - aws: AWS client implementation (real AWS SDK or mock for testing)
 //   - config: Business configuration (validated)
+//   - opts: Optional orchestration settings (concurrency, error mode, journal, preflight, progress)
 //
 // Returns:
 //   - Slice of AccountInfo for created AWS accounts
-//   - Error if any account creation fails
+//   - Error if any account creation fails. AWS failures are reported as
+//     *OperationError. In CollectAllErrors mode the accounts that did
+//     succeed are returned alongside the joined error.
 func CreateAllAccounts(
 	ctx context.Context,
 	aws ports.AWSClient,
 	config Config,
+	opts ...Option,
 ) ([]AccountInfo, error) {
 	// Validate configuration (business rule)
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Determine environments (business rule: default to all)
-	envs := config.Environments
-	if len(envs) == 0 {
-		envs = AllEnvironments()
+	o := newOptions(opts)
+
+	// Determine environments (business rule: default to all declared)
+	envs, err := config.TargetEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Create AWS accounts for each environment (business orchestration)
-	var accounts []AccountInfo
+	if o.preflight {
+		report, err := Preflight(ctx, aws, config)
+		if err != nil {
+			return nil, err
+		}
+		if err := report.Err(); err != nil {
+			return nil, err
+		}
+	}
 
-	for _, env := range envs {
-		// Apply business rules (naming conventions)
-		accountName := GenerateAccountName(config.ProjectCode, env)
-		accountEmail := GenerateAccountEmail(config.EmailPrefix, config.ProjectCode, env)
-		roleName := GetOrganizationAccessRoleName()
+	// Results are indexed by environment position so ordering stays
+	// deterministic regardless of which goroutine finishes first.
+	results := make([]AccountInfo, len(envs))
+	errs := make([]error, len(envs))
+	succeeded := make([]bool, len(envs))
 
-		// Check if AWS account already exists
-		existingID, err := aws.GetAccountByName(ctx, accountName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check existing AWS account %s: %w", accountName, err)
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		firstErr error
+		sem      = make(chan struct{}, o.concurrency)
+		started  int
+	)
+
+schedule:
+	for i, env := range envs {
+		if runCtx.Err() != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-runCtx.Done():
+			break schedule
 		}
 
-		var accountID string
-		if existingID != "" {
-			// AWS account exists - reuse it
-			accountID = existingID
-		} else {
-			// Create new AWS account
-			accountID, err = aws.CreateAccount(ctx, ports.AWSCreateAccountRequest{
-				Name:      accountName,
-				Email:     accountEmail,
-				OrgUnitID: config.OUID,
-				RoleName:  roleName,
-			})
+		started++
+		wg.Add(1)
+		go func(i int, env Environment) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			info, err := provisionAccount(runCtx, aws, config, env, o)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create AWS account %s: %w", accountName, err)
+				errs[i] = err
+				if o.errorMode == FailFast {
+					failOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+				return
 			}
+			results[i] = info
+			succeeded[i] = true
+		}(i, env)
+	}
+	wg.Wait()
 
-			// Wait for AWS account to be ready (Organizations is async)
-			if err := aws.WaitForAccountCreation(ctx, accountID); err != nil {
-				return nil, fmt.Errorf("AWS account %s creation timed out: %w", accountName, err)
-			}
+	if o.errorMode == FailFast {
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
+		return results, nil
+	}
 
-		// Collect account info
-		accounts = append(accounts, AccountInfo{
-			Name:        accountName,
-			Email:       accountEmail,
-			AccountID:   accountID,
-			Environment: env,
-		})
+	var accounts []AccountInfo
+	var failures []error
+	for i := range envs {
+		switch {
+		case errs[i] != nil:
+			failures = append(failures, errs[i])
+		case succeeded[i]:
+			accounts = append(accounts, results[i])
+		}
+	}
+	if started < len(envs) {
+		failures = append(failures, ctx.Err())
 	}
 
-	return accounts, nil
+	return accounts, errors.Join(failures...)
 }
 
 // CreateSingleAccount creates an AWS account for a specific environment.
@@ -101,6 +262,7 @@ func CreateAllAccounts(
 //   - aws: AWS client implementation
 //   - config: Business configuration
 //   - env: Specific environment to create
+//   - opts: Optional orchestration settings (only WithJournal and WithProgress apply)
 //
 // Returns:
 //   - AccountInfo for the created AWS account
@@ -110,53 +272,214 @@ func CreateSingleAccount(
 	aws ports.AWSClient,
 	config Config,
 	env Environment,
+	opts ...Option,
 ) (*AccountInfo, error) {
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Validate environment
-	if err := ValidateEnvironment(env); err != nil {
+	// Validate environment against the declared environments
+	registry, err := config.EnvironmentRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := registry.Validate(env); err != nil {
 		return nil, err
 	}
 
-	// Apply business rules
-	accountName := GenerateAccountName(config.ProjectCode, env)
-	accountEmail := GenerateAccountEmail(config.EmailPrefix, config.ProjectCode, env)
-	roleName := GetOrganizationAccessRoleName()
+	info, err := provisionAccount(ctx, aws, config, env, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// provisionAccount finds or creates the AWS account for one environment.
+//
+// Shared by CreateAllAccounts and CreateSingleAccount. Safe to call from
+// multiple goroutines as long as the AWS client is.
+func provisionAccount(
+	ctx context.Context,
+	aws ports.AWSClient,
+	config Config,
+	env Environment,
+	o options,
+) (AccountInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return AccountInfo{}, err
+	}
+
+	// Apply business rules (naming conventions, per-environment overrides).
+	// An account recorded under another name (adopted) keeps that name.
+	accountName := config.ResourceNames(env).Account
+	if recorded, ok := o.recorded[env]; ok && recorded.Name != "" {
+		accountName = recorded.Name
+	}
+	if entry, ok := o.journal.Lookup(string(env), journal.StepAccountReady); ok && entry.ResourceID != "" {
+		accountName = entry.ResourceID
+	}
+	settings := config.SettingsFor(env, EnvironmentSettings{})
+
+	info := AccountInfo{
+		Name:        accountName,
+		Email:       settings.Email,
+		Environment: env,
+		OUID:        settings.OUID,
+	}
 
-	// Check if AWS account already exists
-	existingID, err := aws.GetAccountByName(ctx, accountName)
+	accountID, created, err := ensureAccount(ctx, aws, env, accountName, settings, o)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check existing AWS account: %w", err)
+		return AccountInfo{}, err
+	}
+
+	if err := placeAccount(ctx, aws, env, accountName, accountID, settings.OUID, o); err != nil {
+		return AccountInfo{}, err
+	}
+
+	// Tagging is idempotent, so reused accounts are simply re-tagged
+	if len(settings.Tags) > 0 {
+		step := o.progress.Start(string(env), string(PhaseTag), accountName, accountID)
+		if err := aws.TagAccount(ctx, accountID, settings.Tags); err != nil {
+			return AccountInfo{}, step.End(NewOperationError(env, accountName, accountID, PhaseTag, err))
+		}
+		step.Succeed("")
+	}
+
+	info.AccountID = accountID
+	info.Created = created
+	return info, nil
+}
+
+// ensureAccount finds or creates the environment's account and waits until
+// it is ready. It reports whether this run (or the journaled run it
+// resumes) created the account.
+func ensureAccount(
+	ctx context.Context,
+	aws ports.AWSClient,
+	env Environment,
+	accountName string,
+	settings EnvironmentSettings,
+	o options,
+) (string, bool, error) {
+	// A previous run already finished this environment. The account still
+	// counts as created if that run requested it, so rollback treats it
+	// the same as on the first run.
+	if entry, ok := o.journal.Lookup(string(env), journal.StepAccountReady); ok {
+		o.progress.Reused(string(env), string(PhaseCreate), accountName, entry.AccountID)
+		requested, created := o.journal.Lookup(string(env), journal.StepAccountCreated)
+		return entry.AccountID, created && requested.AccountID == entry.AccountID, nil
 	}
 
 	var accountID string
-	if existingID != "" {
-		accountID = existingID
+	if entry, ok := o.journal.Lookup(string(env), journal.StepAccountCreated); ok {
+		// A previous run requested the account but never saw it finish -
+		// resume waiting instead of issuing a duplicate CreateAccount
+		accountID = entry.AccountID
 	} else {
+		// Check if AWS account already exists: the recorded one, or one
+		// named by the convention
+		var err error
+		existingID := o.recorded[env].AccountID
+		if existingID == "" {
+			existingID, err = aws.GetAccountByName(ctx, accountName)
+			if err != nil {
+				return "", false, NewOperationError(env, accountName, "", PhaseLookup, err)
+			}
+		}
+
+		if existingID != "" {
+			// Closed accounts keep their name, but can't be reused
+			existing, err := aws.DescribeAccount(ctx, existingID)
+			if err != nil {
+				return "", false, NewOperationError(env, accountName, existingID, PhaseLookup, err)
+			}
+			if existing == nil && o.recorded[env].AccountID == existingID {
+				return "", false, NewOperationError(env, accountName, existingID, PhaseLookup,
+					fmt.Errorf("recorded account %s not found in organization", existingID))
+			}
+			if existing != nil && existing.Status != "" && existing.Status != ports.AccountStatusActive {
+				return "", false, NewOperationError(env, accountName, existingID, PhaseLookup,
+					&InactiveAccountError{AccountID: existingID, Status: existing.Status})
+			}
+
+			// AWS account exists - reuse it
+			o.progress.Reused(string(env), string(PhaseCreate), accountName, existingID)
+			if err := o.journal.Record(ctx, string(env), journal.StepAccountReady, existingID, accountName); err != nil {
+				return "", false, err
+			}
+			return existingID, false, nil
+		}
+
 		// Create new AWS account
+		step := o.progress.Start(string(env), string(PhaseCreate), accountName, "")
 		accountID, err = aws.CreateAccount(ctx, ports.AWSCreateAccountRequest{
 			Name:      accountName,
-			Email:     accountEmail,
-			OrgUnitID: config.OUID,
-			RoleName:  roleName,
+			Email:     settings.Email,
+			OrgUnitID: settings.OUID,
+			RoleName:  GetOrganizationAccessRoleName(),
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to create AWS account: %w", err)
+			return "", false, step.End(NewOperationError(env, accountName, "", PhaseCreate, err))
 		}
+		step.Succeed(accountID)
 
-		// Wait for AWS account to be ready
-		if err := aws.WaitForAccountCreation(ctx, accountID); err != nil {
-			return nil, fmt.Errorf("AWS account creation timed out: %w", err)
+		if err := o.journal.Record(ctx, string(env), journal.StepAccountCreated, accountID, accountName); err != nil {
+			return "", false, err
 		}
 	}
 
-	return &AccountInfo{
-		Name:        accountName,
-		Email:       accountEmail,
-		AccountID:   accountID,
-		Environment: env,
-	}, nil
+	// Wait for AWS account to be ready (Organizations is async)
+	step := o.progress.Start(string(env), string(PhaseWait), accountName, accountID)
+	stop := step.Wait()
+	err := aws.WaitForAccountCreation(ctx, accountID)
+	stop()
+	if err != nil {
+		return "", false, step.End(NewOperationError(env, accountName, accountID, PhaseWait, err))
+	}
+	step.Succeed("")
+
+	if err := o.journal.Record(ctx, string(env), journal.StepAccountReady, accountID, accountName); err != nil {
+		return "", false, err
+	}
+	return accountID, true, nil
+}
+
+// placeAccount makes sure the account sits directly in ouID.
+//
+// This is DOMAIN LOGIC: AWS Organizations always creates accounts in the
+// organization root, and reused accounts may have been moved by hand, so
+// placement is verified for every account instead of trusting
+// AWSCreateAccountRequest.OrgUnitID. Mirrors the MoveAccount step in
+// create-project-accounts.sh.
+func placeAccount(
+	ctx context.Context,
+	aws ports.AWSClient,
+	env Environment,
+	accountName string,
+	accountID string,
+	ouID string,
+	o options,
+) error {
+	// A placement recorded for another OU (the config changed) is redone
+	if entry, ok := o.journal.Lookup(string(env), journal.StepAccountPlaced); ok && entry.AccountID == accountID && entry.ResourceID == ouID {
+		o.progress.Reused(string(env), string(PhasePlace), accountName, accountID)
+		return nil
+	}
+
+	step := o.progress.Start(string(env), string(PhasePlace), accountName, accountID)
+	parentID, err := aws.GetAccountParent(ctx, accountID)
+	if err != nil {
+		return step.End(NewOperationError(env, accountName, accountID, PhasePlace, err))
+	}
+
+	if parentID != ouID {
+		if err := aws.MoveAccount(ctx, accountID, parentID, ouID); err != nil {
+			return step.End(NewOperationError(env, accountName, accountID, PhasePlace, err))
+		}
+	}
+	step.Succeed("")
+
+	return o.journal.Record(ctx, string(env), journal.StepAccountPlaced, accountID, ouID)
 }
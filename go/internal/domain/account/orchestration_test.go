@@ -2,9 +2,15 @@ package account
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
 )
 
 // This test demonstrates Hexagonal Architecture for TESTING (not multi-cloud):
@@ -151,10 +157,49 @@ func TestCreateAllAccountsWithExistingAWSAccount(t *testing.T) {
 				t.Errorf("Dev account should reuse existing ID %s, got %s",
 					devAccountID, account.AccountID)
 			}
+			if account.Created {
+				t.Error("Reused dev account should not be marked as created")
+			}
+		} else if !account.Created {
+			t.Errorf("%s account should be marked as created", account.Environment)
 		}
 	}
 }
 
+func TestCreateAllAccountsReusesRecordedAccounts(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := Config{ProjectCode: "TPA", EmailPrefix: "user", OUID: "ou-813y-8teevv2l"}
+
+	// Adopted under a name outside the convention
+	legacyID, err := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "legacy-dev", Email: "legacy-dev@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err := CreateAllAccounts(ctx, mockAWS, config, WithRecordedAccounts(map[Environment]RecordedAccount{
+		EnvironmentDev: {AccountID: legacyID, Name: "legacy-dev"},
+	}))
+	if err != nil {
+		t.Fatalf("CreateAllAccounts() failed: %v", err)
+	}
+	if dev := accounts[0]; dev.AccountID != legacyID || dev.Name != "legacy-dev" || dev.Created {
+		t.Errorf("dev = %+v, want recorded account %s reused under its own name", dev, legacyID)
+	}
+	if n := mockAWS.Operations().Method("CreateAccount").Count(); n != 3 {
+		t.Errorf("CreateAccount calls = %d, want legacy-dev plus staging and prod", n)
+	}
+
+	// A recorded account that is gone is an error, not a reason to create another
+	_, err = CreateAllAccounts(ctx, mock.NewAWSClient(), config, WithRecordedAccounts(map[Environment]RecordedAccount{
+		EnvironmentDev: {AccountID: "555555555555"},
+	}))
+	var opErr *OperationError
+	if !errors.As(err, &opErr) || opErr.Environment != EnvironmentDev || opErr.Phase != PhaseLookup {
+		t.Errorf("CreateAllAccounts() = %v, want a dev lookup *OperationError", err)
+	}
+}
+
 func TestCreateSingleAccount(t *testing.T) {
 	mockAWS := mock.NewAWSClient()
 
@@ -236,7 +281,648 @@ func TestCreateSingleAccountInvalidEnvironment(t *testing.T) {
 	}
 }
 
+// slowAWS wraps the mock to make WaitForAccountCreation take a little time
+// and to record how many waits were in flight at once.
+type slowAWS struct {
+	*mock.AWSClient
+	delay time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *slowAWS) WaitForAccountCreation(ctx context.Context, accountID string) error {
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.AWSClient.WaitForAccountCreation(ctx, accountID)
+}
+
+// failCreating makes the mock's CreateAccount fail for the named accounts.
+func failCreating(aws *mock.AWSClient, names ...string) {
+	for _, name := range names {
+		aws.AddFault("CreateAccount", mock.FailFor(name, errors.New("simulated CreateAccount failure")))
+	}
+}
+
+func TestCreateAllAccountsConcurrent(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		wantMax     int
+	}{
+		{name: "sequential by default", concurrency: 0, wantMax: 1},
+		{name: "bounded to two workers", concurrency: 2, wantMax: 2},
+		{name: "one worker per environment", concurrency: 3, wantMax: 3},
+		{name: "more workers than environments", concurrency: 10, wantMax: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aws := &slowAWS{AWSClient: mock.NewAWSClient(), delay: 20 * time.Millisecond}
+			config := Config{
+				ProjectCode: "TPA",
+				EmailPrefix: "user",
+				OUID:        "ou-813y-8teevv2l",
+			}
+
+			var opts []Option
+			if tt.concurrency > 0 {
+				opts = append(opts, WithConcurrency(tt.concurrency))
+			}
+
+			accounts, err := CreateAllAccounts(context.Background(), aws, config, opts...)
+			if err != nil {
+				t.Fatalf("CreateAllAccounts() failed: %v", err)
+			}
+
+			// Results must be ordered by environment, not completion time
+			want := AllEnvironments()
+			if len(accounts) != len(want) {
+				t.Fatalf("Expected %d accounts, got %d", len(want), len(accounts))
+			}
+			for i, account := range accounts {
+				if account.Environment != want[i] {
+					t.Errorf("accounts[%d].Environment = %s, want %s", i, account.Environment, want[i])
+				}
+			}
+
+			if aws.maxInFlight > tt.wantMax {
+				t.Errorf("max in-flight waits = %d, must not exceed %d", aws.maxInFlight, tt.wantMax)
+			}
+			if aws.maxInFlight != tt.wantMax {
+				t.Errorf("max in-flight waits = %d, want %d", aws.maxInFlight, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestCreateAllAccountsFailFast(t *testing.T) {
+	aws := mock.NewAWSClient()
+	failCreating(aws, "TPA_DEV")
+	config := Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+	}
+
+	accounts, err := CreateAllAccounts(context.Background(), aws, config)
+	if err == nil {
+		t.Fatal("CreateAllAccounts() should have failed")
+	}
+	if accounts != nil {
+		t.Errorf("Fail-fast mode should not return accounts, got %d", len(accounts))
+	}
+
+	// Sequential fail-fast must not touch later environments
+	for _, op := range aws.GetOperations() {
+		if contains(op, "TPA_STAGING") || contains(op, "TPA_PROD") {
+			t.Errorf("Unexpected operation after failure: %s", op)
+		}
+	}
+}
+
+func TestCreateAllAccountsCollectAllErrors(t *testing.T) {
+	aws := mock.NewAWSClient()
+	failCreating(aws, "TPA_DEV", "TPA_PROD")
+	config := Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+	}
+
+	accounts, err := CreateAllAccounts(context.Background(), aws, config,
+		WithConcurrency(3),
+		WithErrorMode(CollectAllErrors),
+	)
+	if err == nil {
+		t.Fatal("CreateAllAccounts() should have failed")
+	}
+
+	for _, name := range []string{"TPA_DEV", "TPA_PROD"} {
+		if !contains(err.Error(), name) {
+			t.Errorf("Joined error should mention %s, got: %v", name, err)
+		}
+	}
+
+	// The healthy environment still completes
+	if len(accounts) != 1 || accounts[0].Environment != EnvironmentStaging {
+		t.Errorf("Expected only the staging account, got %+v", accounts)
+	}
+}
+
+func TestCreateAllAccountsCancelledContext(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	config := Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, mode := range []ErrorMode{FailFast, CollectAllErrors} {
+		_, err := CreateAllAccounts(ctx, mockAWS, config,
+			WithConcurrency(2),
+			WithErrorMode(mode),
+		)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("mode %d: expected context.Canceled, got %v", mode, err)
+		}
+	}
+
+	if ops := mockAWS.GetOperations(); len(ops) != 0 {
+		t.Errorf("No AWS operations expected after cancellation, got %v", ops)
+	}
+}
+
+func TestCreateAllAccountsWaitTimeout(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.AddFault("WaitForAccountCreation", mock.Hang())
+	config := Config{
+		ProjectCode:  "TPA",
+		EmailPrefix:  "user",
+		OUID:         "ou-813y-8teevv2l",
+		Environments: []Environment{EnvironmentDev, EnvironmentStaging},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := CreateAllAccounts(ctx, mockAWS, config, WithConcurrency(2), WithErrorMode(CollectAllErrors))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CreateAllAccounts() error = %v, want DeadlineExceeded", err)
+	}
+
+	var opErr *OperationError
+	if !errors.As(err, &opErr) || opErr.Phase != PhaseWait || opErr.AccountID == "" {
+		t.Errorf("error = %v, want a wait-phase OperationError with the account ID", err)
+	}
+
+	// Both accounts were requested; neither got past the wait
+	ops := mockAWS.Operations()
+	if got := ops.Method("CreateAccount").Count(); got != 2 {
+		t.Errorf("CreateAccount calls = %d, want 2", got)
+	}
+	if got := ops.Method("MoveAccount", "TagAccount").Count(); got != 0 {
+		t.Errorf("Expected no placement or tagging after timeout, got %v", ops.Strings())
+	}
+}
+
+func TestCreateAllAccountsPollsAsynchronousCreation(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	clock := mock.NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	mockAWS.SetClock(clock)
+	mockAWS.SimulateAccountCreation(mock.AccountCreation{Duration: 3 * time.Minute, PollInterval: time.Minute})
+	mockAWS.FailAccountCreation("TPA_PROD", ports.CodeEmailAlreadyExists)
+	config := Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+	}
+
+	accounts, err := CreateAllAccounts(context.Background(), mockAWS, config, WithErrorMode(CollectAllErrors))
+	if !errors.Is(err, ports.ErrEmailAlreadyExists) {
+		t.Fatalf("CreateAllAccounts() error = %v, want ErrEmailAlreadyExists", err)
+	}
+	var opErr *OperationError
+	if !errors.As(err, &opErr) || opErr.Phase != PhaseWait || opErr.AccountName != "TPA_PROD" {
+		t.Errorf("error = %v, want prod failing while waiting", err)
+	}
+	if len(accounts) != 2 {
+		t.Fatalf("Expected dev and staging, got %+v", accounts)
+	}
+
+	// Each wait polled until its request left IN_PROGRESS
+	ops := mockAWS.Operations()
+	for _, acc := range accounts {
+		if got := ops.ForAccount(acc.AccountID).Method("DescribeCreateAccountStatus").Count(); got != 4 {
+			t.Errorf("%s status checks = %d, want 4", acc.Environment, got)
+		}
+		if err := ops.ForAccount(acc.AccountID).InOrder("CreateAccount", "WaitForAccountCreation", "MoveAccount"); err != nil {
+			t.Errorf("%s: %v", acc.Environment, err)
+		}
+	}
+}
+
+func TestCreateAllAccountsResumesFromJournal(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewJournalStore()
+	mockAWS := mock.NewAWSClient()
+	config := Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+	}
+
+	// First run: staging fails after dev was created
+	firstRun, err := journal.Open(ctx, store, "TPA")
+	if err != nil {
+		t.Fatalf("journal.Open() failed: %v", err)
+	}
+	failCreating(mockAWS, "TPA_STAGING")
+	if _, err := CreateAllAccounts(ctx, mockAWS, config, WithJournal(firstRun)); err == nil {
+		t.Fatal("First run should fail on staging")
+	}
+	mockAWS.ClearFaults()
+
+	devEntry, ok := firstRun.Lookup(string(EnvironmentDev), journal.StepAccountReady)
+	if !ok {
+		t.Fatal("Dev account should be journaled as ready")
+	}
+
+	// Second run: same store, healthy AWS
+	opsBefore := len(mockAWS.GetOperations())
+	secondRun, err := journal.Open(ctx, store, "TPA")
+	if err != nil {
+		t.Fatalf("journal.Open() failed: %v", err)
+	}
+	accounts, err := CreateAllAccounts(ctx, mockAWS, config, WithJournal(secondRun))
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+
+	if accounts[0].AccountID != devEntry.AccountID {
+		t.Errorf("Dev account ID = %s, want journaled %s", accounts[0].AccountID, devEntry.AccountID)
+	}
+
+	// Dev was completed, so the second run must not touch it at all
+	for _, op := range mockAWS.GetOperations()[opsBefore:] {
+		if contains(op, "TPA_DEV") || contains(op, devEntry.AccountID) {
+			t.Errorf("Second run should skip dev, but performed: %s", op)
+		}
+	}
+
+	for _, env := range AllEnvironments() {
+		if _, ok := secondRun.Lookup(string(env), journal.StepAccountReady); !ok {
+			t.Errorf("Environment %s should be journaled as ready", env)
+		}
+	}
+}
+
+func TestCreateAllAccountsResumesInFlightCreation(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewJournalStore()
+	mockAWS := mock.NewAWSClient()
+
+	// A previous run issued CreateAccount for dev but crashed before the wait
+	devID, err := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{
+		Name:  "TPA_DEV",
+		Email: "user+tpa-dev@gmail.com",
+	})
+	if err != nil {
+		t.Fatalf("Failed to pre-create AWS account: %v", err)
+	}
+	previous, _ := journal.Open(ctx, store, "TPA")
+	if err := previous.Record(ctx, string(EnvironmentDev), journal.StepAccountCreated, devID, "TPA_DEV"); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+
+	mark := mockAWS.Mark()
+	j, _ := journal.Open(ctx, store, "TPA")
+	config := Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+	}
+	account, err := CreateSingleAccount(ctx, mockAWS, config, EnvironmentDev, WithJournal(j))
+	if err != nil {
+		t.Fatalf("CreateSingleAccount() failed: %v", err)
+	}
+	if account.AccountID != devID {
+		t.Errorf("AccountID = %s, want resumed %s", account.AccountID, devID)
+	}
+
+	// Resumed wait, then placement - no new lookup or CreateAccount
+	ops := mockAWS.Operations().Since(mark)
+	if len(ops) == 0 || ops[0].Method != "WaitForAccountCreation" || ops[0].AccountID != devID {
+		t.Errorf("Expected a resumed wait first, got %v", ops.Strings())
+	}
+	if n := ops.Method("CreateAccount", "GetAccountByName").Count(); n != 0 {
+		t.Errorf("Unexpected lookups or creates on resume: %v", ops.Strings())
+	}
+}
+
+func TestCreateAllAccountsPlacesAccountsInOU(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := Config{
+		ProjectCode:  "TPA",
+		EmailPrefix:  "user",
+		OUID:         "ou-813y-8teevv2l",
+		Environments: []Environment{EnvironmentDev, EnvironmentStaging, EnvironmentProd},
+	}
+
+	// staging exists in another OU, prod already sits in the right one
+	if err := mockAWS.CreateOrganizationalUnit("ou-813y-legacy01", mock.RootID); err != nil {
+		t.Fatalf("CreateOrganizationalUnit() failed: %v", err)
+	}
+	stagingID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_STAGING"})
+	if err := mockAWS.MoveAccount(ctx, stagingID, mock.RootID, "ou-813y-legacy01"); err != nil {
+		t.Fatalf("MoveAccount() failed: %v", err)
+	}
+	prodID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_PROD"})
+	if err := mockAWS.MoveAccount(ctx, prodID, mock.RootID, config.OUID); err != nil {
+		t.Fatalf("MoveAccount() failed: %v", err)
+	}
+
+	mark := mockAWS.Mark()
+	accounts, err := CreateAllAccounts(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("CreateAllAccounts() failed: %v", err)
+	}
+
+	for _, acc := range accounts {
+		parent, err := mockAWS.GetAccountParent(ctx, acc.AccountID)
+		if err != nil {
+			t.Fatalf("GetAccountParent() failed: %v", err)
+		}
+		if parent != config.OUID {
+			t.Errorf("%s parent = %s, want %s", acc.Environment, parent, config.OUID)
+		}
+	}
+
+	// New dev account is moved out of the root, staging out of the legacy OU,
+	// prod is left alone
+	moves := mockAWS.Operations().Since(mark).Method("MoveAccount")
+	if moves.Count() != 2 {
+		t.Fatalf("Expected 2 moves, got %v", moves.Strings())
+	}
+	if prodMoves := moves.ForAccount(prodID); prodMoves.Count() != 0 {
+		t.Errorf("prod is already placed, should not move: %v", prodMoves.Strings())
+	}
+}
+
+func TestCreateAllAccountsPlacementFailure(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("MoveAccount", ports.NewAWSError(ports.CodeConcurrentModification, "busy"))
+	config := Config{
+		ProjectCode:  "TPA",
+		EmailPrefix:  "user",
+		OUID:         "ou-813y-8teevv2l",
+		Environments: []Environment{EnvironmentDev},
+	}
+
+	_, err := CreateAllAccounts(context.Background(), mockAWS, config)
+
+	var opErr *OperationError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("Expected *OperationError, got %v", err)
+	}
+	if opErr.Phase != PhasePlace || opErr.AccountID == "" || !opErr.Retryable {
+		t.Errorf("Unexpected error details: %+v", opErr)
+	}
+}
+
+func TestCreateAllAccountsSkipsJournaledPlacement(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	store := mock.NewJournalStore()
+	config := Config{
+		ProjectCode:  "TPA",
+		EmailPrefix:  "user",
+		OUID:         "ou-813y-8teevv2l",
+		Environments: []Environment{EnvironmentDev},
+	}
+
+	j, _ := journal.Open(ctx, store, "TPA")
+	if _, err := CreateAllAccounts(ctx, mockAWS, config, WithJournal(j)); err != nil {
+		t.Fatalf("CreateAllAccounts() failed: %v", err)
+	}
+	if _, ok := j.Lookup(string(EnvironmentDev), journal.StepAccountPlaced); !ok {
+		t.Fatal("Expected placement to be journaled")
+	}
+
+	opsBefore := len(mockAWS.GetOperations())
+	resumed, _ := journal.Open(ctx, store, "TPA")
+	if _, err := CreateAllAccounts(ctx, mockAWS, config, WithJournal(resumed)); err != nil {
+		t.Fatalf("CreateAllAccounts() resume failed: %v", err)
+	}
+	if ops := mockAWS.GetOperations()[opsBefore:]; len(ops) != 0 {
+		t.Errorf("Resumed run should not call AWS, got %v", ops)
+	}
+}
+
+func TestCreateAllAccountsMovesWhenOUChanged(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	store := mock.NewJournalStore()
+	config := Config{
+		ProjectCode:  "TPA",
+		EmailPrefix:  "user",
+		OUID:         "ou-813y-8teevv2l",
+		Environments: []Environment{EnvironmentDev},
+	}
+
+	j, _ := journal.Open(ctx, store, "TPA")
+	accounts, err := CreateAllAccounts(ctx, mockAWS, config, WithJournal(j))
+	if err != nil {
+		t.Fatalf("CreateAllAccounts() failed: %v", err)
+	}
+	devID := accounts[0].AccountID
+
+	// The project moves to another OU between runs
+	config.OUID = "ou-813y-newplace"
+	mark := mockAWS.Mark()
+	resumed, _ := journal.Open(ctx, store, "TPA")
+	if _, err := CreateAllAccounts(ctx, mockAWS, config, WithJournal(resumed)); err != nil {
+		t.Fatalf("CreateAllAccounts() resume failed: %v", err)
+	}
+
+	if moves := mockAWS.Operations().Since(mark).Method("MoveAccount"); moves.Count() != 1 {
+		t.Errorf("Resumed run should move the account once, got %v", moves)
+	}
+	if parent, _ := mockAWS.GetAccountParent(ctx, devID); parent != "ou-813y-newplace" {
+		t.Errorf("Parent = %s, want ou-813y-newplace", parent)
+	}
+	if entry, _ := resumed.Lookup(string(EnvironmentDev), journal.StepAccountPlaced); entry.ResourceID != "ou-813y-newplace" {
+		t.Errorf("Journaled placement = %s, want the new OU", entry.ResourceID)
+	}
+}
+
 // Helper function
+func TestCreateAllAccountsTypedErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		sentinel  error
+		phase     Phase
+		code      string
+		retryable bool
+	}{
+		{
+			name:     "email already in use",
+			method:   "CreateAccount",
+			err:      ports.NewAWSError(ports.CodeEmailAlreadyExists, "email address is already in use"),
+			sentinel: ports.ErrEmailAlreadyExists,
+			phase:    PhaseCreate,
+			code:     ports.CodeEmailAlreadyExists,
+		},
+		{
+			name:     "account limit exceeded",
+			method:   "CreateAccount",
+			err:      ports.NewAWSError(ports.CodeAccountLimitExceeded, "too many accounts"),
+			sentinel: ports.ErrAccountLimitExceeded,
+			phase:    PhaseCreate,
+			code:     ports.CodeAccountLimitExceeded,
+		},
+		{
+			name:      "concurrent modification during lookup",
+			method:    "GetAccountByName",
+			err:       ports.NewAWSError(ports.CodeConcurrentModification, "try again"),
+			sentinel:  ports.ErrConcurrentModification,
+			phase:     PhaseLookup,
+			code:      ports.CodeConcurrentModification,
+			retryable: true,
+		},
+		{
+			name:      "throttled while waiting",
+			method:    "WaitForAccountCreation",
+			err:       ports.NewAWSError(ports.CodeTooManyRequests, "slow down"),
+			sentinel:  ports.ErrThrottled,
+			phase:     PhaseWait,
+			code:      ports.CodeTooManyRequests,
+			retryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAWS := mock.NewAWSClient()
+			mockAWS.InjectError(tt.method, tt.err)
+
+			config := Config{
+				ProjectCode:  "TPA",
+				EmailPrefix:  "test",
+				OUID:         "ou-test-12345678",
+				Environments: []Environment{EnvironmentDev},
+			}
+
+			_, err := CreateAllAccounts(context.Background(), mockAWS, config)
+			if err == nil {
+				t.Fatal("CreateAllAccounts() error = nil, want error")
+			}
+
+			var opErr *OperationError
+			if !errors.As(err, &opErr) {
+				t.Fatalf("errors.As(%v, *OperationError) = false, want true", err)
+			}
+			if opErr.Environment != EnvironmentDev {
+				t.Errorf("Environment = %v, want %v", opErr.Environment, EnvironmentDev)
+			}
+			if opErr.AccountName != "TPA_DEV" {
+				t.Errorf("AccountName = %v, want TPA_DEV", opErr.AccountName)
+			}
+			if opErr.Phase != tt.phase {
+				t.Errorf("Phase = %v, want %v", opErr.Phase, tt.phase)
+			}
+			if opErr.Code != tt.code {
+				t.Errorf("Code = %v, want %v", opErr.Code, tt.code)
+			}
+			if opErr.Retryable != tt.retryable {
+				t.Errorf("Retryable = %v, want %v", opErr.Retryable, tt.retryable)
+			}
+			if tt.phase == PhaseWait && opErr.AccountID == "" {
+				t.Error("AccountID should be set once the account was requested")
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", err, tt.sentinel)
+			}
+		})
+	}
+}
+
+func TestInjectedErrorIsConsumed(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	mockAWS.InjectError("CreateAccount", ports.NewAWSError(ports.CodeConcurrentModification, "busy"))
+
+	config := Config{
+		ProjectCode:  "TPA",
+		EmailPrefix:  "test",
+		OUID:         "ou-test-12345678",
+		Environments: []Environment{EnvironmentDev},
+	}
+
+	if _, err := CreateAllAccounts(context.Background(), mockAWS, config); err == nil {
+		t.Fatal("first CreateAllAccounts() error = nil, want injected error")
+	}
+	if _, err := CreateAllAccounts(context.Background(), mockAWS, config); err != nil {
+		t.Fatalf("second CreateAllAccounts() error = %v, want nil", err)
+	}
+}
+
+func TestCreateAllAccountsReportsProgress(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	stagingID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_STAGING", Email: "test+tpa-staging@gmail.com"})
+	mockAWS.InjectError("TagAccount", errors.New("tagging disabled"))
+
+	config := Config{
+		ProjectCode:  "TPA",
+		EmailPrefix:  "test",
+		OUID:         "ou-test-12345678",
+		Environments: []Environment{EnvironmentDev, EnvironmentStaging},
+		Tags:         map[string]string{"team": "platform"},
+	}
+	recorder := mock.NewProgressRecorder()
+	_, err := CreateAllAccounts(ctx, mockAWS, config, WithProgress(recorder), WithErrorMode(CollectAllErrors))
+	if err == nil {
+		t.Fatal("CreateAllAccounts() should report the tagging failure")
+	}
+
+	type step struct {
+		typ  ports.ProgressEventType
+		step string
+	}
+	summarize := func(events []ports.ProgressEvent) []step {
+		var steps []step
+		for _, e := range events {
+			steps = append(steps, step{e.Type, e.Step})
+		}
+		return steps
+	}
+
+	dev := recorder.EventsFor("dev")
+	wantDev := []step{
+		{ports.EventStepStarted, "create"}, {ports.EventStepSucceeded, "create"},
+		{ports.EventStepStarted, "wait"}, {ports.EventPolling, "wait"}, {ports.EventStepSucceeded, "wait"},
+		{ports.EventStepStarted, "place"}, {ports.EventStepSucceeded, "place"},
+		{ports.EventStepStarted, "tag"}, {ports.EventStepFailed, "tag"},
+	}
+	if got := summarize(dev); !slices.Equal(got, wantDev) {
+		t.Errorf("dev events = %v, want %v", got, wantDev)
+	}
+	if dev[1].AccountID == "" || dev[1].AccountName != "TPA_DEV" {
+		t.Errorf("create succeeded event = %+v, want the new account", dev[1])
+	}
+	var opErr *OperationError
+	if failed := dev[len(dev)-1]; !errors.As(failed.Err, &opErr) || opErr.Phase != PhaseTag {
+		t.Errorf("failed event error = %v, want *OperationError for tag", failed.Err)
+	}
+
+	staging := recorder.EventsFor("staging")
+	if len(staging) == 0 || staging[0].Type != ports.EventReused || staging[0].AccountID != stagingID {
+		t.Errorf("staging events = %v, want the existing account reported as reused", summarize(staging))
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsSubstring(s, substr)))
 }
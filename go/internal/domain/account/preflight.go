@@ -0,0 +1,210 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Pre-flight checks.
+//
+// AWS Organizations accepts CreateAccount immediately and only rejects a
+// duplicate root email once the asynchronous creation fails, minutes into
+// WaitForAccountCreation. Preflight lists the organization's accounts up
+// front and flags every conflict before anything is created.
+
+// PreflightIssueKind classifies a pre-flight finding.
+type PreflightIssueKind string
+
+const (
+	IssueDuplicateName PreflightIssueKind = "duplicate_name" // Several accounts have the planned name
+	IssueEmailInUse    PreflightIssueKind = "email_in_use"   // Root email belongs to a different account
+	IssueNearMissName  PreflightIssueKind = "near_miss_name" // An account differs from the planned name only by case or separators
+	IssueEmailMismatch PreflightIssueKind = "email_mismatch" // The account to be reused has a different root email
+	IssueInactive      PreflightIssueKind = "inactive"       // The account with the planned name is suspended or closing
+)
+
+// PreflightSeverity says whether a finding blocks account creation.
+type PreflightSeverity string
+
+const (
+	SeverityError   PreflightSeverity = "error"   // CreateAllAccounts would fail or pick the wrong account
+	SeverityWarning PreflightSeverity = "warning" // Probably a mistake, but creation can proceed
+)
+
+// PreflightIssue describes one conflict between the config and the organization.
+type PreflightIssue struct {
+	Environment   Environment        `json:"environment"`
+	Kind          PreflightIssueKind `json:"kind"`
+	Severity      PreflightSeverity  `json:"severity"`
+	AccountName   string             `json:"accountName"`             // Planned account name
+	Email         string             `json:"email"`                   // Planned root email
+	ExistingID    string             `json:"existingId,omitempty"`    // Conflicting account, if any
+	ExistingName  string             `json:"existingName,omitempty"`  // Its name
+	ExistingEmail string             `json:"existingEmail,omitempty"` // Its root email
+	Message       string             `json:"message"`
+}
+
+func (i PreflightIssue) String() string {
+	return fmt.Sprintf("%s (%s): %s", i.Environment, i.Severity, i.Message)
+}
+
+// PreflightReport lists every finding for a config.
+type PreflightReport struct {
+	Accounts int              `json:"accounts"` // Accounts in the organization
+	Issues   []PreflightIssue `json:"issues"`
+}
+
+// Blocking returns the findings with SeverityError.
+func (r *PreflightReport) Blocking() []PreflightIssue {
+	var blocking []PreflightIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			blocking = append(blocking, issue)
+		}
+	}
+	return blocking
+}
+
+// Err returns the report as an error if any finding blocks creation.
+func (r *PreflightReport) Err() error {
+	if len(r.Blocking()) == 0 {
+		return nil
+	}
+	return r
+}
+
+// Error lists the blocking findings.
+func (r *PreflightReport) Error() string {
+	blocking := r.Blocking()
+	var b strings.Builder
+	fmt.Fprintf(&b, "pre-flight found %d blocking issue(s):", len(blocking))
+	for _, issue := range blocking {
+		b.WriteString("\n  - ")
+		b.WriteString(issue.String())
+	}
+	return b.String()
+}
+
+// Preflight checks the config's planned account names and root emails
+// against every account already in the organization.
+//
+// This is DOMAIN LOGIC:
+//   - An account with exactly the planned name is reused by
+//     CreateAllAccounts, so it is only reported if its root email differs
+//     (warning), it isn't ACTIVE (error: CreateAllAccounts refuses it) or
+//     several accounts share the name (error).
+//   - A planned root email owned by any other account is an error: AWS
+//     would reject the creation. Suspended accounts keep their email.
+//   - An account whose name matches once case and separators are ignored
+//     (TPA_DEV vs TPA-dev) is a warning: usually the same project created
+//     under a different naming convention.
+//
+// Config validation already guarantees the planned names and emails are
+// unique among the environments themselves.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - aws: AWS client implementation
+//   - config: Business configuration (validated)
+//
+// Returns:
+//   - PreflightReport with every finding (empty if there are none)
+//   - Error if the config is invalid or the accounts can't be listed
+func Preflight(ctx context.Context, aws ports.AWSClient, config Config) (*PreflightReport, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	envs, err := config.TargetEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	existing, err := aws.ListAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization accounts: %w", err)
+	}
+
+	report := &PreflightReport{Accounts: len(existing)}
+
+	for _, env := range envs {
+		issue := PreflightIssue{
+			Environment: env,
+			AccountName: config.ResourceNames(env).Account,
+			Email:       config.SettingsFor(env, EnvironmentSettings{}).Email,
+		}
+
+		var sameName []ports.AWSAccount
+		for _, acct := range existing {
+			if acct.Name == issue.AccountName {
+				sameName = append(sameName, acct)
+			}
+		}
+		if len(sameName) > 1 {
+			ids := make([]string, len(sameName))
+			for i, acct := range sameName {
+				ids[i] = acct.ID
+			}
+			report.add(issue, IssueDuplicateName, SeverityError, nil,
+				fmt.Sprintf("%d accounts are named %s (%s); the one to reuse is ambiguous", len(sameName), issue.AccountName, strings.Join(ids, ", ")))
+		}
+
+		for _, acct := range existing {
+			switch {
+			case acct.Name == issue.AccountName:
+				if len(sameName) > 1 {
+					break
+				}
+				if acct.Status != "" && acct.Status != ports.AccountStatusActive {
+					report.add(issue, IssueInactive, SeverityError, &acct,
+						fmt.Sprintf("existing account %s (%s) can't be reused%s", acct.Name, acct.ID, statusNote(acct)))
+				} else if !strings.EqualFold(acct.Email, issue.Email) {
+					report.add(issue, IssueEmailMismatch, SeverityWarning, &acct,
+						fmt.Sprintf("existing account %s (%s) will be reused, but its root email is %s, not %s", acct.Name, acct.ID, acct.Email, issue.Email))
+				}
+			case strings.EqualFold(acct.Email, issue.Email):
+				report.add(issue, IssueEmailInUse, SeverityError, &acct,
+					fmt.Sprintf("root email %s is already used by account %s (%s)%s", issue.Email, acct.Name, acct.ID, statusNote(acct)))
+			case normalizeAccountName(acct.Name) == normalizeAccountName(issue.AccountName):
+				report.add(issue, IssueNearMissName, SeverityWarning, &acct,
+					fmt.Sprintf("account %s (%s) differs from %s only by case or separators", acct.Name, acct.ID, issue.AccountName))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (r *PreflightReport) add(issue PreflightIssue, kind PreflightIssueKind, severity PreflightSeverity, acct *ports.AWSAccount, message string) {
+	issue.Kind = kind
+	issue.Severity = severity
+	issue.Message = message
+	if acct != nil {
+		issue.ExistingID = acct.ID
+		issue.ExistingName = acct.Name
+		issue.ExistingEmail = acct.Email
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+// statusNote explains why a closed account still holds its email.
+func statusNote(acct ports.AWSAccount) string {
+	if acct.Status == "" || acct.Status == ports.AccountStatusActive {
+		return ""
+	}
+	return fmt.Sprintf(" (%s; closed accounts keep their root email)", acct.Status)
+}
+
+// normalizeAccountName folds case and drops separators, so "TPA_DEV",
+// "TPA-dev" and "tpa.dev" compare equal.
+func normalizeAccountName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', '.', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
@@ -0,0 +1,147 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// orgAWS reports a fixed list of organization accounts.
+type orgAWS struct {
+	*mock.AWSClient
+	accounts []ports.AWSAccount
+}
+
+func (o *orgAWS) ListAccounts(ctx context.Context) ([]ports.AWSAccount, error) {
+	return o.accounts, nil
+}
+
+func TestPreflight(t *testing.T) {
+	config := Config{ProjectCode: "TPA", EmailPrefix: "user", OUID: "ou-813y-8teevv2l"}
+
+	tests := []struct {
+		name     string
+		accounts []ports.AWSAccount
+		want     []PreflightIssueKind
+		blocking bool
+	}{
+		{
+			name: "unrelated accounts",
+			accounts: []ports.AWSAccount{
+				{ID: "111111111111", Name: "Management", Email: "admin@example.com", Status: ports.AccountStatusActive},
+			},
+		},
+		{
+			name: "account to reuse",
+			accounts: []ports.AWSAccount{
+				{ID: "111111111111", Name: "TPA_STAGING", Email: "user+tpa-staging@gmail.com", Status: ports.AccountStatusActive},
+			},
+		},
+		{
+			name: "account to reuse has another email",
+			accounts: []ports.AWSAccount{
+				{ID: "111111111111", Name: "TPA_STAGING", Email: "staging@example.com", Status: ports.AccountStatusActive},
+			},
+			want: []PreflightIssueKind{IssueEmailMismatch},
+		},
+		{
+			name: "account to reuse is closed",
+			accounts: []ports.AWSAccount{
+				{ID: "111111111111", Name: "TPA_STAGING", Email: "user+tpa-staging@gmail.com", Status: ports.AccountStatusSuspended},
+			},
+			want:     []PreflightIssueKind{IssueInactive},
+			blocking: true,
+		},
+		{
+			name: "email used by another account",
+			accounts: []ports.AWSAccount{
+				{ID: "111111111111", Name: "Legacy", Email: "User+TPA-dev@gmail.com", Status: ports.AccountStatusSuspended},
+			},
+			want:     []PreflightIssueKind{IssueEmailInUse},
+			blocking: true,
+		},
+		{
+			name: "near-miss name",
+			accounts: []ports.AWSAccount{
+				{ID: "111111111111", Name: "TPA-prod", Email: "prod@example.com", Status: ports.AccountStatusActive},
+			},
+			want: []PreflightIssueKind{IssueNearMissName},
+		},
+		{
+			name: "ambiguous name",
+			accounts: []ports.AWSAccount{
+				{ID: "111111111111", Name: "TPA_PROD", Email: "user+tpa-prod@gmail.com", Status: ports.AccountStatusActive},
+				{ID: "222222222222", Name: "TPA_PROD", Email: "prod@example.com", Status: ports.AccountStatusActive},
+			},
+			want:     []PreflightIssueKind{IssueDuplicateName},
+			blocking: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aws := &orgAWS{AWSClient: mock.NewAWSClient(), accounts: tt.accounts}
+
+			report, err := Preflight(context.Background(), aws, config)
+			if err != nil {
+				t.Fatalf("Preflight() failed: %v", err)
+			}
+			if report.Accounts != len(tt.accounts) {
+				t.Errorf("Accounts = %d, want %d", report.Accounts, len(tt.accounts))
+			}
+
+			var got []PreflightIssueKind
+			for _, issue := range report.Issues {
+				got = append(got, issue.Kind)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Preflight() issues = %v, want %v", report.Issues, tt.want)
+			}
+			if (report.Err() != nil) != tt.blocking {
+				t.Errorf("Err() = %v, want blocking %v", report.Err(), tt.blocking)
+			}
+		})
+	}
+}
+
+func TestCreateAllAccountsWithPreflight(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := Config{ProjectCode: "TPA", EmailPrefix: "user", OUID: "ou-813y-8teevv2l"}
+
+	// Someone already used the prod address for another account
+	if _, err := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "Sandbox", Email: "user+tpa-prod@gmail.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := CreateAllAccounts(ctx, mockAWS, config, WithPreflight())
+
+	var report *PreflightReport
+	if !errors.As(err, &report) {
+		t.Fatalf("CreateAllAccounts() = %v, want *PreflightReport", err)
+	}
+	if issue := report.Blocking()[0]; issue.Environment != EnvironmentProd || issue.ExistingName != "Sandbox" {
+		t.Errorf("Blocking issue = %+v, want prod clashing with Sandbox", issue)
+	}
+	if !strings.Contains(err.Error(), "root email user+tpa-prod@gmail.com is already used by account Sandbox") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	// Nothing was created
+	for _, op := range mockAWS.GetOperations()[1:] {
+		if strings.HasPrefix(op, "CreateAccount") {
+			t.Errorf("Preflight failure should stop creation, got %s", op)
+		}
+	}
+
+	// A clean organization passes straight through
+	accounts, err := CreateAllAccounts(ctx, mock.NewAWSClient(), config, WithPreflight())
+	if err != nil || len(accounts) != 3 {
+		t.Errorf("CreateAllAccounts() = %d accounts, %v; want 3, nil", len(accounts), err)
+	}
+}
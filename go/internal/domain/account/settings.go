@@ -0,0 +1,292 @@
+package account
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Per-environment settings.
+//
+// Settings are layered, later layers winning:
+//  1. Project-level values (Config.OUID, Config.Tags, and whatever the
+//     caller passes as the base, e.g. project region and billing)
+//  2. The environment definition's Defaults (EnvironmentSpec.Defaults)
+//  3. The config's override block for the environment (Config.Overrides)
+//
+// Zero values never override: an empty string or a zero amount means
+// "inherit". Tags are merged key by key.
+
+// EnvironmentSettings holds the settings one environment can customize.
+type EnvironmentSettings struct {
+	OUID              string            `json:"ouId,omitempty"`              // OU the account lives in
+	Email             string            `json:"email,omitempty"`             // Account root email (instead of the generated one)
+	NotificationEmail string            `json:"notificationEmail,omitempty"` // Where billing alerts go
+	Regions           []string          `json:"regions,omitempty"`           // Regions to bootstrap CDK in
+	MonthlyLimit      float64           `json:"monthlyLimit,omitempty"`      // Budget limit in USD
+	AlertThreshold    float64           `json:"alertThreshold,omitempty"`    // Alarm/alert amount in USD
+	AlertPercents     []int             `json:"alertPercents,omitempty"`     // Budget alert percentages
+	PolicyARNs        []string          `json:"policyArns,omitempty"`        // Policies attached to the deploy role
+	Tags              map[string]string `json:"tags,omitempty"`              // Tags applied to the account
+}
+
+// Merge returns s with every non-zero field of over applied on top.
+func (s EnvironmentSettings) Merge(over EnvironmentSettings) EnvironmentSettings {
+	merged := s
+	if over.OUID != "" {
+		merged.OUID = over.OUID
+	}
+	if over.Email != "" {
+		merged.Email = over.Email
+	}
+	if over.NotificationEmail != "" {
+		merged.NotificationEmail = over.NotificationEmail
+	}
+	if len(over.Regions) > 0 {
+		merged.Regions = slices.Clone(over.Regions)
+	}
+	if over.MonthlyLimit != 0 {
+		merged.MonthlyLimit = over.MonthlyLimit
+	}
+	if over.AlertThreshold != 0 {
+		merged.AlertThreshold = over.AlertThreshold
+	}
+	if len(over.AlertPercents) > 0 {
+		merged.AlertPercents = slices.Clone(over.AlertPercents)
+	}
+	if len(over.PolicyARNs) > 0 {
+		merged.PolicyARNs = slices.Clone(over.PolicyARNs)
+	}
+	if len(s.Tags) > 0 || len(over.Tags) > 0 {
+		merged.Tags = make(map[string]string, len(s.Tags)+len(over.Tags))
+		maps.Copy(merged.Tags, s.Tags)
+		maps.Copy(merged.Tags, over.Tags)
+	}
+	return merged
+}
+
+// SettingsFor resolves the settings for env.
+//
+// base carries project-level values this package doesn't own (regions,
+// billing, policies). OUID, Email and Tags come from the config itself;
+// the environment's Defaults and Overrides are then applied in that order.
+func (c *Config) SettingsFor(env Environment, base EnvironmentSettings) EnvironmentSettings {
+	base.OUID = c.OUID
+	base.Email = c.Email.Address(c.EmailPrefix, c.ProjectCode, env)
+	base.Tags = c.Tags
+
+	settings := base.Merge(EnvironmentSettings{})
+	if registry, err := c.EnvironmentRegistry(); err == nil {
+		if spec, ok := registry.Lookup(env); ok {
+			settings = settings.Merge(spec.Defaults)
+		}
+	}
+	return settings.Merge(c.Overrides[env])
+}
+
+var (
+	// Full email address (overrides name an exact address, not a prefix)
+	fullEmailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+	// AWS region, e.g. us-east-1, eu-central-2, us-gov-west-1
+	regionRegex = regexp.MustCompile(`^[a-z]{2}(-gov)?-[a-z]+-[0-9]$`)
+)
+
+// validateSettings checks the fields set in one settings block.
+// field prefixes error fields, e.g. "overrides.prod".
+func validateSettings(report *ValidationReport, field string, s EnvironmentSettings) {
+	if s.OUID != "" {
+		if err := ValidateOUID(s.OUID); err != nil {
+			nested := *err.(*ValidationError)
+			nested.Field = field + ".ouId"
+			report.Add(&nested)
+		}
+	}
+
+	if s.Email != "" && !fullEmailRegex.MatchString(s.Email) {
+		report.Add(&ValidationError{
+			Field:      field + ".email",
+			Code:       CodeInvalidFormat,
+			Message:    "must be a full email address",
+			Value:      s.Email,
+			Suggestion: "use an address such as aws-prod@example.com",
+		})
+	}
+
+	if s.NotificationEmail != "" && !fullEmailRegex.MatchString(s.NotificationEmail) {
+		report.Add(&ValidationError{
+			Field:   field + ".notificationEmail",
+			Code:    CodeInvalidFormat,
+			Message: "must be a full email address",
+			Value:   s.NotificationEmail,
+		})
+	}
+
+	for i, region := range s.Regions {
+		if !regionRegex.MatchString(region) {
+			report.Add(&ValidationError{
+				Field:      fmt.Sprintf("%s.regions[%d]", field, i),
+				Code:       CodeInvalidFormat,
+				Message:    fmt.Sprintf("invalid AWS region %q", region),
+				Value:      region,
+				Suggestion: "use a region code such as us-east-1",
+			})
+		}
+	}
+
+	if s.MonthlyLimit < 0 {
+		report.Add(&ValidationError{
+			Field:   field + ".monthlyLimit",
+			Code:    CodeOutOfRange,
+			Message: "must not be negative",
+			Value:   fmt.Sprintf("%.2f", s.MonthlyLimit),
+		})
+	}
+	if s.AlertThreshold < 0 {
+		report.Add(&ValidationError{
+			Field:   field + ".alertThreshold",
+			Code:    CodeOutOfRange,
+			Message: "must not be negative",
+			Value:   fmt.Sprintf("%.2f", s.AlertThreshold),
+		})
+	}
+
+	for i, arn := range s.PolicyARNs {
+		if !strings.HasPrefix(arn, "arn:aws") || !strings.Contains(arn, ":policy/") {
+			report.Add(&ValidationError{
+				Field:      fmt.Sprintf("%s.policyArns[%d]", field, i),
+				Code:       CodeInvalidFormat,
+				Message:    fmt.Sprintf("invalid IAM policy ARN %q", arn),
+				Value:      arn,
+				Suggestion: "use a full ARN, e.g. arn:aws:iam::aws:policy/" + arn,
+			})
+		}
+	}
+
+	validateTags(report, field+".tags", s.Tags)
+}
+
+// validateTags enforces the AWS tag limits.
+func validateTags(report *ValidationReport, field string, tags map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		switch {
+		case key == "" || len(key) > 128:
+			report.Add(&ValidationError{
+				Field:   field,
+				Code:    CodeTooLong,
+				Message: fmt.Sprintf("tag key %q must be 1-128 characters", key),
+				Value:   key,
+			})
+		case strings.HasPrefix(strings.ToLower(key), "aws:"):
+			report.Add(&ValidationError{
+				Field:      field + "." + key,
+				Code:       CodeReserved,
+				Message:    fmt.Sprintf("tag key %q uses the reserved aws: prefix", key),
+				Value:      key,
+				Suggestion: "drop the aws: prefix",
+			})
+		case len(tags[key]) > 256:
+			report.Add(&ValidationError{
+				Field:   field + "." + key,
+				Code:    CodeTooLong,
+				Message: fmt.Sprintf("tag %q value must be at most 256 characters", key),
+				Value:   tags[key],
+			})
+		}
+	}
+}
+
+// validateOverrides checks the config's settings blocks and that every
+// environment ends up with its own valid root email (AWS requires unique
+// ones).
+func (c *Config) validateOverrides(report *ValidationReport, registry *EnvironmentRegistry) {
+	validateTags(report, "tags", c.Tags)
+
+	for _, spec := range registry.Specs() {
+		validateSettings(report, fmt.Sprintf("environmentSpecs.%s.defaults", spec.Name), spec.Defaults)
+	}
+
+	for _, env := range slices.Sorted(maps.Keys(c.Overrides)) {
+		if err := registry.Validate(env); err != nil {
+			report.Add(&ValidationError{
+				Field:      "overrides." + string(env),
+				Code:       CodeUnknown,
+				Message:    fmt.Sprintf("unknown environment (declared: %v)", registry.Environments()),
+				Value:      string(env),
+				Suggestion: "declare it in environmentSpecs or remove the override",
+			})
+			continue
+		}
+		validateSettings(report, "overrides."+string(env), c.Overrides[env])
+	}
+
+	// Generated addresses are only meaningful once their inputs are valid
+	if report.HasField("emailPrefix") || report.HasField("email") {
+		return
+	}
+
+	seen := make(map[string]Environment)
+	for _, env := range registry.Environments() {
+		field := "email"
+		if c.Overrides[env].Email != "" {
+			field = "overrides." + string(env) + ".email"
+		} else if c.Email.scheme() == EmailSchemeExplicit {
+			field = "email.addresses." + string(env)
+		}
+		if report.HasField(field) {
+			continue
+		}
+
+		email := strings.ToLower(c.SettingsFor(env, EnvironmentSettings{}).Email)
+		if err := ValidateAccountEmail(field, email); err != nil {
+			report.Add(err)
+			continue
+		}
+		if other, dup := seen[email]; dup {
+			report.Add(&ValidationError{
+				Field:      field,
+				Code:       CodeDuplicate,
+				Message:    fmt.Sprintf("%s is already used by %s (AWS requires a unique root email per account)", email, other),
+				Value:      email,
+				Suggestion: fmt.Sprintf("give %s its own address", env),
+			})
+		}
+		seen[email] = env
+	}
+}
+
+// describe lists the settings that differ from base, for summaries.
+func (s EnvironmentSettings) describe(base EnvironmentSettings) []string {
+	var parts []string
+	if s.OUID != base.OUID {
+		parts = append(parts, "OU "+s.OUID)
+	}
+	if s.NotificationEmail != base.NotificationEmail {
+		parts = append(parts, "alerts to "+s.NotificationEmail)
+	}
+	if !slices.Equal(s.Regions, base.Regions) {
+		parts = append(parts, "regions "+strings.Join(s.Regions, ", "))
+	}
+	if s.MonthlyLimit != base.MonthlyLimit {
+		parts = append(parts, fmt.Sprintf("budget $%.2f", s.MonthlyLimit))
+	}
+	if s.AlertThreshold != base.AlertThreshold {
+		parts = append(parts, fmt.Sprintf("alert at $%.2f", s.AlertThreshold))
+	}
+	if !slices.Equal(s.AlertPercents, base.AlertPercents) {
+		parts = append(parts, fmt.Sprintf("alert percents %v", s.AlertPercents))
+	}
+	if !slices.Equal(s.PolicyARNs, base.PolicyARNs) {
+		parts = append(parts, "policies "+strings.Join(s.PolicyARNs, ", "))
+	}
+	if !maps.Equal(s.Tags, base.Tags) {
+		var tags []string
+		for _, key := range slices.Sorted(maps.Keys(s.Tags)) {
+			tags = append(tags, key+"="+s.Tags[key])
+		}
+		parts = append(parts, "tags "+strings.Join(tags, ", "))
+	}
+	return parts
+}
@@ -0,0 +1,199 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+)
+
+func overrideConfig() Config {
+	return Config{
+		ProjectCode: "TPA",
+		EmailPrefix: "user",
+		OUID:        "ou-813y-8teevv2l",
+		Tags:        map[string]string{"project": "TPA", "owner": "platform"},
+		Overrides: map[Environment]EnvironmentSettings{
+			EnvironmentProd: {
+				OUID:              "ou-813y-prod0001",
+				Email:             "aws-prod@example.com",
+				NotificationEmail: "oncall@example.com",
+				MonthlyLimit:      500,
+				Tags:              map[string]string{"owner": "sre", "tier": "critical"},
+			},
+		},
+	}
+}
+
+func TestSettingsFor(t *testing.T) {
+	config := overrideConfig()
+	config.EnvironmentSpecs = []EnvironmentSpec{
+		{Name: "dev", Order: 1},
+		{Name: "prod", Order: 2, Production: true, Defaults: EnvironmentSettings{
+			MonthlyLimit:   200,
+			AlertThreshold: 150,
+			Regions:        []string{"us-east-1", "us-west-2"},
+		}},
+	}
+	base := EnvironmentSettings{Regions: []string{"us-east-1"}, MonthlyLimit: 25, AlertThreshold: 15}
+
+	dev := config.SettingsFor(EnvironmentDev, base)
+	if dev.OUID != config.OUID || dev.Email != "user+tpa-dev@gmail.com" || dev.MonthlyLimit != 25 {
+		t.Errorf("dev settings = %+v, want project defaults", dev)
+	}
+
+	prod := config.SettingsFor(EnvironmentProd, base)
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"OUID from override", prod.OUID, "ou-813y-prod0001"},
+		{"Email from override", prod.Email, "aws-prod@example.com"},
+		{"MonthlyLimit from override", prod.MonthlyLimit, 500.0},
+		{"AlertThreshold from spec defaults", prod.AlertThreshold, 150.0},
+		{"Regions from spec defaults", strings.Join(prod.Regions, ","), "us-east-1,us-west-2"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	wantTags := map[string]string{"project": "TPA", "owner": "sre", "tier": "critical"}
+	if !maps.Equal(prod.Tags, wantTags) {
+		t.Errorf("prod tags = %v, want %v", prod.Tags, wantTags)
+	}
+
+	// Resolving must not leak into the config's own maps
+	if config.Tags["owner"] != "platform" {
+		t.Errorf("config tags were modified: %v", config.Tags)
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		field  string
+	}{
+		{
+			name:   "unknown environment",
+			modify: func(c *Config) { c.Overrides["qa"] = EnvironmentSettings{MonthlyLimit: 10} },
+			field:  "overrides.qa",
+		},
+		{
+			name:   "invalid OU",
+			modify: func(c *Config) { c.Overrides[EnvironmentDev] = EnvironmentSettings{OUID: "prod-ou"} },
+			field:  "overrides.dev.ouId",
+		},
+		{
+			name:   "email prefix instead of address",
+			modify: func(c *Config) { c.Overrides[EnvironmentDev] = EnvironmentSettings{Email: "user"} },
+			field:  "overrides.dev.email",
+		},
+		{
+			name: "duplicate root email",
+			modify: func(c *Config) {
+				c.Overrides[EnvironmentDev] = EnvironmentSettings{Email: "AWS-prod@example.com"}
+			},
+			field: "overrides.prod.email",
+		},
+		{
+			name:   "invalid region",
+			modify: func(c *Config) { c.Overrides[EnvironmentDev] = EnvironmentSettings{Regions: []string{"mars-1"}} },
+			field:  "overrides.dev.regions[0]",
+		},
+		{
+			name: "invalid policy ARN",
+			modify: func(c *Config) {
+				c.Overrides[EnvironmentDev] = EnvironmentSettings{PolicyARNs: []string{"AdministratorAccess"}}
+			},
+			field: "overrides.dev.policyArns[0]",
+		},
+		{
+			name:   "reserved tag prefix",
+			modify: func(c *Config) { c.Tags["aws:createdBy"] = "me" },
+			field:  "tags.aws:createdBy",
+		},
+		{
+			name: "invalid spec defaults",
+			modify: func(c *Config) {
+				c.EnvironmentSpecs = []EnvironmentSpec{{Name: "prod", Defaults: EnvironmentSettings{MonthlyLimit: -1}}}
+			},
+			field: "environmentSpecs.prod.defaults.monthlyLimit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := overrideConfig()
+			tt.modify(&config)
+
+			var validationErr *ValidationError
+			if err := config.Validate(); !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want ValidationError", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("Field = %s, want %s (%v)", validationErr.Field, tt.field, validationErr)
+			}
+		})
+	}
+
+	config := overrideConfig()
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestGenerateSummaryShowsOverrides(t *testing.T) {
+	summary := GenerateSummary(overrideConfig())
+
+	if !strings.Contains(summary, "TPA_PROD        -> aws-prod@example.com") {
+		t.Errorf("Summary should use the prod email override:\n%s", summary)
+	}
+	for _, want := range []string{"OU ou-813y-prod0001", "alerts to oncall@example.com", "budget $500.00", "tier=critical"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	// Environments without overrides get no details line
+	lines := strings.Split(summary, "\n")
+	i := slices.IndexFunc(lines, func(line string) bool { return strings.Contains(line, "TPA_DEV") })
+	if i < 0 || !strings.Contains(lines[i+1], "TPA_STAGING") {
+		t.Errorf("dev should have no details line:\n%s", summary)
+	}
+}
+
+func TestCreateAllAccountsAppliesOverrides(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+
+	accounts, err := CreateAllAccounts(ctx, mockAWS, overrideConfig())
+	if err != nil {
+		t.Fatalf("CreateAllAccounts() failed: %v", err)
+	}
+
+	dev, prod := accounts[0], accounts[2]
+	if prod.Email != "aws-prod@example.com" || prod.OUID != "ou-813y-prod0001" {
+		t.Errorf("prod account = %+v, want override email and OU", prod)
+	}
+	if parent, _ := mockAWS.GetAccountParent(ctx, prod.AccountID); parent != "ou-813y-prod0001" {
+		t.Errorf("prod parent = %s, want ou-813y-prod0001", parent)
+	}
+	if parent, _ := mockAWS.GetAccountParent(ctx, dev.AccountID); parent != "ou-813y-8teevv2l" {
+		t.Errorf("dev parent = %s, want ou-813y-8teevv2l", parent)
+	}
+
+	if tags := mockAWS.AccountTags(prod.AccountID); tags["owner"] != "sre" || tags["tier"] != "critical" || tags["project"] != "TPA" {
+		t.Errorf("prod tags = %v, want merged project and override tags", tags)
+	}
+	if tags := mockAWS.AccountTags(dev.AccountID); tags["owner"] != "platform" || tags["tier"] != "" {
+		t.Errorf("dev tags = %v, want project tags only", tags)
+	}
+}
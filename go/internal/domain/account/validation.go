@@ -0,0 +1,144 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validation results.
+//
+// Validators collect every problem into a ValidationReport instead of
+// stopping at the first one, so a wizard or API client can show them all at
+// once. The report is an error whose Unwrap exposes each *ValidationError,
+// so errors.As(err, &validationErr) still finds the first problem.
+
+// ValidationCode classifies a validation failure for machine consumers.
+type ValidationCode string
+
+const (
+	CodeRequired      ValidationCode = "required"       // Field must be set
+	CodeInvalidFormat ValidationCode = "invalid_format" // Value doesn't match the expected format
+	CodeTooLong       ValidationCode = "too_long"       // Value exceeds a length limit
+	CodeOutOfRange    ValidationCode = "out_of_range"   // Number outside the allowed range
+	CodeUnknown       ValidationCode = "unknown"        // Names something that doesn't exist (environment, preset, ...)
+	CodeDuplicate     ValidationCode = "duplicate"      // Value must be unique but isn't
+	CodeReserved      ValidationCode = "reserved"       // Value uses a reserved name or prefix
+	CodeConflict      ValidationCode = "conflict"       // Value contradicts another field
+	CodeInvalid       ValidationCode = "invalid"        // Anything else
+)
+
+// ValidationError represents a validation failure with a descriptive message.
+type ValidationError struct {
+	Field      string         `json:"field"`                // Path to the field, e.g. "overrides.prod.ouId"
+	Code       ValidationCode `json:"code"`                 // Machine-readable failure class
+	Message    string         `json:"message"`              // Human-readable description
+	Value      string         `json:"value,omitempty"`      // Offending value, if useful to show
+	Suggestion string         `json:"suggestion,omitempty"` // How to fix it, if known
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationReport lists every validation failure found in a config.
+//
+// The zero value is an empty (valid) report.
+type ValidationReport struct {
+	Errors []*ValidationError
+}
+
+// Add records err. Nil is ignored, nested reports are flattened and errors
+// that aren't *ValidationError are recorded with CodeInvalid.
+func (r *ValidationReport) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	var report *ValidationReport
+	if errors.As(err, &report) {
+		r.Errors = append(r.Errors, report.Errors...)
+		return
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		r.Errors = append(r.Errors, validationErr)
+		return
+	}
+
+	r.Errors = append(r.Errors, &ValidationError{Code: CodeInvalid, Message: err.Error()})
+}
+
+// Valid reports whether no problems were recorded.
+func (r *ValidationReport) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Err returns the report as an error, or nil if it is valid.
+func (r *ValidationReport) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return r
+}
+
+// HasField reports whether a problem was recorded for field or anything
+// nested under it (e.g., "email" matches "email.domain").
+func (r *ValidationReport) HasField(field string) bool {
+	for _, e := range r.Errors {
+		if e.Field == field || strings.HasPrefix(e.Field, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Error lists every problem, one per line after a count.
+func (r *ValidationReport) Error() string {
+	if len(r.Errors) == 1 {
+		return r.Errors[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d validation errors:", len(r.Errors))
+	for _, e := range r.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
+
+// Unwrap exposes each problem to errors.Is and errors.As.
+func (r *ValidationReport) Unwrap() []error {
+	errs := make([]error, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = e
+	}
+	return errs
+}
+
+// MarshalJSON encodes the report as {"valid": bool, "errors": [...]}.
+func (r *ValidationReport) MarshalJSON() ([]byte, error) {
+	errs := r.Errors
+	if errs == nil {
+		errs = []*ValidationError{}
+	}
+	return json.Marshal(struct {
+		Valid  bool               `json:"valid"`
+		Errors []*ValidationError `json:"errors"`
+	}{Valid: r.Valid(), Errors: errs})
+}
+
+// UnmarshalJSON decodes a report written by MarshalJSON.
+func (r *ValidationReport) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		Errors []*ValidationError `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	r.Errors = decoded.Errors
+	return nil
+}
@@ -0,0 +1,146 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func brokenConfig() Config {
+	return Config{
+		ProjectCode: "tp",
+		EmailPrefix: "user",
+		OUID:        "ou-bad",
+		Overrides: map[Environment]EnvironmentSettings{
+			EnvironmentProd: {Regions: []string{"us-east-1", "moon-1"}},
+			"qa":            {MonthlyLimit: 10},
+		},
+		Naming: NamingConvention{Preset: "v3"},
+	}
+}
+
+func TestValidateAllCollectsEveryProblem(t *testing.T) {
+	config := brokenConfig()
+	report := config.ValidateAll()
+
+	want := []struct {
+		field string
+		code  ValidationCode
+		value string
+	}{
+		{"projectCode", CodeInvalidFormat, "tp"},
+		{"ouID", CodeInvalidFormat, "ou-bad"},
+		{"overrides.prod.regions[1]", CodeInvalidFormat, "moon-1"},
+		{"overrides.qa", CodeUnknown, "qa"},
+		{"naming.preset", CodeUnknown, "v3"},
+	}
+	if len(report.Errors) != len(want) {
+		t.Fatalf("ValidateAll() found %d problems, want %d:\n%v", len(report.Errors), len(want), report)
+	}
+	for i, w := range want {
+		got := report.Errors[i]
+		if got.Field != w.field || got.Code != w.code || got.Value != w.value {
+			t.Errorf("Errors[%d] = %s/%s/%q, want %s/%s/%q", i, got.Field, got.Code, got.Value, w.field, w.code, w.value)
+		}
+	}
+
+	if !strings.Contains(report.Errors[0].Suggestion, "3-character") {
+		t.Errorf("projectCode suggestion = %q", report.Errors[0].Suggestion)
+	}
+	if !strings.Contains(report.Errors[4].Suggestion, "hyphenated") {
+		t.Errorf("naming.preset suggestion should list presets, got %q", report.Errors[4].Suggestion)
+	}
+}
+
+func TestValidateStaysCompatibleWithErrorsAs(t *testing.T) {
+	config := brokenConfig()
+	err := config.Validate()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "projectCode" {
+		t.Errorf("errors.As() should find the first problem, got %v", validationErr)
+	}
+
+	var report *ValidationReport
+	if !errors.As(err, &report) || len(report.Errors) != 5 {
+		t.Errorf("errors.As() should find the full report, got %v", report)
+	}
+
+	if !strings.HasPrefix(err.Error(), "5 validation errors:") {
+		t.Errorf("Error() = %q, want a count followed by each problem", err.Error())
+	}
+
+	// A single problem reads like it always did
+	config = Config{ProjectCode: "TPA", EmailPrefix: "user", OUID: "invalid"}
+	if err := config.Validate(); err == nil || err.Error() != "ouID: must start with 'ou-'" {
+		t.Errorf("Validate() = %v, want the lone ouID error", err)
+	}
+}
+
+func TestValidateAllSkipsDependentChecks(t *testing.T) {
+	// An invalid prefix would also make every generated email invalid;
+	// only the root cause is reported
+	config := Config{ProjectCode: "TPA", EmailPrefix: "not an email", OUID: "ou-813y-8teevv2l"}
+
+	report := config.ValidateAll()
+	if len(report.Errors) != 1 || report.Errors[0].Field != "emailPrefix" {
+		t.Errorf("ValidateAll() = %v, want only the emailPrefix problem", report)
+	}
+
+	// A broken template is reported once, not once per rendered name
+	config = Config{ProjectCode: "TPA", EmailPrefix: "user", OUID: "ou-813y-8teevv2l", Naming: NamingConvention{Role: "Deploy Role"}}
+	report = config.ValidateAll()
+	if len(report.Errors) != 1 || report.Errors[0].Field != "naming.role" {
+		t.Errorf("ValidateAll() = %v, want a single naming.role problem", report)
+	}
+}
+
+func TestNewEnvironmentRegistryReportsEverySpec(t *testing.T) {
+	_, err := NewEnvironmentRegistry(
+		EnvironmentSpec{Name: "QA"},
+		EnvironmentSpec{Name: "dev"},
+		EnvironmentSpec{Name: "dev"},
+	)
+
+	var report *ValidationReport
+	if !errors.As(err, &report) || len(report.Errors) != 2 {
+		t.Fatalf("NewEnvironmentRegistry() = %v, want two problems", err)
+	}
+	if report.Errors[0].Field != "environmentSpecs[0].name" || report.Errors[1].Code != CodeDuplicate {
+		t.Errorf("Unexpected problems: %v", report)
+	}
+}
+
+func TestValidationReportJSON(t *testing.T) {
+	config := brokenConfig()
+	data, err := json.Marshal(config.ValidateAll())
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	for _, want := range []string{
+		`"valid":false`,
+		`"field":"overrides.prod.regions[1]"`,
+		`"code":"invalid_format"`,
+		`"value":"moon-1"`,
+		`"suggestion":"use a region code such as us-east-1"`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON missing %s:\n%s", want, data)
+		}
+	}
+
+	var decoded ValidationReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if len(decoded.Errors) != 5 || decoded.Errors[2].Value != "moon-1" {
+		t.Errorf("Round trip lost problems: %+v", decoded.Errors)
+	}
+
+	// A valid report still has an (empty) errors list
+	data, _ = json.Marshal(&ValidationReport{})
+	if string(data) != `{"valid":true,"errors":[]}` {
+		t.Errorf("Valid report JSON = %s", data)
+	}
+}
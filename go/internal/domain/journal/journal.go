@@ -0,0 +1,241 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Run journal for resumable setup.
+//
+// Every completed step is recorded (and persisted through a
+// ports.JournalStore) as soon as it finishes. When a run is restarted with
+// the same run ID, the orchestrator consults the journal and skips steps
+// that already completed instead of repeating them.
+//
+// Hexagonal Architecture:
+//   - This is DOMAIN LOGIC (what to record, how to interpret it)
+//   - Storage is a PORT (ports.JournalStore)
+//   - The mock adapter provides an in-memory store for tests
+
+// Step identifies one resumable unit of work for an environment.
+type Step string
+
+const (
+	// StepAccountCreated means CreateAccount returned an account ID.
+	// If StepAccountReady is missing, creation is still in flight and a
+	// restart resumes waiting on that ID instead of issuing a new request.
+	// The entry is kept afterwards: it records that this tool created the
+	// account, as opposed to reusing one that already existed.
+	StepAccountCreated Step = "account-created"
+
+	// StepAccountReady means the account finished creating (or was reused).
+	StepAccountReady Step = "account-ready"
+
+	// StepAccountPlaced means the account was verified (or moved) to sit in
+	// the configured OU. ResourceID holds the OU ID.
+	StepAccountPlaced Step = "account-placed"
+
+	// StepOIDCCreated means the GitHub OIDC provider exists in the account.
+	StepOIDCCreated Step = "oidc-created"
+
+	// StepRoleCreated means the GitHub Actions deploy role exists.
+	StepRoleCreated Step = "role-created"
+
+	// StepCDKBootstrapped means CDK bootstrap completed in the account.
+	// Use CDKBootstrapStep to record it per region.
+	StepCDKBootstrapped Step = "cdk-bootstrapped"
+
+	// StepBudgetCreated means the account's budget exists.
+	StepBudgetCreated Step = "budget-created"
+)
+
+// CDKBootstrapStep returns the step recording CDK bootstrap in one region
+// (e.g., "cdk-bootstrapped:eu-west-1"), since an account can be
+// bootstrapped in several.
+func CDKBootstrapStep(region string) Step {
+	return StepCDKBootstrapped + Step(":"+region)
+}
+
+// Journal tracks completed steps for one run.
+//
+// A nil *Journal is valid and records nothing, so orchestration code can
+// call it unconditionally. Safe for concurrent use.
+type Journal struct {
+	mu    sync.Mutex
+	store ports.JournalStore
+	run   ports.RunJournal
+	clock ports.Clock
+}
+
+// Open loads the journal for runID from store, or starts an empty one if
+// none exists yet.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - store: Where the journal is persisted
+//   - runID: Stable identifier for the run (the project code works well)
+//
+// Returns:
+//   - The journal, ready to consult and record into
+//   - Error if an existing journal cannot be loaded
+func Open(ctx context.Context, store ports.JournalStore, runID string) (*Journal, error) {
+	if runID == "" {
+		return nil, fmt.Errorf("journal run ID must not be empty")
+	}
+
+	existing, err := store.LoadJournal(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run journal %s: %w", runID, err)
+	}
+
+	j := &Journal{
+		store: store,
+		run:   ports.RunJournal{RunID: runID},
+		clock: ports.SystemClock(),
+	}
+	if existing != nil {
+		j.run.Entries = append(j.run.Entries, existing.Entries...)
+		j.run.UpdatedAt = existing.UpdatedAt
+	}
+
+	return j, nil
+}
+
+// SetClock replaces the clock that stamps recorded entries. Defaults to
+// the system clock.
+func (j *Journal) SetClock(clock ports.Clock) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.clock = clock
+}
+
+// RunID returns the identifier of the run this journal belongs to.
+func (j *Journal) RunID() string {
+	if j == nil {
+		return ""
+	}
+	return j.run.RunID
+}
+
+// Lookup returns the entry for a completed step, if recorded.
+func (j *Journal) Lookup(env string, step Step) (ports.JournalEntry, bool) {
+	if j == nil {
+		return ports.JournalEntry{}, false
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, entry := range j.run.Entries {
+		if entry.Environment == env && entry.Step == string(step) {
+			return entry, true
+		}
+	}
+	return ports.JournalEntry{}, false
+}
+
+// Record marks a step as completed and persists the journal.
+//
+// Recording a step that is already present replaces the earlier entry.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - env: Environment the step applied to
+//   - step: The completed step
+//   - accountID: AWS Account ID the step applied to
+//   - resourceID: ARN or name produced by the step (empty if none)
+//
+// Returns:
+//   - Error if the journal cannot be persisted
+func (j *Journal) Record(ctx context.Context, env string, step Step, accountID, resourceID string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := j.clock.Now()
+	entry := ports.JournalEntry{
+		Environment: env,
+		Step:        string(step),
+		AccountID:   accountID,
+		ResourceID:  resourceID,
+		CompletedAt: now,
+	}
+
+	replaced := false
+	for i, existing := range j.run.Entries {
+		if existing.Environment == env && existing.Step == string(step) {
+			j.run.Entries[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		j.run.Entries = append(j.run.Entries, entry)
+	}
+	j.run.UpdatedAt = now
+
+	// Save a copy so the store never aliases our slice
+	snapshot := j.run
+	snapshot.Entries = append([]ports.JournalEntry(nil), j.run.Entries...)
+
+	if err := j.store.SaveJournal(ctx, &snapshot); err != nil {
+		return fmt.Errorf("failed to save run journal %s: %w", j.run.RunID, err)
+	}
+	return nil
+}
+
+// Forget removes a recorded step and persists the journal.
+//
+// Used when a step is undone (e.g., during rollback) so a later run
+// performs it again instead of trusting a stale entry.
+func (j *Journal) Forget(ctx context.Context, env string, step Step) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	kept := j.run.Entries[:0]
+	for _, entry := range j.run.Entries {
+		if entry.Environment != env || entry.Step != string(step) {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == len(j.run.Entries) {
+		return nil
+	}
+	j.run.Entries = kept
+	j.run.UpdatedAt = j.clock.Now()
+
+	snapshot := j.run
+	snapshot.Entries = append([]ports.JournalEntry(nil), j.run.Entries...)
+
+	if err := j.store.SaveJournal(ctx, &snapshot); err != nil {
+		return fmt.Errorf("failed to save run journal %s: %w", j.run.RunID, err)
+	}
+	return nil
+}
+
+// Entries returns a copy of all recorded entries in recording order.
+func (j *Journal) Entries() []ports.JournalEntry {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]ports.JournalEntry, len(j.run.Entries))
+	copy(entries, j.run.Entries)
+	return entries
+}
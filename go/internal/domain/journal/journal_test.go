@@ -0,0 +1,120 @@
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+)
+
+func TestJournalRecordAndReload(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewJournalStore()
+
+	j, err := Open(ctx, store, "TPA")
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+
+	if _, ok := j.Lookup("dev", StepAccountCreated); ok {
+		t.Fatal("Fresh journal should have no entries")
+	}
+
+	if err := j.Record(ctx, "dev", StepAccountCreated, "100000000001", "TPA_DEV"); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+	if store.SaveCount() != 1 {
+		t.Errorf("Record() should persist immediately, saves = %d", store.SaveCount())
+	}
+
+	// Simulate a restart: open a new journal from the same store
+	reopened, err := Open(ctx, store, "TPA")
+	if err != nil {
+		t.Fatalf("Open() after restart failed: %v", err)
+	}
+
+	entry, ok := reopened.Lookup("dev", StepAccountCreated)
+	if !ok {
+		t.Fatal("Reopened journal should contain the recorded step")
+	}
+	if entry.AccountID != "100000000001" || entry.ResourceID != "TPA_DEV" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.CompletedAt.IsZero() {
+		t.Error("Entry should carry a completion time")
+	}
+
+	// Steps are tracked per environment
+	if _, ok := reopened.Lookup("staging", StepAccountCreated); ok {
+		t.Error("Step recorded for dev must not appear for staging")
+	}
+}
+
+func TestJournalRecordReplacesExistingStep(t *testing.T) {
+	ctx := context.Background()
+	j, err := Open(ctx, mock.NewJournalStore(), "TPA")
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+
+	_ = j.Record(ctx, "dev", StepRoleCreated, "100000000001", "old-arn")
+	_ = j.Record(ctx, "dev", StepRoleCreated, "100000000001", "new-arn")
+
+	if got := len(j.Entries()); got != 1 {
+		t.Errorf("Expected 1 entry, got %d", got)
+	}
+	if entry, _ := j.Lookup("dev", StepRoleCreated); entry.ResourceID != "new-arn" {
+		t.Errorf("ResourceID = %s, want new-arn", entry.ResourceID)
+	}
+}
+
+func TestNilJournalIsNoOp(t *testing.T) {
+	var j *Journal
+
+	if err := j.Record(context.Background(), "dev", StepAccountReady, "1", ""); err != nil {
+		t.Errorf("Record() on nil journal should be a no-op, got %v", err)
+	}
+	if _, ok := j.Lookup("dev", StepAccountReady); ok {
+		t.Error("Lookup() on nil journal should find nothing")
+	}
+}
+
+func TestOpenRequiresRunID(t *testing.T) {
+	if _, err := Open(context.Background(), mock.NewJournalStore(), ""); err == nil {
+		t.Error("Open() should reject an empty run ID")
+	}
+}
+
+func TestJournalForget(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewJournalStore()
+	j, _ := Open(ctx, store, "TPA")
+
+	_ = j.Record(ctx, "dev", StepRoleCreated, "100000000001", "arn")
+	_ = j.Record(ctx, "dev", StepBudgetCreated, "100000000001", "budget")
+
+	if err := j.Forget(ctx, "dev", StepRoleCreated); err != nil {
+		t.Fatalf("Forget() failed: %v", err)
+	}
+
+	reopened, _ := Open(ctx, store, "TPA")
+	if _, ok := reopened.Lookup("dev", StepRoleCreated); ok {
+		t.Error("Forgotten step should not survive a reload")
+	}
+	if _, ok := reopened.Lookup("dev", StepBudgetCreated); !ok {
+		t.Error("Other steps must be kept")
+	}
+}
+
+func TestJournalStampsEntriesWithClock(t *testing.T) {
+	ctx := context.Background()
+	clock := mock.NewClock(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	j, _ := Open(ctx, mock.NewJournalStore(), "TPA")
+	j.SetClock(clock)
+
+	_ = j.Record(ctx, "dev", StepAccountCreated, "100000000001", "TPA_DEV")
+	if entry, _ := j.Lookup("dev", StepAccountCreated); !entry.CompletedAt.Equal(clock.Now()) {
+		t.Errorf("CompletedAt = %v, want %v", entry.CompletedAt, clock.Now())
+	}
+}
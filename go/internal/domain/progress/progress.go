@@ -0,0 +1,177 @@
+package progress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Progress events for orchestration.
+//
+// Orchestration code brackets each step with Start and Succeed/Fail; the
+// Tracker stamps events with the time and the step's duration and hands
+// them to a ports.ProgressReporter.
+//
+// Hexagonal Architecture:
+//   - This is DOMAIN LOGIC (which steps exist, when they start and end)
+//   - Rendering is a PORT (ports.ProgressReporter)
+//   - internal/adapters/progress renders to a terminal, NDJSON or channels
+
+// DefaultHeartbeat is how often a waiting step reports EventPolling.
+const DefaultHeartbeat = 15 * time.Second
+
+// Tracker emits progress events.
+//
+// A nil *Tracker is valid and reports nothing, so orchestration code can
+// call it unconditionally. Safe for concurrent use.
+type Tracker struct {
+	reporter  ports.ProgressReporter
+	heartbeat time.Duration
+	clock     ports.Clock
+}
+
+// New creates a tracker that sends events to reporter, or nil if reporter
+// is nil.
+func New(reporter ports.ProgressReporter) *Tracker {
+	if reporter == nil {
+		return nil
+	}
+	return &Tracker{reporter: reporter, heartbeat: DefaultHeartbeat, clock: ports.SystemClock()}
+}
+
+// WithHeartbeat returns a copy of t that reports EventPolling every d
+// while waiting.
+func (t *Tracker) WithHeartbeat(d time.Duration) *Tracker {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	copied.heartbeat = d
+	return &copied
+}
+
+// WithClock returns a copy of t that reads event times and waits for
+// heartbeats on clock instead of the system clock.
+func (t *Tracker) WithClock(clock ports.Clock) *Tracker {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	copied.clock = clock
+	return &copied
+}
+
+// Reused reports that a step had nothing to do.
+func (t *Tracker) Reused(env, step, accountName, accountID string) {
+	if t == nil {
+		return
+	}
+	t.reporter.Report(ports.ProgressEvent{
+		Type:        ports.EventReused,
+		Time:        t.clock.Now(),
+		Environment: env,
+		Step:        step,
+		AccountName: accountName,
+		AccountID:   accountID,
+	})
+}
+
+// Start reports that a step started and returns it, to report its end.
+func (t *Tracker) Start(env, step, accountName, accountID string) *Step {
+	if t == nil {
+		return nil
+	}
+	s := &Step{
+		tracker: t,
+		event: ports.ProgressEvent{
+			Environment: env,
+			Step:        step,
+			AccountName: accountName,
+			AccountID:   accountID,
+		},
+		started: t.clock.Now(),
+	}
+	s.emit(ports.EventStepStarted, "", nil)
+	return s
+}
+
+// Step is a step in progress. A nil *Step reports nothing.
+type Step struct {
+	tracker *Tracker
+	event   ports.ProgressEvent
+	started time.Time
+}
+
+// Succeed reports that the step finished. accountID, if set, is the
+// account the step produced (e.g., by CreateAccount).
+func (s *Step) Succeed(accountID string) {
+	if s == nil {
+		return
+	}
+	s.emit(ports.EventStepSucceeded, accountID, nil)
+}
+
+// Fail reports that the step failed with err.
+func (s *Step) Fail(err error) {
+	if s == nil {
+		return
+	}
+	s.emit(ports.EventStepFailed, "", err)
+}
+
+// End reports Succeed or Fail depending on err, and returns err.
+func (s *Step) End(err error) error {
+	if err != nil {
+		s.Fail(err)
+	} else {
+		s.Succeed("")
+	}
+	return err
+}
+
+// Wait reports EventPolling now and then every heartbeat until the
+// returned stop function is called.
+func (s *Step) Wait() (stop func()) {
+	if s == nil {
+		return func() {}
+	}
+	s.emit(ports.EventPolling, "", nil)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case <-s.tracker.clock.After(s.tracker.heartbeat):
+				s.emit(ports.EventPolling, "", nil)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+func (s *Step) emit(typ ports.ProgressEventType, accountID string, err error) {
+	event := s.event
+	event.Type = typ
+	event.Time = s.tracker.clock.Now()
+	event.Err = err
+	if accountID != "" {
+		event.AccountID = accountID
+	}
+	if typ != ports.EventStepStarted {
+		event.Duration = event.Time.Sub(s.started)
+	}
+	s.tracker.reporter.Report(event)
+}
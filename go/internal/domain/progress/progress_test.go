@@ -0,0 +1,119 @@
+package progress
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func TestNilTrackerReportsNothing(t *testing.T) {
+	var tracker *Tracker
+	if New(nil) != nil {
+		t.Fatal("New(nil) should return a nil tracker")
+	}
+
+	// None of these may panic
+	tracker.Reused("dev", "create", "TPA_DEV", "100000000001")
+	step := tracker.WithHeartbeat(time.Millisecond).Start("dev", "create", "TPA_DEV", "")
+	step.Wait()()
+	step.Succeed("100000000001")
+	if err := step.End(errors.New("boom")); err == nil {
+		t.Error("End() should return its error")
+	}
+}
+
+func TestStepEvents(t *testing.T) {
+	recorder := mock.NewProgressRecorder()
+	tracker := New(recorder).WithHeartbeat(time.Millisecond)
+
+	step := tracker.Start("dev", "wait", "TPA_DEV", "100000000001")
+	stop := step.Wait()
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	step.End(errors.New("account creation failed"))
+
+	events := recorder.Events()
+	if len(events) < 4 {
+		t.Fatalf("Expected started, at least two polls and failed, got %d events", len(events))
+	}
+	if events[0].Type != ports.EventStepStarted || events[0].Duration != 0 {
+		t.Errorf("first event = %+v, want step-started", events[0])
+	}
+	for _, e := range events[1 : len(events)-1] {
+		if e.Type != ports.EventPolling {
+			t.Errorf("event %+v, want polling", e)
+		}
+	}
+	last := events[len(events)-1]
+	if last.Type != ports.EventStepFailed || last.Err == nil || last.Duration < 10*time.Millisecond {
+		t.Errorf("last event = %+v, want step-failed with the wait's duration", last)
+	}
+	if last.Environment != "dev" || last.Step != "wait" || last.AccountName != "TPA_DEV" || last.AccountID != "100000000001" {
+		t.Errorf("last event = %+v, want the step's identity", last)
+	}
+
+	// Nothing is reported after stop
+	count := len(recorder.Events())
+	time.Sleep(5 * time.Millisecond)
+	if len(recorder.Events()) != count {
+		t.Error("Heartbeat kept reporting after stop")
+	}
+}
+
+// manualClock fires heartbeats only when the test calls tick.
+type manualClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	fire chan time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) After(time.Duration) <-chan time.Time { return c.fire }
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// tick advances the clock and hands one heartbeat to the waiting step.
+func (c *manualClock) tick(d time.Duration) {
+	c.advance(d)
+	c.fire <- c.Now()
+}
+
+func TestStepEventsOnClock(t *testing.T) {
+	clock := &manualClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), fire: make(chan time.Time)}
+	recorder := mock.NewProgressRecorder()
+	tracker := New(recorder).WithHeartbeat(10 * time.Second).WithClock(clock)
+
+	step := tracker.Start("dev", "wait", "TPA_DEV", "100000000001")
+	stop := step.Wait()
+	clock.tick(10 * time.Second)
+	clock.tick(10 * time.Second)
+	stop()
+	clock.advance(5 * time.Second)
+	step.Succeed("")
+
+	var durations []time.Duration
+	for _, e := range recorder.Events() {
+		durations = append(durations, e.Duration)
+	}
+	want := []time.Duration{0, 0, 10 * time.Second, 20 * time.Second, 25 * time.Second}
+	if !slices.Equal(durations, want) {
+		t.Errorf("durations = %v, want %v", durations, want)
+	}
+	if last := recorder.Events()[4]; !last.Time.Equal(clock.Now()) || last.Type != ports.EventStepSucceeded {
+		t.Errorf("last event = %+v, want step-succeeded at the clock's time", last)
+	}
+}
@@ -0,0 +1,249 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Adopting existing accounts.
+//
+// Accounts created by hand (or by another tool) are normally invisible to
+// SetupProject unless their name happens to match the naming convention.
+// AdoptAccount records such an account against an environment in the run
+// journal and the project state, after checking that the tool can actually
+// work in it. A later SetupProject with the same journal or state store
+// then reuses it instead of creating a new account, and DetectDrift and
+// DecommissionProject find it by its recorded ID.
+
+// ErrAccountNotFound means no account matched the adoption request.
+var ErrAccountNotFound = errors.New("account not found in organization")
+
+// AdoptRequest identifies the account to adopt. Exactly one of AccountID,
+// Email or Tag must be set.
+type AdoptRequest struct {
+	Environment account.Environment // Environment the account will serve
+
+	AccountID string // Adopt this account
+	Email     string // Or: find the account by root email (case-insensitive)
+	TagKey    string // Or: find the single account tagged TagKey=TagValue
+	TagValue  string
+
+	// AccountName accepts an account whose name doesn't follow the naming
+	// convention. It must equal the account's actual name, so a typo can't
+	// adopt the wrong account.
+	AccountName string
+}
+
+// AdoptResult describes an adopted account.
+type AdoptResult struct {
+	Environment    account.Environment
+	AccountID      string
+	AccountName    string // Actual account name
+	Email          string
+	ConventionName string // Name the naming convention expects
+	ParentID       string // Current root or OU; setup moves it into the environment's OU
+	Missing        []Drift
+}
+
+// AdoptAccount brings an existing account under management.
+//
+// This is DOMAIN LOGIC:
+//  1. Resolve the account (by ID, root email or tag) in the organization
+//  2. Require it to be ACTIVE and named by the convention, unless
+//     AccountName explicitly accepts its actual name
+//  3. Verify access by assuming the organization access role in it
+//  4. Record it as the environment's account (journal.StepAccountReady and
+//     the project state), under its actual name
+//  5. Report which baseline resources (OIDC provider, deploy role, CDK
+//     bootstrap, budget) SetupProject still has to create
+//
+// Only WithJournal and WithStateStore apply. Without either nothing is
+// recorded, which makes AdoptAccount a read-only check. An environment
+// already recorded with a different account (in either) is refused.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - aws: AWS client implementation
+//   - config: Project configuration (defaults are applied)
+//   - req: Which account to adopt, and for which environment
+//   - opts: Optional settings (only WithJournal and WithStateStore apply)
+//
+// Returns:
+//   - AdoptResult describing the account and what it still lacks
+//   - Error if the account can't be found, accessed or recorded
+func AdoptAccount(
+	ctx context.Context,
+	aws ports.AWSClient,
+	config Config,
+	req AdoptRequest,
+	opts ...Option,
+) (result *AdoptResult, err error) {
+	config = config.WithDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	registry, err := config.Account.EnvironmentRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := registry.Validate(req.Environment); err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+
+	acct, err := findAccount(ctx, aws, req)
+	if err != nil {
+		return nil, err
+	}
+	if acct.Status != ports.AccountStatusActive {
+		return nil, fmt.Errorf("cannot adopt account %s (%s): status is %s", acct.Name, acct.ID, acct.Status)
+	}
+
+	result = &AdoptResult{
+		Environment:    req.Environment,
+		AccountID:      acct.ID,
+		AccountName:    acct.Name,
+		Email:          acct.Email,
+		ConventionName: config.Account.ResourceNames(req.Environment).Account,
+	}
+
+	switch {
+	case req.AccountName != "" && req.AccountName != acct.Name:
+		return nil, fmt.Errorf("account %s is named %q, not %q", acct.ID, acct.Name, req.AccountName)
+	case req.AccountName == "" && acct.Name != result.ConventionName:
+		return nil, fmt.Errorf("account %s is named %q but the naming convention expects %q (set AccountName to adopt it anyway)",
+			acct.ID, acct.Name, result.ConventionName)
+	}
+
+	if entry, ok := o.journal.Lookup(string(req.Environment), journal.StepAccountReady); ok && entry.AccountID != acct.ID {
+		return nil, fmt.Errorf("%s is already recorded as account %s", req.Environment, entry.AccountID)
+	}
+
+	var state *ports.ProjectState
+	projectCode := config.Account.ProjectCode
+	if o.stateStore != nil {
+		var unlock func(context.Context) error
+		unlock, err = lockState(ctx, o.stateStore, projectCode)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+				result, err = nil, fmt.Errorf("failed to unlock state: %w", unlockErr)
+			}
+		}()
+
+		state, err = o.stateStore.LoadState(ctx, projectCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load state for project %s: %w", projectCode, err)
+		}
+		if state != nil {
+			if recorded := state.Environments[string(req.Environment)]; recorded.AccountID != "" && recorded.AccountID != acct.ID {
+				return nil, fmt.Errorf("%s is already recorded as account %s", req.Environment, recorded.AccountID)
+			}
+		}
+	}
+
+	roleName := account.GetOrganizationAccessRoleName()
+	roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", acct.ID, roleName)
+	if _, err := aws.AssumeRole(ctx, roleARN, "aws-bootstrap-adopt"); err != nil {
+		return nil, fmt.Errorf("cannot access account %s through %s: %w", acct.ID, roleName, err)
+	}
+
+	result.ParentID, err = aws.GetAccountParent(ctx, acct.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check OU of AWS account %s: %w", acct.ID, err)
+	}
+
+	drifts, err := environmentDrift(ctx, aws, config, req.Environment, acct.ID, acct.Name, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drifts {
+		// Name and OU are reported above; setup fixes the OU itself
+		if d.Resource != ResourceAccount {
+			result.Missing = append(result.Missing, d)
+		}
+	}
+
+	if err := o.journal.Record(ctx, string(req.Environment), journal.StepAccountReady, acct.ID, acct.Name); err != nil {
+		return nil, err
+	}
+	if o.stateStore != nil {
+		state = RecordAdoption(state, config, result, time.Now().UTC())
+		if err := o.stateStore.SaveState(ctx, state); err != nil {
+			return nil, fmt.Errorf("failed to save state for project %s: %w", projectCode, err)
+		}
+	}
+	return result, nil
+}
+
+// findAccount resolves the request to exactly one organization account.
+func findAccount(ctx context.Context, aws ports.AWSClient, req AdoptRequest) (*ports.AWSAccount, error) {
+	set := 0
+	for _, s := range []string{req.AccountID, req.Email, req.TagKey} {
+		if s != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errors.New("adopt request must set exactly one of AccountID, Email or TagKey")
+	}
+
+	if req.AccountID != "" {
+		acct, err := aws.DescribeAccount(ctx, req.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe AWS account %s: %w", req.AccountID, err)
+		}
+		if acct == nil {
+			return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, req.AccountID)
+		}
+		return acct, nil
+	}
+
+	accounts, err := aws.ListAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization accounts: %w", err)
+	}
+
+	var matches []ports.AWSAccount
+	for _, acct := range accounts {
+		if req.Email != "" {
+			if strings.EqualFold(acct.Email, req.Email) {
+				matches = append(matches, acct)
+			}
+			continue
+		}
+		tags, err := aws.GetAccountTags(ctx, acct.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tags of AWS account %s: %w", acct.ID, err)
+		}
+		if value, ok := tags[req.TagKey]; ok && value == req.TagValue {
+			matches = append(matches, acct)
+		}
+	}
+
+	what := "email " + req.Email
+	if req.Email == "" {
+		what = fmt.Sprintf("tag %s=%s", req.TagKey, req.TagValue)
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: no account with %s", ErrAccountNotFound, what)
+	case 1:
+		return &matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, acct := range matches {
+			ids[i] = acct.ID
+		}
+		return nil, fmt.Errorf("%d accounts match %s (%s); adopt one by AccountID", len(matches), what, strings.Join(ids, ", "))
+	}
+}
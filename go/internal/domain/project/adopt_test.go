@@ -0,0 +1,245 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// handMadeAccount creates an account the way someone clicking through the
+// console would: any name, left in the root, tagged by hand.
+func handMadeAccount(t *testing.T, aws *mock.AWSClient, name, email string, tags map[string]string) string {
+	t.Helper()
+	ctx := context.Background()
+	accountID, err := aws.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: name, Email: email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) > 0 {
+		if err := aws.TagAccount(ctx, accountID, tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return accountID
+}
+
+func TestAdoptAccount(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	legacyID := handMadeAccount(t, mockAWS, "legacy-dev", "legacy-dev@example.com", map[string]string{"team": "tpa-dev"})
+	j, _ := journal.Open(ctx, mock.NewJournalStore(), "TPA")
+
+	result, err := AdoptAccount(ctx, mockAWS, testConfig(), AdoptRequest{
+		Environment: account.EnvironmentDev,
+		TagKey:      "team",
+		TagValue:    "tpa-dev",
+		AccountName: "legacy-dev",
+	}, WithJournal(j))
+	if err != nil {
+		t.Fatalf("AdoptAccount() failed: %v", err)
+	}
+
+	if result.AccountID != legacyID || result.ConventionName != "TPA_DEV" || result.ParentID != mock.RootID {
+		t.Errorf("AdoptAccount() = %+v", result)
+	}
+	var missing []string
+	for _, d := range result.Missing {
+		missing = append(missing, string(d.Resource))
+	}
+	if got := strings.Join(missing, ","); got != "oidc-provider,deploy-role,cdk-bootstrap,budget" {
+		t.Errorf("Missing = %s, want every baseline resource", got)
+	}
+
+	if entry, ok := j.Lookup("dev", journal.StepAccountReady); !ok || entry.AccountID != legacyID {
+		t.Errorf("Journal entry = %+v, want dev recorded as %s", entry, legacyID)
+	}
+
+	// Setup picks the adopted account up instead of creating TPA_DEV
+	setupResult, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(j))
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	if got := setupResult.Environments[0].Account; got.AccountID != legacyID || got.Name != "legacy-dev" {
+		t.Errorf("dev account = %s (%s), want adopted %s (legacy-dev)", got.AccountID, got.Name, legacyID)
+	}
+	if parent, _ := mockAWS.GetAccountParent(ctx, legacyID); parent != "ou-813y-8teevv2l" {
+		t.Errorf("adopted account parent = %s, want the project OU", parent)
+	}
+	for _, op := range mockAWS.GetOperations() {
+		if strings.HasPrefix(op, "CreateAccount(TPA_DEV") {
+			t.Errorf("Setup created a new dev account: %s", op)
+		}
+	}
+}
+
+func TestAdoptAccountRefusals(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		req     AdoptRequest
+		prepare func(aws *mock.AWSClient)
+		want    string
+	}{
+		{
+			name: "name outside the convention",
+			req:  AdoptRequest{Environment: account.EnvironmentDev, Email: "legacy-dev@example.com"},
+			want: `convention expects "TPA_DEV"`,
+		},
+		{
+			name: "override doesn't match",
+			req:  AdoptRequest{Environment: account.EnvironmentDev, Email: "legacy-dev@example.com", AccountName: "legacy-prod"},
+			want: `named "legacy-dev", not "legacy-prod"`,
+		},
+		{
+			name: "no access",
+			req:  AdoptRequest{Environment: account.EnvironmentDev, Email: "legacy-dev@example.com", AccountName: "legacy-dev"},
+			prepare: func(aws *mock.AWSClient) {
+				aws.InjectError("AssumeRole", errors.New("AccessDenied"))
+			},
+			want: "cannot access account",
+		},
+		{
+			name: "closed account",
+			req:  AdoptRequest{Environment: account.EnvironmentDev, Email: "legacy-dev@example.com", AccountName: "legacy-dev"},
+			prepare: func(aws *mock.AWSClient) {
+				accounts, _ := aws.ListAccounts(ctx)
+				aws.CloseAccount(ctx, accounts[0].ID)
+			},
+			want: "status is SUSPENDED",
+		},
+		{
+			name: "ambiguous tag",
+			req:  AdoptRequest{Environment: account.EnvironmentDev, TagKey: "owner", TagValue: "tpa"},
+			want: "2 accounts match tag owner=tpa",
+		},
+		{
+			name: "unknown environment",
+			req:  AdoptRequest{Environment: "qa", Email: "legacy-dev@example.com"},
+			want: "must be one of",
+		},
+		{
+			name: "no selector",
+			req:  AdoptRequest{Environment: account.EnvironmentDev},
+			want: "exactly one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAWS := mock.NewAWSClient()
+			handMadeAccount(t, mockAWS, "legacy-dev", "legacy-dev@example.com", map[string]string{"owner": "tpa"})
+			handMadeAccount(t, mockAWS, "legacy-prod", "legacy-prod@example.com", map[string]string{"owner": "tpa"})
+			if tt.prepare != nil {
+				tt.prepare(mockAWS)
+			}
+			j, _ := journal.Open(ctx, mock.NewJournalStore(), "TPA")
+
+			_, err := AdoptAccount(ctx, mockAWS, testConfig(), tt.req, WithJournal(j))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("AdoptAccount() = %v, want error containing %q", err, tt.want)
+			}
+			if entries := j.Entries(); len(entries) != 0 {
+				t.Errorf("Refused adoption recorded %v", entries)
+			}
+		})
+	}
+}
+
+func TestAdoptAccountByID(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	accountID := handMadeAccount(t, mockAWS, "TPA_PROD", "ops@example.com", nil)
+	j, _ := journal.Open(ctx, mock.NewJournalStore(), "TPA")
+
+	// A conventionally named account needs no override
+	if _, err := AdoptAccount(ctx, mockAWS, testConfig(), AdoptRequest{Environment: account.EnvironmentProd, AccountID: accountID}, WithJournal(j)); err != nil {
+		t.Fatalf("AdoptAccount() failed: %v", err)
+	}
+
+	// Adopting again is harmless; adopting another account for prod is not
+	if _, err := AdoptAccount(ctx, mockAWS, testConfig(), AdoptRequest{Environment: account.EnvironmentProd, AccountID: accountID}, WithJournal(j)); err != nil {
+		t.Errorf("re-adopting the same account failed: %v", err)
+	}
+	otherID := handMadeAccount(t, mockAWS, "other", "other@example.com", nil)
+	if _, err := AdoptAccount(ctx, mockAWS, testConfig(), AdoptRequest{Environment: account.EnvironmentProd, AccountID: otherID, AccountName: "other"}, WithJournal(j)); err == nil {
+		t.Error("AdoptAccount() should refuse a second account for prod")
+	}
+
+	if _, err := AdoptAccount(ctx, mockAWS, testConfig(), AdoptRequest{Environment: account.EnvironmentDev, AccountID: "555555555555"}); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("AdoptAccount() = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestAdoptAccountRecordsState(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	store := mock.NewStateStore()
+	legacyID := handMadeAccount(t, mockAWS, "legacy-dev", "legacy-dev@example.com", nil)
+
+	// No journal: the state store alone carries the adoption
+	if _, err := AdoptAccount(ctx, mockAWS, testConfig(), AdoptRequest{
+		Environment: account.EnvironmentDev,
+		AccountID:   legacyID,
+		AccountName: "legacy-dev",
+	}, WithStateStore(store)); err != nil {
+		t.Fatalf("AdoptAccount() failed: %v", err)
+	}
+	state, _ := store.LoadState(ctx, "TPA")
+	if dev := state.Environments["dev"]; dev.AccountID != legacyID || dev.AccountName != "legacy-dev" {
+		t.Fatalf("Recorded dev = %+v, want %s (legacy-dev)", dev, legacyID)
+	}
+
+	// Another account for dev is refused
+	otherID := handMadeAccount(t, mockAWS, "other", "other@example.com", nil)
+	if _, err := AdoptAccount(ctx, mockAWS, testConfig(), AdoptRequest{Environment: account.EnvironmentDev, AccountID: otherID, AccountName: "other"}, WithStateStore(store)); err == nil {
+		t.Error("AdoptAccount() should refuse a second account for dev")
+	}
+
+	// A fresh journal still reuses the adopted account, under its own name
+	j, _ := journal.Open(ctx, mock.NewJournalStore(), "TPA")
+	setupResult, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(j), WithStateStore(store))
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	if dev := setupResult.Environments[0].Account; dev.AccountID != legacyID || dev.Name != "legacy-dev" {
+		t.Errorf("dev account = %s (%s), want adopted %s (legacy-dev)", dev.AccountID, dev.Name, legacyID)
+	}
+	if n := mockAWS.Operations().Method("CreateAccount").Where(func(op mock.Operation) bool {
+		return strings.Contains(op.String(), "TPA_DEV")
+	}).Count(); n != 0 {
+		t.Errorf("Setup created %d new dev accounts", n)
+	}
+
+	// Drift checks the adopted account by its recorded ID and name
+	state, _ = store.LoadState(ctx, "TPA")
+	if name := state.Environments["dev"].AccountName; name != "legacy-dev" {
+		t.Errorf("Recorded dev name = %q after setup, want legacy-dev", name)
+	}
+	report, err := DetectDrift(ctx, mockAWS, testConfig(), RecordedStateFrom(state))
+	if err != nil {
+		t.Fatalf("DetectDrift() failed: %v", err)
+	}
+	if report.HasDrift() {
+		t.Errorf("Adopted account should have no drift:\n%s", report.Summary())
+	}
+
+	// Decommissioning finds it through the state, then forgets it
+	result, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU,
+		WithConfirmation(typeAccountName), WithDecommissionStateStore(store))
+	if err != nil {
+		t.Fatalf("DecommissionProject() failed: %v", err)
+	}
+	if dev := result.Environments[0]; dev.AccountID != legacyID || dev.AccountName != "legacy-dev" || dev.SuspendedOUID != suspendedOU {
+		t.Errorf("Decommissioned dev = %+v, want %s (legacy-dev) suspended", dev, legacyID)
+	}
+	if state, _ = store.LoadState(ctx, "TPA"); len(state.Environments) != 0 {
+		t.Errorf("State should be empty after decommissioning, got %v", state.Environments)
+	}
+}
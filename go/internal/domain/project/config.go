@@ -0,0 +1,232 @@
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+)
+
+// Business rules for complete project setup.
+//
+// A project is the set of AWS accounts for one application plus everything
+// GitHub Actions needs to deploy into them: OIDC trust, deploy roles, CDK
+// bootstrap, and billing guard rails. This is the Go equivalent of
+// setup-complete-project.sh.
+
+// Defaults matching the v1 bash scripts.
+const (
+	DefaultRegion         = "us-east-1"
+	DefaultMonthlyLimit   = 25.00
+	DefaultAlertThreshold = 15.00
+	AdministratorAccess   = "arn:aws:iam::aws:policy/AdministratorAccess"
+)
+
+// DefaultAlertPercents are the budget notification thresholds added on top
+// of the alert threshold (same as setup-billing-alerts.sh).
+func DefaultAlertPercents() []int {
+	return []int{90, 100}
+}
+
+// Config represents the configuration for a complete project setup.
+type Config struct {
+	Account account.Config // Accounts to create (project code, email, OU, environments)
+
+	GitHubOrg  string // GitHub organization or user that owns the repository
+	GitHubRepo string // GitHub repository name
+
+	Region           string   // Region for CDK bootstrap (default: us-east-1; environments may list their own regions)
+	RoleName         string   // Deploy role name (default: from the account naming convention, GitHubActionsDeployRole in v1)
+	PolicyARNs       []string // Policies attached to the deploy role (default: AdministratorAccess; overridable per environment)
+	AllowAllBranches bool     // If false, only main/develop may assume the deploy role
+
+	Billing BillingConfig
+}
+
+// BillingConfig holds the budget and alarm settings applied to every
+// environment, unless the environment's definition or override block
+// changes them (see account.EnvironmentSettings).
+type BillingConfig struct {
+	MonthlyLimit      float64 // Budget limit in USD (default: 25)
+	AlertThreshold    float64 // Alarm/alert amount in USD (default: 15)
+	AlertPercents     []int   // Additional budget alert percentages (default: 90, 100)
+	NotificationEmail string  // Where alerts go (default: the mailbox behind the account emails)
+}
+
+var githubNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// WithDefaults returns a copy of the config with unset fields filled in.
+func (c Config) WithDefaults() Config {
+	if c.Region == "" {
+		c.Region = DefaultRegion
+	}
+	if len(c.PolicyARNs) == 0 {
+		c.PolicyARNs = []string{AdministratorAccess}
+	}
+	if c.Billing.MonthlyLimit == 0 {
+		c.Billing.MonthlyLimit = DefaultMonthlyLimit
+	}
+	if c.Billing.AlertThreshold == 0 {
+		c.Billing.AlertThreshold = DefaultAlertThreshold
+	}
+	if len(c.Billing.AlertPercents) == 0 {
+		c.Billing.AlertPercents = DefaultAlertPercents()
+	}
+	if c.Billing.NotificationEmail == "" {
+		c.Billing.NotificationEmail = c.Account.Email.Mailbox(c.Account.EmailPrefix)
+	}
+	return c
+}
+
+// Validate validates all fields in the configuration.
+//
+// Call WithDefaults first; zero billing amounts are rejected here.
+//
+// Returns:
+//   - Error if any validation fails, nil if all valid. The error is an
+//     *account.ValidationReport listing every problem; errors.As also
+//     finds the first *account.ValidationError.
+func (c *Config) Validate() error {
+	return c.ValidateAll().Err()
+}
+
+// ValidateAll checks every field, including the account config, and
+// returns all problems found.
+func (c *Config) ValidateAll() *account.ValidationReport {
+	report := c.Account.ValidateAll()
+
+	if !githubNameRegex.MatchString(c.GitHubOrg) {
+		report.Add(&account.ValidationError{
+			Field:   "githubOrg",
+			Code:    githubCode(c.GitHubOrg),
+			Message: "must be a valid GitHub organization or user name",
+			Value:   c.GitHubOrg,
+		})
+	}
+
+	if !githubNameRegex.MatchString(c.GitHubRepo) {
+		report.Add(&account.ValidationError{
+			Field:      "githubRepo",
+			Code:       githubCode(c.GitHubRepo),
+			Message:    "must be a valid GitHub repository name",
+			Value:      c.GitHubRepo,
+			Suggestion: "use the name from the repository URL (letters, digits, '.', '-' and '_')",
+		})
+	}
+
+	validateBilling(report, "billing", c.Billing)
+
+	// Environment defaults and overrides can change the amounts, so check
+	// what each environment will actually get (unless it is the same as
+	// the project-wide billing already checked above). The values may come
+	// from any of those blocks, so problems are reported under the neutral
+	// environments.<env> prefix, with the field names overrides use.
+	envs, err := c.Account.TargetEnvironments()
+	if err != nil {
+		return report // Already reported by the account config
+	}
+	for _, env := range envs {
+		billing := c.settingsFor(env).Billing
+		if billing.MonthlyLimit == c.Billing.MonthlyLimit &&
+			billing.AlertThreshold == c.Billing.AlertThreshold &&
+			billing.NotificationEmail == c.Billing.NotificationEmail &&
+			slices.Equal(billing.AlertPercents, c.Billing.AlertPercents) {
+			continue
+		}
+		validateBilling(report, "environments."+string(env), billing)
+	}
+
+	return report
+}
+
+// githubCode distinguishes a missing GitHub name from a malformed one.
+func githubCode(name string) account.ValidationCode {
+	if name == "" {
+		return account.CodeRequired
+	}
+	return account.CodeInvalidFormat
+}
+
+// validateBilling checks billing amounts, reporting fields under prefix.
+func validateBilling(report *account.ValidationReport, prefix string, billing BillingConfig) {
+	if billing.MonthlyLimit <= 0 {
+		report.Add(&account.ValidationError{
+			Field:   prefix + ".monthlyLimit",
+			Code:    account.CodeOutOfRange,
+			Message: "must be greater than zero",
+			Value:   fmt.Sprintf("%.2f", billing.MonthlyLimit),
+		})
+	} else if billing.AlertThreshold <= 0 || billing.AlertThreshold > billing.MonthlyLimit {
+		report.Add(&account.ValidationError{
+			Field:      prefix + ".alertThreshold",
+			Code:       account.CodeOutOfRange,
+			Message:    fmt.Sprintf("must be between 0 and the monthly limit ($%.2f)", billing.MonthlyLimit),
+			Value:      fmt.Sprintf("%.2f", billing.AlertThreshold),
+			Suggestion: fmt.Sprintf("use at most %.2f or raise the monthly limit", billing.MonthlyLimit),
+		})
+	}
+
+	if billing.NotificationEmail == "" {
+		report.Add(&account.ValidationError{
+			Field:      prefix + ".notificationEmail",
+			Code:       account.CodeRequired,
+			Message:    "is required when account emails don't share one mailbox",
+			Suggestion: "set the address billing alerts should go to",
+		})
+	}
+
+	for i, pct := range billing.AlertPercents {
+		if pct <= 0 || pct > 1000 {
+			report.Add(&account.ValidationError{
+				Field:   fmt.Sprintf("%s.alertPercents[%d]", prefix, i),
+				Code:    account.CodeOutOfRange,
+				Message: "must be between 1 and 1000",
+				Value:   fmt.Sprint(pct),
+			})
+		}
+	}
+}
+
+// roleName returns env's deploy role name: RoleName if set, otherwise the
+// one the naming convention gives.
+func (c Config) roleName(env account.Environment) string {
+	if c.RoleName != "" {
+		return c.RoleName
+	}
+	return c.Account.ResourceNames(env).Role
+}
+
+// envSettings are the settings one environment actually gets.
+type envSettings struct {
+	OUID       string
+	Regions    []string
+	Billing    BillingConfig
+	PolicyARNs []string
+}
+
+// settingsFor resolves env's settings: the project-wide values, then the
+// environment definition's defaults, then the account config's override
+// block for env (see account.Config.SettingsFor).
+func (c Config) settingsFor(env account.Environment) envSettings {
+	resolved := c.Account.SettingsFor(env, account.EnvironmentSettings{
+		NotificationEmail: c.Billing.NotificationEmail,
+		Regions:           []string{c.Region},
+		MonthlyLimit:      c.Billing.MonthlyLimit,
+		AlertThreshold:    c.Billing.AlertThreshold,
+		AlertPercents:     c.Billing.AlertPercents,
+		PolicyARNs:        c.PolicyARNs,
+	})
+
+	return envSettings{
+		OUID:    resolved.OUID,
+		Regions: resolved.Regions,
+		Billing: BillingConfig{
+			MonthlyLimit:      resolved.MonthlyLimit,
+			AlertThreshold:    resolved.AlertThreshold,
+			AlertPercents:     resolved.AlertPercents,
+			NotificationEmail: resolved.NotificationEmail,
+		},
+		PolicyARNs: resolved.PolicyARNs,
+	}
+}
@@ -0,0 +1,409 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Project teardown.
+//
+// Decommissioning undoes SetupProject: it deletes what setup created inside
+// each account, parks the accounts in a suspended OU (typically one with a
+// deny-all SCP) and optionally closes them. Every step checks before it
+// acts, so an interrupted decommission can simply be run again.
+
+// ErrNotConfirmed means a production environment was not confirmed by
+// typing its account name.
+var ErrNotConfirmed = errors.New("decommissioning not confirmed")
+
+// ConfirmationRequest asks the operator to confirm tearing down a
+// production environment.
+type ConfirmationRequest struct {
+	Environment  account.Environment
+	AccountName  string // What the operator must type, e.g. "TPA_PROD"
+	AccountID    string
+	CloseAccount bool // True if the account will also be closed
+}
+
+// ConfirmFunc asks the operator to confirm and returns what they typed.
+type ConfirmFunc func(ctx context.Context, req ConfirmationRequest) (string, error)
+
+// DecommissionOption configures project decommissioning.
+type DecommissionOption func(*decommissionOptions)
+
+type decommissionOptions struct {
+	closeAccounts bool
+	confirm       ConfirmFunc
+	journal       *journal.Journal
+	stateStore    ports.StateStore
+}
+
+// WithAccountClosure closes each account after moving it to the suspended
+// OU. Closed accounts can only be reopened through AWS Support, within
+// 90 days.
+func WithAccountClosure() DecommissionOption {
+	return func(o *decommissionOptions) {
+		o.closeAccounts = true
+	}
+}
+
+// WithConfirmation sets how production environments are confirmed.
+// Without it, decommissioning a production environment fails with
+// ErrNotConfirmed.
+func WithConfirmation(confirm ConfirmFunc) DecommissionOption {
+	return func(o *decommissionOptions) {
+		o.confirm = confirm
+	}
+}
+
+// WithDecommissionJournal forgets each decommissioned environment's steps
+// in j, so a later SetupProject with the same journal starts fresh.
+func WithDecommissionJournal(j *journal.Journal) DecommissionOption {
+	return func(o *decommissionOptions) {
+		o.journal = j
+	}
+}
+
+// WithDecommissionStateStore takes each environment's account from the
+// project state in store (see WithStateStore), so accounts adopted under
+// another name are found too, and removes each decommissioned environment
+// from it. Environments the state doesn't record are looked up by their
+// convention name.
+func WithDecommissionStateStore(store ports.StateStore) DecommissionOption {
+	return func(o *decommissionOptions) {
+		o.stateStore = store
+	}
+}
+
+// DecommissionResult describes what DecommissionProject did.
+type DecommissionResult struct {
+	Environments []DecommissionedEnvironment // Ordered by environment
+}
+
+// DecommissionedEnvironment describes the teardown of one environment.
+type DecommissionedEnvironment struct {
+	Environment   account.Environment
+	AccountName   string
+	AccountID     string   // Empty if the account doesn't exist
+	Removed       []string // Resources deleted, e.g. "budget TPA-dev-monthly-budget"
+	SuspendedOUID string   // OU the account now sits in
+	Status        string   // Account status afterwards (ports.AccountStatus*)
+	CloseDeferred string   // Why the account wasn't closed, if closure was requested
+}
+
+// DecommissionProject tears down every environment of a project.
+//
+// This is DOMAIN LOGIC - the reverse of SetupProject, per environment:
+//  1. Delete the budget, billing alarm and its SNS topic
+//  2. Delete the GitHub Actions deploy role and the GitHub OIDC provider
+//  3. Move the account into suspendedOUID
+//  4. Close the account (only with WithAccountClosure)
+//
+// CDK bootstrap stacks are left in place; closing the account removes them.
+// Accounts that are already closed are only moved.
+//
+// Production environments must be confirmed (see WithConfirmation) before
+// anything is touched. A mismatch aborts the whole run with ErrNotConfirmed.
+//
+// AWS limits account closures to 10% of member accounts per rolling 30
+// days. Once CloseAccount reports the quota as exhausted, the remaining
+// accounts are left suspended-but-open with CloseDeferred explaining why;
+// run again once the window allows.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - aws: AWS client implementation
+//   - config: Project configuration (defaults are applied)
+//   - suspendedOUID: OU that receives the decommissioned accounts
+//   - opts: Optional settings (closure, confirmation, journal, state store)
+//
+// Returns:
+//   - DecommissionResult for every environment handled so far
+//   - Error if a step fails (the result shows how far it got)
+func DecommissionProject(
+	ctx context.Context,
+	aws ports.AWSClient,
+	config Config,
+	suspendedOUID string,
+	opts ...DecommissionOption,
+) (result *DecommissionResult, err error) {
+	config = config.WithDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := account.ValidateOUID(suspendedOUID); err != nil {
+		return nil, fmt.Errorf("invalid suspended OU: %w", err)
+	}
+
+	var o decommissionOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	envs, err := config.Account.TargetEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	registry, err := config.Account.EnvironmentRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	d := &decommission{aws: aws, config: config, suspendedOUID: suspendedOUID, o: o}
+	if o.stateStore != nil {
+		projectCode := config.Account.ProjectCode
+		var unlock func(context.Context) error
+		unlock, err = lockState(ctx, o.stateStore, projectCode)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			// Unlock even if the run was cancelled
+			if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+				err = fmt.Errorf("failed to unlock state: %w", unlockErr)
+			}
+		}()
+
+		d.state, err = o.stateStore.LoadState(ctx, projectCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load state for project %s: %w", projectCode, err)
+		}
+	}
+	recorded := recordedAccounts(d.state)
+
+	// Find every account and collect confirmations before changing anything
+	result = &DecommissionResult{Environments: make([]DecommissionedEnvironment, len(envs))}
+	for i, env := range envs {
+		name := config.Account.ResourceNames(env).Account
+		accountID := recorded[env].AccountID
+		if accountID == "" {
+			accountID, err = aws.GetAccountByName(ctx, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up AWS account %s: %w", name, err)
+			}
+		} else if recorded[env].Name != "" {
+			name = recorded[env].Name
+		}
+		result.Environments[i] = DecommissionedEnvironment{Environment: env, AccountName: name, AccountID: accountID}
+
+		if spec, _ := registry.Lookup(env); spec.Production && accountID != "" {
+			if err := confirmDecommission(ctx, o, ConfirmationRequest{
+				Environment:  env,
+				AccountName:  name,
+				AccountID:    accountID,
+				CloseAccount: o.closeAccounts,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range result.Environments {
+		env := &result.Environments[i]
+		if env.AccountID == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := d.environment(ctx, env); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func confirmDecommission(ctx context.Context, o decommissionOptions, req ConfirmationRequest) error {
+	if o.confirm == nil {
+		return fmt.Errorf("%w: %s is a production environment (type %s to confirm)", ErrNotConfirmed, req.Environment, req.AccountName)
+	}
+	typed, err := o.confirm(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to confirm decommissioning %s: %w", req.Environment, err)
+	}
+	if typed != req.AccountName {
+		return fmt.Errorf("%w: typed %q, expected %q", ErrNotConfirmed, typed, req.AccountName)
+	}
+	return nil
+}
+
+// decommission holds the state shared by every environment's teardown.
+type decommission struct {
+	aws           ports.AWSClient
+	config        Config
+	suspendedOUID string
+	o             decommissionOptions
+
+	// state is the project state loaded from o.stateStore, if set.
+	state *ports.ProjectState
+
+	// closeDeferred is set once AWS reports the close quota as exhausted.
+	closeDeferred string
+}
+
+func (d *decommission) environment(ctx context.Context, env *DecommissionedEnvironment) error {
+	acct, err := d.aws.DescribeAccount(ctx, env.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to describe AWS account %s: %w", env.AccountName, err)
+	}
+	if acct == nil {
+		return fmt.Errorf("AWS account %s (%s) disappeared", env.AccountName, env.AccountID)
+	}
+	env.Status = acct.Status
+
+	// Resources in closed accounts are out of reach (and go away with them)
+	if acct.Status == ports.AccountStatusActive {
+		if err := d.removeResources(ctx, env); err != nil {
+			return err
+		}
+	}
+
+	if err := d.suspend(ctx, env); err != nil {
+		return err
+	}
+
+	if d.o.closeAccounts && env.Status == ports.AccountStatusActive {
+		if err := d.close(ctx, env); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range d.o.journal.Entries() {
+		if entry.Environment != string(env.Environment) {
+			continue
+		}
+		if err := d.o.journal.Forget(ctx, entry.Environment, journal.Step(entry.Step)); err != nil {
+			return err
+		}
+	}
+	return d.forgetState(ctx, env.Environment)
+}
+
+// forgetState removes a decommissioned environment from the project state.
+func (d *decommission) forgetState(ctx context.Context, env account.Environment) error {
+	if d.state == nil {
+		return nil
+	}
+	if _, ok := d.state.Environments[string(env)]; !ok {
+		return nil
+	}
+
+	delete(d.state.Environments, string(env))
+	d.state.UpdatedAt = time.Now().UTC()
+	if err := d.o.stateStore.SaveState(ctx, d.state); err != nil {
+		return fmt.Errorf("failed to save state for project %s: %w", d.state.ProjectCode, err)
+	}
+	return nil
+}
+
+// removeResources deletes what setup created, in reverse order.
+func (d *decommission) removeResources(ctx context.Context, env *DecommissionedEnvironment) error {
+	names := d.config.Account.ResourceNames(env.Environment)
+	accountID := env.AccountID
+
+	budget, err := d.aws.GetBudget(ctx, accountID, names.Budget)
+	if err != nil {
+		return fmt.Errorf("failed to check budget in %s: %w", env.AccountName, err)
+	}
+	if budget != nil {
+		if err := d.aws.DeleteBudget(ctx, accountID, names.Budget); err != nil {
+			return fmt.Errorf("failed to delete budget in %s: %w", env.AccountName, err)
+		}
+		env.Removed = append(env.Removed, "budget "+names.Budget)
+	}
+
+	alarm, err := d.aws.GetBillingAlarm(ctx, accountID, names.Alarm)
+	if err != nil {
+		return fmt.Errorf("failed to check billing alarm in %s: %w", env.AccountName, err)
+	}
+	if alarm != nil {
+		// The topic is only reachable through the alarm that notifies it,
+		// so it goes first: a rerun after an interruption still finds it
+		if alarm.TopicARN != "" {
+			if err := d.aws.DeleteSNSTopic(ctx, alarm.TopicARN); err != nil {
+				return fmt.Errorf("failed to delete SNS topic in %s: %w", env.AccountName, err)
+			}
+		}
+		if err := d.aws.DeleteBillingAlarm(ctx, accountID, names.Alarm); err != nil {
+			return fmt.Errorf("failed to delete billing alarm in %s: %w", env.AccountName, err)
+		}
+		if alarm.TopicARN != "" {
+			env.Removed = append(env.Removed, "SNS topic "+alarm.TopicARN)
+		}
+		env.Removed = append(env.Removed, "billing alarm "+names.Alarm)
+	}
+
+	roleName := d.config.roleName(env.Environment)
+	roleARN, err := d.aws.GetRole(ctx, accountID, roleName)
+	if err != nil {
+		return fmt.Errorf("failed to check deploy role in %s: %w", env.AccountName, err)
+	}
+	if roleARN != "" {
+		if err := d.aws.DeleteRole(ctx, accountID, roleName); err != nil {
+			return fmt.Errorf("failed to delete deploy role in %s: %w", env.AccountName, err)
+		}
+		env.Removed = append(env.Removed, "role "+roleARN)
+	}
+
+	providerARN, err := d.aws.GetOIDCProviderForGitHub(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to check GitHub OIDC provider in %s: %w", env.AccountName, err)
+	}
+	if providerARN != "" {
+		if err := d.aws.DeleteOIDCProviderForGitHub(ctx, accountID); err != nil {
+			return fmt.Errorf("failed to delete GitHub OIDC provider in %s: %w", env.AccountName, err)
+		}
+		env.Removed = append(env.Removed, "OIDC provider "+providerARN)
+	}
+
+	return nil
+}
+
+// suspend moves the account into the suspended OU.
+func (d *decommission) suspend(ctx context.Context, env *DecommissionedEnvironment) error {
+	parentID, err := d.aws.GetAccountParent(ctx, env.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to find OU of %s: %w", env.AccountName, err)
+	}
+	if parentID != d.suspendedOUID {
+		if err := d.aws.MoveAccount(ctx, env.AccountID, parentID, d.suspendedOUID); err != nil {
+			return fmt.Errorf("failed to move %s to suspended OU %s: %w", env.AccountName, d.suspendedOUID, err)
+		}
+	}
+	env.SuspendedOUID = d.suspendedOUID
+	return nil
+}
+
+// close closes the account unless the close quota is already exhausted.
+func (d *decommission) close(ctx context.Context, env *DecommissionedEnvironment) error {
+	if d.closeDeferred != "" {
+		env.CloseDeferred = d.closeDeferred
+		return nil
+	}
+
+	err := d.aws.CloseAccount(ctx, env.AccountID)
+	switch {
+	case errors.Is(err, ports.ErrCloseAccountQuotaExceeded):
+		d.closeDeferred = "AWS close-account quota exhausted for the current 30-day window"
+		env.CloseDeferred = d.closeDeferred
+		return nil
+	case err != nil && !errors.Is(err, ports.ErrAccountAlreadyClosed):
+		return fmt.Errorf("failed to close AWS account %s: %w", env.AccountName, err)
+	}
+
+	acct, err := d.aws.DescribeAccount(ctx, env.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to describe AWS account %s: %w", env.AccountName, err)
+	}
+	if acct != nil {
+		env.Status = acct.Status
+	}
+	return nil
+}
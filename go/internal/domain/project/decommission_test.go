@@ -0,0 +1,249 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+const suspendedOU = "ou-813y-suspended"
+
+// typeAccountName confirms by typing exactly what was asked for.
+func typeAccountName(ctx context.Context, req ConfirmationRequest) (string, error) {
+	return req.AccountName, nil
+}
+
+func TestDecommissionProject(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	store := mock.NewJournalStore()
+	j, _ := journal.Open(ctx, store, "TPA")
+
+	setupResult, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(j))
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	var confirmed []string
+	confirm := func(ctx context.Context, req ConfirmationRequest) (string, error) {
+		confirmed = append(confirmed, req.AccountName)
+		return req.AccountName, nil
+	}
+
+	result, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU,
+		WithAccountClosure(), WithConfirmation(confirm), WithDecommissionJournal(j))
+	if err != nil {
+		t.Fatalf("DecommissionProject() failed: %v", err)
+	}
+
+	if strings.Join(confirmed, ",") != "TPA_PROD" {
+		t.Errorf("Confirmed %v, want only TPA_PROD", confirmed)
+	}
+
+	for i, env := range result.Environments {
+		setupEnv := setupResult.Environments[i]
+		if env.AccountID != setupEnv.Account.AccountID {
+			t.Errorf("%s: AccountID = %s, want %s", env.Environment, env.AccountID, setupEnv.Account.AccountID)
+		}
+		if len(env.Removed) != 5 {
+			t.Errorf("%s: removed %v, want budget, alarm, topic, role and OIDC provider", env.Environment, env.Removed)
+		}
+		if env.Status != ports.AccountStatusSuspended || env.SuspendedOUID != suspendedOU {
+			t.Errorf("%s: status %s in %s, want SUSPENDED in %s", env.Environment, env.Status, env.SuspendedOUID, suspendedOU)
+		}
+		if parent, _ := mockAWS.GetAccountParent(ctx, env.AccountID); parent != suspendedOU {
+			t.Errorf("%s: parent = %s, want %s", env.Environment, parent, suspendedOU)
+		}
+		if budget, _ := mockAWS.GetBudget(ctx, env.AccountID, setupEnv.BudgetName); budget != nil {
+			t.Errorf("%s: budget still exists", env.Environment)
+		}
+		if arn, _ := mockAWS.GetRole(ctx, env.AccountID, "GitHubActionsDeployRole"); arn != "" {
+			t.Errorf("%s: deploy role still exists", env.Environment)
+		}
+	}
+
+	if entries := j.Entries(); len(entries) != 0 {
+		t.Errorf("Journal should be empty after decommissioning, got %v", entries)
+	}
+
+	// Running again finds nothing left to remove or close
+	again, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU, WithAccountClosure(), WithConfirmation(typeAccountName))
+	if err != nil {
+		t.Fatalf("second DecommissionProject() failed: %v", err)
+	}
+	for _, env := range again.Environments {
+		if len(env.Removed) != 0 || env.CloseDeferred != "" {
+			t.Errorf("%s: second run removed %v (deferred %q), want nothing", env.Environment, env.Removed, env.CloseDeferred)
+		}
+	}
+}
+
+func TestSetupAfterDecommissionRefusesClosedAccounts(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	j, _ := journal.Open(ctx, mock.NewJournalStore(), "TPA")
+
+	if _, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(j)); err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	if _, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU,
+		WithAccountClosure(), WithConfirmation(typeAccountName), WithDecommissionJournal(j)); err != nil {
+		t.Fatalf("DecommissionProject() failed: %v", err)
+	}
+
+	// The journal is forgotten, but the closed accounts keep their names
+	mark := mockAWS.Mark()
+	_, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(j))
+	if !errors.Is(err, account.ErrAccountNotActive) {
+		t.Fatalf("SetupProject() error = %v, want ErrAccountNotActive", err)
+	}
+	var opErr *account.OperationError
+	if !errors.As(err, &opErr) || opErr.Phase != account.PhaseLookup || opErr.AccountName != "TPA_DEV" {
+		t.Errorf("error = %#v, want a lookup failure for TPA_DEV", opErr)
+	}
+	if writes := mockAWS.Operations().Since(mark).Method("MoveAccount", "TagAccount", "CreateOIDCProviderForGitHub"); writes.Count() != 0 {
+		t.Errorf("Setup touched the closed accounts: %v", writes)
+	}
+}
+
+func TestDecommissionProjectRequiresProdConfirmation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		opts []DecommissionOption
+	}{
+		{"no confirmation", nil},
+		{"wrong name typed", []DecommissionOption{WithConfirmation(func(ctx context.Context, req ConfirmationRequest) (string, error) {
+			return "tpa_prod", nil
+		})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAWS := mock.NewAWSClient()
+			if _, err := SetupProject(ctx, mockAWS, testConfig()); err != nil {
+				t.Fatalf("SetupProject() failed: %v", err)
+			}
+			before := len(mockAWS.GetOperations())
+
+			_, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU, tt.opts...)
+			if !errors.Is(err, ErrNotConfirmed) {
+				t.Fatalf("DecommissionProject() = %v, want ErrNotConfirmed", err)
+			}
+
+			// Nothing was touched, not even dev
+			for _, op := range mockAWS.GetOperations()[before:] {
+				if !strings.HasPrefix(op, "GetAccountByName") {
+					t.Errorf("Unconfirmed decommission performed %s", op)
+				}
+			}
+		})
+	}
+}
+
+func TestDecommissionProjectResumesAfterInterruption(t *testing.T) {
+	errInterrupted := errors.New("interrupted")
+
+	// Failing either delete leaves the alarm, and with it the topic, findable
+	for _, method := range []string{"DeleteSNSTopic", "DeleteBillingAlarm"} {
+		t.Run(method, func(t *testing.T) {
+			ctx := context.Background()
+			mockAWS := mock.NewAWSClient()
+			if _, err := SetupProject(ctx, mockAWS, testConfig()); err != nil {
+				t.Fatalf("SetupProject() failed: %v", err)
+			}
+
+			mockAWS.AddFault(method, mock.FailFirst(1, errInterrupted))
+			if _, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU, WithConfirmation(typeAccountName)); !errors.Is(err, errInterrupted) {
+				t.Fatalf("DecommissionProject() = %v, want the injected failure", err)
+			}
+
+			if _, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU, WithConfirmation(typeAccountName)); err != nil {
+				t.Fatalf("second DecommissionProject() failed: %v", err)
+			}
+			if topics := mockAWS.Snapshot().Topics; len(topics) != 0 {
+				t.Errorf("Topics left behind: %+v", topics)
+			}
+		})
+	}
+}
+
+func TestDecommissionProjectCloseQuota(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	if _, err := SetupProject(ctx, mockAWS, testConfig()); err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	mockAWS.SetCloseAccountQuota(1)
+
+	result, err := DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU, WithAccountClosure(), WithConfirmation(typeAccountName))
+	if err != nil {
+		t.Fatalf("DecommissionProject() failed: %v", err)
+	}
+
+	dev, staging, prod := result.Environments[0], result.Environments[1], result.Environments[2]
+	if dev.Status != ports.AccountStatusSuspended || dev.CloseDeferred != "" {
+		t.Errorf("dev = %s (deferred %q), want closed", dev.Status, dev.CloseDeferred)
+	}
+	for _, env := range []DecommissionedEnvironment{staging, prod} {
+		if env.Status != ports.AccountStatusActive || !strings.Contains(env.CloseDeferred, "quota") {
+			t.Errorf("%s = %s (deferred %q), want open with a quota note", env.Environment, env.Status, env.CloseDeferred)
+		}
+		if env.SuspendedOUID != suspendedOU {
+			t.Errorf("%s should still be moved to the suspended OU", env.Environment)
+		}
+	}
+
+	// Only one CloseAccount hit the quota; the rest weren't attempted
+	attempts := 0
+	for _, op := range mockAWS.GetOperations() {
+		if strings.HasPrefix(op, "CloseAccount") {
+			attempts++
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("CloseAccount attempts = %d, want 2 (one success, one quota error)", attempts)
+	}
+
+	// Once the window allows, a re-run closes the rest
+	mockAWS.SetCloseAccountQuota(10)
+	result, err = DecommissionProject(ctx, mockAWS, testConfig(), suspendedOU, WithAccountClosure(), WithConfirmation(typeAccountName))
+	if err != nil {
+		t.Fatalf("second DecommissionProject() failed: %v", err)
+	}
+	for _, env := range result.Environments {
+		if env.Status != ports.AccountStatusSuspended {
+			t.Errorf("%s = %s, want SUSPENDED", env.Environment, env.Status)
+		}
+	}
+}
+
+func TestDecommissionProjectWithoutClosure(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := testConfig()
+	if _, err := SetupProject(ctx, mockAWS, config); err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	result, err := DecommissionProject(ctx, mockAWS, config, suspendedOU, WithConfirmation(typeAccountName))
+	if err != nil {
+		t.Fatalf("DecommissionProject() failed: %v", err)
+	}
+	for _, env := range result.Environments {
+		if env.Status != ports.AccountStatusActive || env.SuspendedOUID != suspendedOU {
+			t.Errorf("%s = %s in %s, want ACTIVE in the suspended OU", env.Environment, env.Status, env.SuspendedOUID)
+		}
+	}
+
+	if _, err := DecommissionProject(ctx, mockAWS, config, "not-an-ou"); err == nil {
+		t.Error("DecommissionProject() should reject an invalid suspended OU")
+	}
+}
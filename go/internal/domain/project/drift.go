@@ -0,0 +1,298 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Drift detection.
+//
+// The v1 scripts record account IDs in .aws-bootstrap/account-ids.json and
+// every later script trusts them. DetectDrift checks those recorded IDs
+// against the live organization: that each account still exists under the
+// expected name and OU, and still has what SetupProject put in it. Like
+// BuildPlan it only calls read-only port methods.
+
+// DriftKind classifies a difference between recorded and live state.
+type DriftKind string
+
+const (
+	DriftMissing    DriftKind = "missing"    // Expected but not found
+	DriftChanged    DriftKind = "changed"    // Found, but differs from what was expected
+	DriftUnexpected DriftKind = "unexpected" // Found, but not recorded (or not configured)
+)
+
+// RecordedState is the account state a previous run wrote down.
+type RecordedState struct {
+	ProjectCode string
+	OUID        string // Project OU the accounts were placed in
+	AccountIDs  map[account.Environment]string
+
+	// AccountNames holds the actual name of accounts recorded under a name
+	// other than the naming convention's (adopted accounts). Optional.
+	AccountNames map[account.Environment]string
+}
+
+// ParseAccountIDs reads .aws-bootstrap/account-ids.json in either schema
+// (see ports.StateVersion):
+//
+//	{"projectCode": "TPA", "devAccountId": "...", "ouId": "ou-..."}
+//
+// Any "<env>AccountId" key is accepted, so custom environments work too.
+// Empty IDs (a failed v1 run) are treated as not recorded.
+func ParseAccountIDs(data []byte) (*RecordedState, error) {
+	var state ports.ProjectState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse account IDs: %w", err)
+	}
+	return RecordedStateFrom(&state), nil
+}
+
+// Drift is one difference between recorded and live state.
+type Drift struct {
+	Environment account.Environment `json:"environment"`
+	Resource    ResourceType        `json:"resource"`
+	Name        string              `json:"name"` // Resource name (account name, role name, region, ...)
+	Kind        DriftKind           `json:"drift"`
+	Changes     []Change            `json:"changes,omitempty"` // Expected (From) vs live (To), for changed resources
+	Detail      string              `json:"detail,omitempty"`
+}
+
+// DriftReport lists every difference found by DetectDrift.
+type DriftReport struct {
+	ProjectCode string    `json:"projectCode"`
+	CheckedAt   time.Time `json:"checkedAt"`
+	Drifts      []Drift   `json:"drifts"`
+}
+
+// HasDrift reports whether anything differs.
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Drifts) > 0
+}
+
+// DetectDrift compares recorded state with what AWS reports.
+//
+// This is DOMAIN LOGIC - expected names, OUs and billing settings come from
+// config, account IDs from recorded:
+//   - Recorded accounts must exist, be ACTIVE, carry the expected name (the
+//     recorded one, else the convention's) and sit in the expected OU; accounts in the project OU must also sit in
+//     the recorded OU
+//   - Inside each live account, the OIDC provider, deploy role, CDK
+//     bootstrap (per region) and budget must exist; budget amounts must
+//     match the config
+//   - Configured environments without a recorded ID are missing, unless an
+//     account with the expected name exists (unexpected: it isn't recorded)
+//   - Recorded environments that aren't configured are unexpected
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - aws: AWS client (only read-only methods are called)
+//   - config: Project configuration (defaults are applied)
+//   - recorded: State written by a previous run (see ParseAccountIDs), or
+//     nil if nothing was recorded
+//
+// Returns:
+//   - DriftReport (empty Drifts if everything matches)
+//   - Error if configuration is invalid or a lookup fails
+func DetectDrift(ctx context.Context, aws ports.AWSClient, config Config, recorded *RecordedState) (*DriftReport, error) {
+	config = config.WithDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if recorded == nil {
+		recorded = &RecordedState{}
+	}
+	if recorded.ProjectCode != "" && recorded.ProjectCode != config.Account.ProjectCode {
+		return nil, fmt.Errorf("recorded state is for project %s, not %s", recorded.ProjectCode, config.Account.ProjectCode)
+	}
+
+	envs, err := config.Account.TargetEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	report := &DriftReport{
+		ProjectCode: config.Account.ProjectCode,
+		CheckedAt:   time.Now().UTC(),
+	}
+
+	for _, env := range envs {
+		drifts, err := environmentDrift(ctx, aws, config, env, recorded.AccountIDs[env], recorded.AccountNames[env], recorded.OUID)
+		if err != nil {
+			return nil, err
+		}
+		report.Drifts = append(report.Drifts, drifts...)
+	}
+
+	for _, env := range slices.Sorted(maps.Keys(recorded.AccountIDs)) {
+		if !slices.Contains(envs, env) {
+			report.Drifts = append(report.Drifts, Drift{
+				Environment: env,
+				Resource:    ResourceAccount,
+				Name:        recorded.AccountIDs[env],
+				Kind:        DriftUnexpected,
+				Detail:      "recorded environment is not configured",
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// environmentDrift checks one configured environment. recordedName is the
+// account's recorded name and recordedOUID the project OU a previous run
+// recorded, if any.
+func environmentDrift(ctx context.Context, aws ports.AWSClient, config Config, env account.Environment, accountID, recordedName, recordedOUID string) ([]Drift, error) {
+	names := config.Account.ResourceNames(env)
+	if accountID != "" && recordedName != "" {
+		names.Account = recordedName
+	}
+	settings := config.settingsFor(env)
+	drift := func(resource ResourceType, name string, kind DriftKind, detail string, changes ...Change) Drift {
+		return Drift{Environment: env, Resource: resource, Name: name, Kind: kind, Detail: detail, Changes: changes}
+	}
+
+	if accountID == "" {
+		existingID, err := aws.GetAccountByName(ctx, names.Account)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing AWS account %s: %w", names.Account, err)
+		}
+		if existingID != "" {
+			return []Drift{drift(ResourceAccount, names.Account, DriftUnexpected, "account "+existingID+" exists but is not recorded")}, nil
+		}
+		return []Drift{drift(ResourceAccount, names.Account, DriftMissing, "no account recorded or found")}, nil
+	}
+
+	acct, err := aws.DescribeAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe AWS account %s: %w", accountID, err)
+	}
+	if acct == nil {
+		return []Drift{drift(ResourceAccount, names.Account, DriftMissing, "recorded account "+accountID+" not found")}, nil
+	}
+
+	var drifts []Drift
+	var changes []Change
+	if acct.Name != names.Account {
+		changes = append(changes, Change{Field: "name", From: names.Account, To: acct.Name})
+	}
+	if acct.Status != ports.AccountStatusActive {
+		changes = append(changes, Change{Field: "status", From: ports.AccountStatusActive, To: acct.Status})
+	}
+	parentID, err := aws.GetAccountParent(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check OU of AWS account %s: %w", accountID, err)
+	}
+	if parentID != settings.OUID {
+		changes = append(changes, Change{Field: "parent", From: settings.OUID, To: parentID})
+	}
+	// The recorded OU is the project's: overridden environments live elsewhere
+	if recordedOUID != "" && settings.OUID == config.Account.OUID && parentID != recordedOUID {
+		changes = append(changes, Change{Field: "recordedParent", From: recordedOUID, To: parentID})
+	}
+	if len(changes) > 0 {
+		drifts = append(drifts, drift(ResourceAccount, names.Account, DriftChanged, "account "+accountID, changes...))
+	}
+
+	// A closed account's resources are out of reach
+	if acct.Status != ports.AccountStatusActive {
+		return drifts, nil
+	}
+
+	oidcARN, err := aws.GetOIDCProviderForGitHub(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check GitHub OIDC provider in %s: %w", names.Account, err)
+	}
+	if oidcARN == "" {
+		drifts = append(drifts, drift(ResourceOIDCProvider, githubOIDCProviderName, DriftMissing, ""))
+	}
+
+	roleName := config.roleName(env)
+	roleARN, err := aws.GetRole(ctx, accountID, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check role %s in %s: %w", roleName, names.Account, err)
+	}
+	if roleARN == "" {
+		drifts = append(drifts, drift(ResourceDeployRole, roleName, DriftMissing, ""))
+	}
+
+	for _, region := range settings.Regions {
+		bootstrapped, err := aws.IsCDKBootstrapped(ctx, accountID, region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check CDK bootstrap in %s (%s): %w", names.Account, region, err)
+		}
+		if !bootstrapped {
+			drifts = append(drifts, drift(ResourceCDKBootstrap, region, DriftMissing, ""))
+		}
+	}
+
+	budget, err := aws.GetBudget(ctx, accountID, names.Budget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check budget in %s: %w", names.Account, err)
+	}
+	if budget == nil {
+		drifts = append(drifts, drift(ResourceBudget, names.Budget, DriftMissing, ""))
+	} else {
+		changes := amountChanges(nil, "limitAmount", settings.Billing.MonthlyLimit, budget.LimitAmount)
+		changes = amountChanges(changes, "alertAmount", settings.Billing.AlertThreshold, budget.AlertAmount)
+		if len(changes) > 0 {
+			drifts = append(drifts, drift(ResourceBudget, names.Budget, DriftChanged, "", changes...))
+		}
+	}
+
+	return drifts, nil
+}
+
+// Summary renders the report as human-readable text.
+//
+// This is pure presentation logic - no side effects.
+func (r *DriftReport) Summary() string {
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("Drift report for project %s (%s)\n", r.ProjectCode, r.CheckedAt.Format(time.RFC3339)))
+	summary.WriteString("==========================================================\n")
+
+	if !r.HasDrift() {
+		summary.WriteString("\nNo drift: recorded state matches AWS\n")
+		return summary.String()
+	}
+
+	counts := make(map[DriftKind]int)
+	var current account.Environment
+	for _, d := range r.Drifts {
+		if d.Environment != current {
+			current = d.Environment
+			summary.WriteString(fmt.Sprintf("\n%s:\n", current))
+		}
+		counts[d.Kind]++
+		line := fmt.Sprintf("  %-10s %-14s %s", d.Kind, d.Resource, d.Name)
+		if d.Detail != "" {
+			line += " (" + d.Detail + ")"
+		}
+		summary.WriteString(line + "\n")
+		for _, c := range d.Changes {
+			summary.WriteString(fmt.Sprintf("             %s: expected %s, found %s\n", c.Field, c.From, c.To))
+		}
+	}
+
+	summary.WriteString(fmt.Sprintf("\n%d missing, %d changed, %d unexpected\n",
+		counts[DriftMissing], counts[DriftChanged], counts[DriftUnexpected]))
+	return summary.String()
+}
+
+// MarshalDriftReport serializes a drift report to indented JSON.
+func MarshalDriftReport(r *DriftReport) ([]byte, error) {
+	if r.Drifts == nil {
+		report := *r
+		report.Drifts = []Drift{}
+		r = &report
+	}
+	return json.MarshalIndent(r, "", "  ")
+}
@@ -0,0 +1,215 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// v1AccountIDs renders account-ids.json the way create-project-accounts.sh does.
+func v1AccountIDs(result *SetupResult) []byte {
+	return fmt.Appendf(nil, `{
+  "projectCode": "TPA",
+  "devAccountId": "%s",
+  "stagingAccountId": "%s",
+  "prodAccountId": "%s",
+  "ouId": "ou-813y-8teevv2l"
+}`, result.Environments[0].Account.AccountID, result.Environments[1].Account.AccountID, result.Environments[2].Account.AccountID)
+}
+
+func TestParseAccountIDs(t *testing.T) {
+	state, err := ParseAccountIDs([]byte(`{"projectCode": "TPA", "devAccountId": "100000000001", "stagingAccountId": "", "ouId": "ou-813y-8teevv2l", "note": 1}`))
+	if err != nil {
+		t.Fatalf("ParseAccountIDs() failed: %v", err)
+	}
+	if state.ProjectCode != "TPA" || state.OUID != "ou-813y-8teevv2l" {
+		t.Errorf("ParseAccountIDs() = %+v", state)
+	}
+	if len(state.AccountIDs) != 1 || state.AccountIDs[account.EnvironmentDev] != "100000000001" {
+		t.Errorf("AccountIDs = %v, want only dev (empty IDs aren't recorded)", state.AccountIDs)
+	}
+
+	if _, err := ParseAccountIDs([]byte("not json")); err == nil {
+		t.Error("ParseAccountIDs() should reject invalid JSON")
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	result, err := SetupProject(ctx, mockAWS, testConfig())
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	recorded, _ := ParseAccountIDs(v1AccountIDs(result))
+
+	report, err := DetectDrift(ctx, mockAWS, testConfig(), recorded)
+	if err != nil {
+		t.Fatalf("DetectDrift() failed: %v", err)
+	}
+	if report.HasDrift() {
+		t.Fatalf("Fresh setup should have no drift:\n%s", report.Summary())
+	}
+
+	// Break things by hand
+	dev, staging, prod := result.Environments[0].Account, result.Environments[1].Account, result.Environments[2].Account
+	mockAWS.DeleteRole(ctx, dev.AccountID, "GitHubActionsDeployRole")
+	mockAWS.MoveAccount(ctx, staging.AccountID, "ou-813y-8teevv2l", "ou-813y-elsewhere")
+	mockAWS.UpdateBudget(ctx, ports.AWSCreateBudgetRequest{AccountID: prod.AccountID, BudgetName: "TPA-prod-monthly-budget", LimitAmount: 1000, AlertAmount: 15})
+	recorded.AccountIDs["qa"] = "123456789012"
+
+	report, err = DetectDrift(ctx, mockAWS, testConfig(), recorded)
+	if err != nil {
+		t.Fatalf("DetectDrift() failed: %v", err)
+	}
+
+	want := []struct {
+		env      account.Environment
+		resource ResourceType
+		kind     DriftKind
+	}{
+		{account.EnvironmentDev, ResourceDeployRole, DriftMissing},
+		{account.EnvironmentStaging, ResourceAccount, DriftChanged},
+		{account.EnvironmentProd, ResourceBudget, DriftChanged},
+		{"qa", ResourceAccount, DriftUnexpected},
+	}
+	if len(report.Drifts) != len(want) {
+		t.Fatalf("DetectDrift() found %d drifts, want %d:\n%s", len(report.Drifts), len(want), report.Summary())
+	}
+	for i, w := range want {
+		got := report.Drifts[i]
+		if got.Environment != w.env || got.Resource != w.resource || got.Kind != w.kind {
+			t.Errorf("Drifts[%d] = %s/%s/%s, want %s/%s/%s", i, got.Environment, got.Resource, got.Kind, w.env, w.resource, w.kind)
+		}
+	}
+
+	if c := report.Drifts[1].Changes[0]; c.Field != "parent" || c.To != "ou-813y-elsewhere" {
+		t.Errorf("staging change = %+v, want parent moved to ou-813y-elsewhere", c)
+	}
+
+	summary := report.Summary()
+	for _, s := range []string{"missing    deploy-role    GitHubActionsDeployRole", "limitAmount: expected 25.00, found 1000.00", "1 missing, 2 changed, 1 unexpected"} {
+		if !strings.Contains(summary, s) {
+			t.Errorf("Summary missing %q:\n%s", s, summary)
+		}
+	}
+
+	data, err := MarshalDriftReport(report)
+	if err != nil {
+		t.Fatalf("MarshalDriftReport() failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"drift": "unexpected"`) {
+		t.Errorf("JSON missing drift kind:\n%s", data)
+	}
+}
+
+func TestDetectDriftAccounts(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		modify func(aws *mock.AWSClient, recorded *RecordedState)
+		kind   DriftKind
+		detail string
+	}{
+		{
+			name: "recorded account doesn't exist",
+			modify: func(aws *mock.AWSClient, recorded *RecordedState) {
+				recorded.AccountIDs[account.EnvironmentDev] = "555555555555"
+			},
+			kind:   DriftMissing,
+			detail: "555555555555 not found",
+		},
+		{
+			name: "account exists but isn't recorded",
+			modify: func(aws *mock.AWSClient, recorded *RecordedState) {
+				delete(recorded.AccountIDs, account.EnvironmentDev)
+			},
+			kind:   DriftUnexpected,
+			detail: "not recorded",
+		},
+		{
+			name: "account closed",
+			modify: func(aws *mock.AWSClient, recorded *RecordedState) {
+				aws.CloseAccount(ctx, recorded.AccountIDs[account.EnvironmentDev])
+			},
+			kind: DriftChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAWS := mock.NewAWSClient()
+			result, err := SetupProject(ctx, mockAWS, testConfig())
+			if err != nil {
+				t.Fatalf("SetupProject() failed: %v", err)
+			}
+			recorded, _ := ParseAccountIDs(v1AccountIDs(result))
+			tt.modify(mockAWS, recorded)
+
+			report, err := DetectDrift(ctx, mockAWS, testConfig(), recorded)
+			if err != nil {
+				t.Fatalf("DetectDrift() failed: %v", err)
+			}
+			if len(report.Drifts) != 1 {
+				t.Fatalf("DetectDrift() = %d drifts, want 1 (a broken account hides its resources):\n%s", len(report.Drifts), report.Summary())
+			}
+			got := report.Drifts[0]
+			if got.Resource != ResourceAccount || got.Kind != tt.kind || !strings.Contains(got.Detail, tt.detail) {
+				t.Errorf("Drift = %+v, want account %s (%s)", got, tt.kind, tt.detail)
+			}
+		})
+	}
+}
+
+func TestDetectDriftRejectsOtherProject(t *testing.T) {
+	recorded := &RecordedState{ProjectCode: "XYZ"}
+	if _, err := DetectDrift(context.Background(), mock.NewAWSClient(), testConfig(), recorded); err == nil {
+		t.Error("DetectDrift() should reject state recorded for another project")
+	}
+}
+
+func TestDetectDriftRecordedOU(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	result, err := SetupProject(ctx, mockAWS, testConfig())
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	recorded, _ := ParseAccountIDs(v1AccountIDs(result))
+	recorded.OUID = "ou-813y-previous"
+
+	report, err := DetectDrift(ctx, mockAWS, testConfig(), recorded)
+	if err != nil {
+		t.Fatalf("DetectDrift() failed: %v", err)
+	}
+	if len(report.Drifts) != 3 {
+		t.Fatalf("DetectDrift() = %d drifts, want one per account:\n%s", len(report.Drifts), report.Summary())
+	}
+	for _, d := range report.Drifts {
+		want := Change{Field: "recordedParent", From: "ou-813y-previous", To: "ou-813y-8teevv2l"}
+		if d.Resource != ResourceAccount || d.Kind != DriftChanged || len(d.Changes) != 1 || d.Changes[0] != want {
+			t.Errorf("Drift = %+v, want the recorded OU reported as changed", d)
+		}
+	}
+}
+
+func TestDetectDriftWithoutRecordedState(t *testing.T) {
+	report, err := DetectDrift(context.Background(), mock.NewAWSClient(), testConfig(), nil)
+	if err != nil {
+		t.Fatalf("DetectDrift() failed: %v", err)
+	}
+	if len(report.Drifts) != 3 {
+		t.Fatalf("DetectDrift() = %d drifts, want every account missing:\n%s", len(report.Drifts), report.Summary())
+	}
+	for _, d := range report.Drifts {
+		if d.Kind != DriftMissing {
+			t.Errorf("Drift = %+v, want missing", d)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Plan/apply for project setup.
+//
+// BuildPlan only calls read-only port methods and describes what
+// SetupProject WOULD do. The plan serializes to JSON so it can be reviewed
+// (e.g., in a pull request) before ApplyPlan executes exactly those actions.
+//
+// ApplyPlan re-plans first and refuses to run if reality changed since the
+// plan was made - a reviewed plan must never turn into different mutations.
+
+// PlanFormatVersion is the version of the serialized plan format.
+const PlanFormatVersion = 1
+
+// ErrPlanDrift is returned (wrapped in *PlanDriftError) when AWS no longer
+// matches the state a plan was computed against.
+var ErrPlanDrift = errors.New("plan is stale")
+
+// ActionType describes what applying a plan does to a resource.
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create" // Resource doesn't exist and will be created
+	ActionReuse  ActionType = "reuse"  // Resource exists and will be used as-is
+	ActionUpdate ActionType = "update" // Resource exists but its settings will change
+	ActionNoOp   ActionType = "no-op"  // Resource already matches the configuration
+)
+
+// Mutates reports whether the action changes anything in AWS.
+func (a ActionType) Mutates() bool {
+	return a == ActionCreate || a == ActionUpdate
+}
+
+// ResourceType identifies the kind of resource an action applies to.
+type ResourceType string
+
+const (
+	ResourceAccount      ResourceType = "account"
+	ResourceOIDCProvider ResourceType = "oidc-provider"
+	ResourceDeployRole   ResourceType = "deploy-role"
+	ResourceCDKBootstrap ResourceType = "cdk-bootstrap"
+	ResourceBillingAlarm ResourceType = "billing-alarm" // SNS topic + subscription + alarm
+	ResourceBudget       ResourceType = "budget"
+)
+
+// Plan is the computed change set for a project.
+type Plan struct {
+	Version             int               `json:"version"`
+	ProjectCode         string            `json:"projectCode"`
+	ManagementAccountID string            `json:"managementAccountId"`
+	CreatedAt           time.Time         `json:"createdAt"`
+	Environments        []EnvironmentPlan `json:"environments"`
+}
+
+// EnvironmentPlan lists the actions for one environment, in execution order.
+type EnvironmentPlan struct {
+	Environment account.Environment `json:"environment"`
+	AccountName string              `json:"accountName"`
+	AccountID   string              `json:"accountId,omitempty"` // Empty if the account will be created
+	Actions     []Action            `json:"actions"`
+}
+
+// Action is a single planned change.
+type Action struct {
+	Resource ResourceType `json:"resource"`
+	Name     string       `json:"name"`
+	Type     ActionType   `json:"action"`
+	ARN      string       `json:"arn,omitempty"`     // Existing resource ARN, when known
+	Changes  []Change     `json:"changes,omitempty"` // Field changes for updates
+}
+
+// Change describes one field an update will modify.
+type Change struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// actionKey indexes plan actions while applying.
+type actionKey struct {
+	env      account.Environment
+	resource ResourceType
+	region   string // Only set for per-region resources (CDK bootstrap)
+}
+
+// keyFor returns the index key for an action in env.
+func keyFor(env account.Environment, action Action) actionKey {
+	key := actionKey{env: env, resource: action.Resource}
+	if action.Resource == ResourceCDKBootstrap {
+		key.region = action.Name
+	}
+	return key
+}
+
+// PlanDriftError lists how AWS differs from the state a plan was built on.
+type PlanDriftError struct {
+	Differences []string
+}
+
+func (e *PlanDriftError) Error() string {
+	return fmt.Sprintf("%s: AWS changed since planning: %s", ErrPlanDrift, strings.Join(e.Differences, "; "))
+}
+
+// Is makes errors.Is(err, ErrPlanDrift) work.
+func (e *PlanDriftError) Is(target error) bool {
+	return target == ErrPlanDrift
+}
+
+// BuildPlan computes what SetupProject would do, without mutating AWS.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - aws: AWS client (only read-only methods are called)
+//   - config: Project configuration (defaults are applied)
+//
+// Returns:
+//   - Plan with one EnvironmentPlan per environment
+//   - Error if configuration is invalid or a lookup fails
+func BuildPlan(ctx context.Context, aws ports.AWSClient, config Config) (*Plan, error) {
+	config = config.WithDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	identity, err := aws.GetCallerIdentity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	plan := &Plan{
+		Version:             PlanFormatVersion,
+		ProjectCode:         config.Account.ProjectCode,
+		ManagementAccountID: identity.AccountID,
+		CreatedAt:           time.Now().UTC(),
+	}
+
+	envs, err := config.Account.TargetEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	for _, env := range envs {
+		envPlan, err := planEnvironment(ctx, aws, config, env)
+		if err != nil {
+			return nil, err
+		}
+		plan.Environments = append(plan.Environments, envPlan)
+	}
+
+	return plan, nil
+}
+
+// planEnvironment inspects one environment's resources.
+func planEnvironment(ctx context.Context, aws ports.AWSClient, config Config, env account.Environment) (EnvironmentPlan, error) {
+	names := config.Account.ResourceNames(env)
+	accountName, alarmName, budgetName := names.Account, names.Alarm, names.Budget
+	roleName := config.roleName(env)
+	settings := config.settingsFor(env)
+
+	envPlan := EnvironmentPlan{
+		Environment: env,
+		AccountName: accountName,
+	}
+
+	accountID, err := aws.GetAccountByName(ctx, accountName)
+	if err != nil {
+		return envPlan, fmt.Errorf("failed to check existing AWS account %s: %w", accountName, err)
+	}
+	envPlan.AccountID = accountID
+
+	// New account: everything inside it is new too
+	if accountID == "" {
+		envPlan.Actions = []Action{
+			{Resource: ResourceAccount, Name: accountName, Type: ActionCreate},
+			{Resource: ResourceOIDCProvider, Name: githubOIDCProviderName, Type: ActionCreate},
+			{Resource: ResourceDeployRole, Name: roleName, Type: ActionCreate},
+		}
+		for _, region := range settings.Regions {
+			envPlan.Actions = append(envPlan.Actions, Action{Resource: ResourceCDKBootstrap, Name: region, Type: ActionCreate})
+		}
+		envPlan.Actions = append(envPlan.Actions,
+			Action{Resource: ResourceBillingAlarm, Name: alarmName, Type: ActionCreate},
+			Action{Resource: ResourceBudget, Name: budgetName, Type: ActionCreate},
+		)
+		return envPlan, nil
+	}
+
+	// Account placement (reused accounts are moved into the OU if needed)
+	parentID, err := aws.GetAccountParent(ctx, accountID)
+	if err != nil {
+		return envPlan, fmt.Errorf("failed to check OU of AWS account %s: %w", accountName, err)
+	}
+	accountAction := Action{Resource: ResourceAccount, Name: accountName, Type: ActionReuse}
+	if parentID != settings.OUID {
+		accountAction.Type = ActionUpdate
+		accountAction.Changes = []Change{{Field: "parent", From: parentID, To: settings.OUID}}
+	}
+	envPlan.Actions = append(envPlan.Actions, accountAction)
+
+	// OIDC provider
+	oidcARN, err := aws.GetOIDCProviderForGitHub(ctx, accountID)
+	if err != nil {
+		return envPlan, fmt.Errorf("failed to check GitHub OIDC provider in %s: %w", accountName, err)
+	}
+	envPlan.Actions = append(envPlan.Actions, existsAction(ResourceOIDCProvider, githubOIDCProviderName, oidcARN))
+
+	// Deploy role
+	roleARN, err := aws.GetRole(ctx, accountID, roleName)
+	if err != nil {
+		return envPlan, fmt.Errorf("failed to check role %s in %s: %w", roleName, accountName, err)
+	}
+	envPlan.Actions = append(envPlan.Actions, existsAction(ResourceDeployRole, roleName, roleARN))
+
+	// CDK bootstrap, once per region
+	for _, region := range settings.Regions {
+		bootstrapped, err := aws.IsCDKBootstrapped(ctx, accountID, region)
+		if err != nil {
+			return envPlan, fmt.Errorf("failed to check CDK bootstrap in %s (%s): %w", accountName, region, err)
+		}
+		cdk := Action{Resource: ResourceCDKBootstrap, Name: region, Type: ActionCreate}
+		if bootstrapped {
+			cdk.Type = ActionNoOp
+		}
+		envPlan.Actions = append(envPlan.Actions, cdk)
+	}
+
+	// Billing alarm
+	alarm, err := aws.GetBillingAlarm(ctx, accountID, alarmName)
+	if err != nil {
+		return envPlan, fmt.Errorf("failed to check billing alarm in %s: %w", accountName, err)
+	}
+	alarmAction := Action{Resource: ResourceBillingAlarm, Name: alarmName, Type: ActionCreate}
+	if alarm != nil {
+		alarmAction.ARN = alarm.TopicARN
+		alarmAction.Changes = amountChanges(nil, "threshold", alarm.Threshold, settings.Billing.AlertThreshold)
+		alarmAction.Type = changeType(alarmAction.Changes)
+	}
+	envPlan.Actions = append(envPlan.Actions, alarmAction)
+
+	// Budget
+	budget, err := aws.GetBudget(ctx, accountID, budgetName)
+	if err != nil {
+		return envPlan, fmt.Errorf("failed to check budget in %s: %w", accountName, err)
+	}
+	budgetAction := Action{Resource: ResourceBudget, Name: budgetName, Type: ActionCreate}
+	if budget != nil {
+		changes := amountChanges(nil, "limitAmount", budget.LimitAmount, settings.Billing.MonthlyLimit)
+		changes = amountChanges(changes, "alertAmount", budget.AlertAmount, settings.Billing.AlertThreshold)
+		if budget.Email != settings.Billing.NotificationEmail {
+			changes = append(changes, Change{Field: "email", From: budget.Email, To: settings.Billing.NotificationEmail})
+		}
+		if !slices.Equal(budget.AlertPercents, settings.Billing.AlertPercents) {
+			changes = append(changes, Change{
+				Field: "alertPercents",
+				From:  fmt.Sprint(budget.AlertPercents),
+				To:    fmt.Sprint(settings.Billing.AlertPercents),
+			})
+		}
+		budgetAction.Changes = changes
+		budgetAction.Type = changeType(changes)
+	}
+	envPlan.Actions = append(envPlan.Actions, budgetAction)
+
+	return envPlan, nil
+}
+
+// githubOIDCProviderName is the issuer the OIDC provider is named after.
+const githubOIDCProviderName = "token.actions.githubusercontent.com"
+
+func existsAction(resource ResourceType, name, arn string) Action {
+	if arn == "" {
+		return Action{Resource: resource, Name: name, Type: ActionCreate}
+	}
+	return Action{Resource: resource, Name: name, Type: ActionReuse, ARN: arn}
+}
+
+func amountChanges(changes []Change, field string, from, to float64) []Change {
+	if from == to {
+		return changes
+	}
+	return append(changes, Change{Field: field, From: fmt.Sprintf("%.2f", from), To: fmt.Sprintf("%.2f", to)})
+}
+
+func changeType(changes []Change) ActionType {
+	if len(changes) > 0 {
+		return ActionUpdate
+	}
+	return ActionNoOp
+}
+
+// ApplyPlan executes exactly the actions in plan.
+//
+// The plan is re-computed first; if AWS no longer matches (an account
+// appeared, a budget was edited, the configuration changed), ApplyPlan
+// returns a *PlanDriftError without mutating anything.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - aws: AWS client implementation
+//   - config: The same project configuration the plan was built from
+//   - plan: The reviewed plan
+//   - opts: Optional settings (concurrency, journal, rollback)
+//
+// Returns:
+//   - SetupResult with every ARN and ID (created or reused)
+//   - Error if the plan is stale or any action fails
+func ApplyPlan(
+	ctx context.Context,
+	aws ports.AWSClient,
+	config Config,
+	plan *Plan,
+	opts ...Option,
+) (*SetupResult, error) {
+	if plan == nil {
+		return nil, errors.New("plan is required")
+	}
+	config = config.WithDefaults()
+
+	current, err := BuildPlan(ctx, aws, config)
+	if err != nil {
+		return nil, err
+	}
+	if diffs := plan.Diff(current); len(diffs) > 0 {
+		return nil, &PlanDriftError{Differences: diffs}
+	}
+
+	o := newOptions(opts)
+	if err := validateQuarantineOU(o); err != nil {
+		return nil, err
+	}
+
+	s := newSetup(aws, config, o, plan.ManagementAccountID)
+	s.actions = make(map[actionKey]Action)
+	for _, envPlan := range plan.Environments {
+		for _, action := range envPlan.Actions {
+			s.actions[keyFor(envPlan.Environment, action)] = action
+		}
+	}
+
+	return s.execute(ctx)
+}
+
+// HasChanges reports whether applying the plan would mutate anything.
+func (p *Plan) HasChanges() bool {
+	for _, env := range p.Environments {
+		for _, action := range env.Actions {
+			if action.Type.Mutates() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Diff lists differences between p and other, ignoring CreatedAt.
+//
+// Returns:
+//   - Human-readable differences (empty if the plans are equivalent)
+func (p *Plan) Diff(other *Plan) []string {
+	var diffs []string
+
+	if p.ProjectCode != other.ProjectCode {
+		diffs = append(diffs, fmt.Sprintf("project code %s -> %s", p.ProjectCode, other.ProjectCode))
+	}
+	if p.ManagementAccountID != other.ManagementAccountID {
+		diffs = append(diffs, fmt.Sprintf("management account %s -> %s", p.ManagementAccountID, other.ManagementAccountID))
+	}
+	if len(p.Environments) != len(other.Environments) {
+		return append(diffs, fmt.Sprintf("%d environments -> %d", len(p.Environments), len(other.Environments)))
+	}
+
+	for i, want := range p.Environments {
+		got := other.Environments[i]
+		if want.Environment != got.Environment || want.AccountName != got.AccountName {
+			diffs = append(diffs, fmt.Sprintf("environment %s (%s) -> %s (%s)", want.Environment, want.AccountName, got.Environment, got.AccountName))
+			continue
+		}
+		if want.AccountID != got.AccountID {
+			diffs = append(diffs, fmt.Sprintf("%s: account ID %q -> %q", want.Environment, want.AccountID, got.AccountID))
+		}
+		if len(want.Actions) != len(got.Actions) {
+			diffs = append(diffs, fmt.Sprintf("%s: %d actions -> %d", want.Environment, len(want.Actions), len(got.Actions)))
+			continue
+		}
+		for j, wantAction := range want.Actions {
+			gotAction := got.Actions[j]
+			if !actionsEqual(wantAction, gotAction) {
+				diffs = append(diffs, fmt.Sprintf("%s: %s %s planned %s, now %s",
+					want.Environment, wantAction.Resource, wantAction.Name, describeAction(wantAction), describeAction(gotAction)))
+			}
+		}
+	}
+
+	return diffs
+}
+
+func actionsEqual(a, b Action) bool {
+	return a.Resource == b.Resource &&
+		a.Name == b.Name &&
+		a.Type == b.Type &&
+		a.ARN == b.ARN &&
+		slices.Equal(a.Changes, b.Changes)
+}
+
+func describeAction(a Action) string {
+	if len(a.Changes) == 0 {
+		return string(a.Type)
+	}
+	fields := make([]string, len(a.Changes))
+	for i, c := range a.Changes {
+		fields[i] = fmt.Sprintf("%s %s->%s", c.Field, c.From, c.To)
+	}
+	return fmt.Sprintf("%s (%s)", a.Type, strings.Join(fields, ", "))
+}
+
+// Summary renders the plan as human-readable text.
+//
+// This is pure presentation logic - no side effects.
+func (p *Plan) Summary() string {
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("Plan for project %s (management account %s)\n", p.ProjectCode, p.ManagementAccountID))
+	summary.WriteString("==========================================================\n")
+
+	counts := make(map[ActionType]int)
+	for _, env := range p.Environments {
+		accountID := env.AccountID
+		if accountID == "" {
+			accountID = "(new)"
+		}
+		summary.WriteString(fmt.Sprintf("\n%s: %s %s\n", env.Environment, env.AccountName, accountID))
+		for _, action := range env.Actions {
+			counts[action.Type]++
+			summary.WriteString(fmt.Sprintf("  %-7s %-14s %s\n", action.Type, action.Resource, action.Name))
+			for _, c := range action.Changes {
+				summary.WriteString(fmt.Sprintf("          %s: %s -> %s\n", c.Field, c.From, c.To))
+			}
+		}
+	}
+
+	summary.WriteString(fmt.Sprintf("\n%d to create, %d to update, %d to reuse, %d unchanged\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionReuse], counts[ActionNoOp]))
+	return summary.String()
+}
+
+// MarshalPlan serializes a plan to indented JSON for review.
+func MarshalPlan(p *Plan) ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// UnmarshalPlan parses a plan produced by MarshalPlan.
+//
+// Returns:
+//   - The plan
+//   - Error if the JSON is invalid or from an unsupported format version
+func UnmarshalPlan(data []byte) (*Plan, error) {
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if p.Version != PlanFormatVersion {
+		return nil, fmt.Errorf("unsupported plan version %d (expected %d)", p.Version, PlanFormatVersion)
+	}
+	return &p, nil
+}
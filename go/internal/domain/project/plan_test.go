@@ -0,0 +1,264 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// readOnlyPrefixes are the port methods planning is allowed to call.
+var readOnlyPrefixes = []string{"Get", "DescribeAccount(", "IsCDKBootstrapped("}
+
+func assertReadOnly(t *testing.T, ops []string) {
+	t.Helper()
+	for _, op := range ops {
+		readOnly := false
+		for _, prefix := range readOnlyPrefixes {
+			if strings.HasPrefix(op, prefix) {
+				readOnly = true
+			}
+		}
+		if !readOnly {
+			t.Errorf("Planning must not mutate AWS, but performed: %s", op)
+		}
+	}
+}
+
+func TestBuildPlanFreshProject(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+
+	plan, err := BuildPlan(context.Background(), mockAWS, testConfig())
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+	assertReadOnly(t, mockAWS.GetOperations())
+
+	if len(plan.Environments) != 3 {
+		t.Fatalf("Expected 3 environment plans, got %d", len(plan.Environments))
+	}
+	for _, env := range plan.Environments {
+		if env.AccountID != "" {
+			t.Errorf("%s: fresh project should have no account ID, got %s", env.Environment, env.AccountID)
+		}
+		for _, action := range env.Actions {
+			if action.Type != ActionCreate {
+				t.Errorf("%s: %s should be created, planned %s", env.Environment, action.Resource, action.Type)
+			}
+		}
+	}
+	if !plan.HasChanges() {
+		t.Error("Fresh plan should have changes")
+	}
+}
+
+func TestApplyPlan(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := testConfig()
+
+	plan, err := BuildPlan(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+
+	// The plan survives a JSON round trip (e.g., reviewed in a PR)
+	data, err := MarshalPlan(plan)
+	if err != nil {
+		t.Fatalf("MarshalPlan() failed: %v", err)
+	}
+	reviewed, err := UnmarshalPlan(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPlan() failed: %v", err)
+	}
+
+	result, err := ApplyPlan(ctx, mockAWS, config, reviewed)
+	if err != nil {
+		t.Fatalf("ApplyPlan() failed: %v", err)
+	}
+	if len(result.Environments) != 3 || result.Environments[0].RoleARN == "" {
+		t.Errorf("Unexpected apply result: %+v", result)
+	}
+
+	// Re-planning after apply finds nothing left to do
+	again, err := BuildPlan(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("BuildPlan() after apply failed: %v", err)
+	}
+	if again.HasChanges() {
+		t.Errorf("Plan after apply should have no changes:\n%s", again.Summary())
+	}
+
+	// Applying the no-op plan doesn't mutate anything
+	opsBefore := len(mockAWS.GetOperations())
+	if _, err := ApplyPlan(ctx, mockAWS, config, again); err != nil {
+		t.Fatalf("ApplyPlan() of no-op plan failed: %v", err)
+	}
+	assertReadOnly(t, mockAWS.GetOperations()[opsBefore:])
+}
+
+func TestApplyPlanUpdatesBudget(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := testConfig()
+
+	if _, err := SetupProject(ctx, mockAWS, config); err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	config.Billing.MonthlyLimit = 50
+	plan, err := BuildPlan(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+
+	budgetAction := plan.Environments[0].Actions[len(plan.Environments[0].Actions)-1]
+	if budgetAction.Resource != ResourceBudget || budgetAction.Type != ActionUpdate {
+		t.Fatalf("Expected budget update, got %+v", budgetAction)
+	}
+	if len(budgetAction.Changes) != 1 || budgetAction.Changes[0] != (Change{Field: "limitAmount", From: "25.00", To: "50.00"}) {
+		t.Errorf("Unexpected budget changes: %+v", budgetAction.Changes)
+	}
+	if !strings.Contains(plan.Summary(), "limitAmount: 25.00 -> 50.00") {
+		t.Errorf("Summary should show the change:\n%s", plan.Summary())
+	}
+
+	opsBefore := len(mockAWS.GetOperations())
+	result, err := ApplyPlan(ctx, mockAWS, config, plan)
+	if err != nil {
+		t.Fatalf("ApplyPlan() failed: %v", err)
+	}
+
+	updates := 0
+	for _, op := range mockAWS.GetOperations()[opsBefore:] {
+		switch {
+		case strings.HasPrefix(op, "UpdateBudget("):
+			updates++
+		case !slices.ContainsFunc(readOnlyPrefixes, func(prefix string) bool { return strings.HasPrefix(op, prefix) }):
+			t.Errorf("Only budget updates expected, got %s", op)
+		}
+	}
+	if updates != 3 {
+		t.Errorf("Expected 3 budget updates, got %d", updates)
+	}
+
+	budget, _ := mockAWS.GetBudget(ctx, result.Environments[0].Account.AccountID, "TPA-dev-monthly-budget")
+	if budget == nil || budget.LimitAmount != 50 {
+		t.Errorf("Budget should have been updated to $50, got %+v", budget)
+	}
+}
+
+func TestApplyPlanRefusesDrift(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := testConfig()
+
+	plan, err := BuildPlan(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+
+	// Someone creates the dev account between plan and apply
+	if _, err := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"}); err != nil {
+		t.Fatalf("CreateAccount() failed: %v", err)
+	}
+
+	opsBefore := len(mockAWS.GetOperations())
+	_, err = ApplyPlan(ctx, mockAWS, config, plan)
+	if !errors.Is(err, ErrPlanDrift) {
+		t.Fatalf("Expected ErrPlanDrift, got %v", err)
+	}
+
+	var drift *PlanDriftError
+	if !errors.As(err, &drift) || len(drift.Differences) == 0 {
+		t.Fatalf("Expected PlanDriftError with differences, got %v", err)
+	}
+	if !strings.Contains(drift.Differences[0], "dev") {
+		t.Errorf("Difference should mention dev: %v", drift.Differences)
+	}
+	assertReadOnly(t, mockAWS.GetOperations()[opsBefore:])
+}
+
+func TestBuildPlanMovesMisplacedAccount(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := testConfig()
+
+	// The dev account exists but still sits in the organization root
+	devID, err := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	if err != nil {
+		t.Fatalf("CreateAccount() failed: %v", err)
+	}
+
+	plan, err := BuildPlan(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+
+	accountAction := plan.Environments[0].Actions[0]
+	if accountAction.Resource != ResourceAccount || accountAction.Type != ActionUpdate {
+		t.Fatalf("Expected account update, got %+v", accountAction)
+	}
+	want := Change{Field: "parent", From: mock.RootID, To: config.Account.OUID}
+	if len(accountAction.Changes) != 1 || accountAction.Changes[0] != want {
+		t.Errorf("Unexpected account changes: %+v", accountAction.Changes)
+	}
+
+	if _, err := ApplyPlan(ctx, mockAWS, config, plan); err != nil {
+		t.Fatalf("ApplyPlan() failed: %v", err)
+	}
+	if parent, _ := mockAWS.GetAccountParent(ctx, devID); parent != config.Account.OUID {
+		t.Errorf("dev account parent = %s, want %s", parent, config.Account.OUID)
+	}
+}
+
+func TestBuildPlanExistingOrganization(t *testing.T) {
+	mockAWS, err := mock.Fixture("existing-org")
+	if err != nil {
+		t.Fatalf("Fixture() failed: %v", err)
+	}
+
+	plan, err := BuildPlan(context.Background(), mockAWS, testConfig())
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+	assertReadOnly(t, mockAWS.GetOperations())
+
+	// dev is partly bootstrapped, staging only exists, and prod is the
+	// hand-made legacy-prod account, which the plan doesn't pick up by name
+	want := map[string]map[ResourceType]ActionType{
+		"dev":     {ResourceAccount: ActionReuse, ResourceOIDCProvider: ActionReuse, ResourceDeployRole: ActionCreate},
+		"staging": {ResourceAccount: ActionReuse, ResourceOIDCProvider: ActionCreate, ResourceBudget: ActionCreate},
+		"prod":    {ResourceAccount: ActionCreate},
+	}
+	for _, env := range plan.Environments {
+		for _, action := range env.Actions {
+			if wantType, ok := want[string(env.Environment)][action.Resource]; ok && action.Type != wantType {
+				t.Errorf("%s %s: planned %s, want %s", env.Environment, action.Resource, action.Type, wantType)
+			}
+			if env.Environment == "dev" && action.Resource == ResourceBudget && action.Type == ActionCreate {
+				t.Error("dev budget exists in the fixture and should not be created")
+			}
+		}
+	}
+}
+
+func TestUnmarshalPlanRejectsUnknownVersion(t *testing.T) {
+	if _, err := UnmarshalPlan([]byte(`{"version": 99}`)); err == nil {
+		t.Error("UnmarshalPlan() should reject unknown versions")
+	}
+}
+
+func TestApplyPlanRequiresPlan(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+	if _, err := ApplyPlan(context.Background(), mockAWS, testConfig(), nil); err == nil || !strings.Contains(err.Error(), "plan is required") {
+		t.Errorf("ApplyPlan(nil) error = %v, want plan is required", err)
+	}
+	if ops := mockAWS.GetOperations(); len(ops) != 0 {
+		t.Errorf("ApplyPlan(nil) called AWS: %v", ops)
+	}
+}
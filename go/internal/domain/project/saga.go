@@ -0,0 +1,103 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Saga-style rollback for project setup.
+//
+// AWS has no transactions spanning IAM, Budgets, SNS and Organizations. When
+// rollback is enabled, every step that creates something registers a
+// compensating action, and if a later step fails the compensations run in
+// reverse order. Resources that were reused (already existed) are never
+// compensated - rollback only undoes what this run created.
+//
+// AWS accounts can't simply be deleted, so the compensation for a newly
+// created account is to move it into a quarantine OU (when configured).
+
+// compensation undoes one completed step.
+type compensation struct {
+	description string
+	undo        func(ctx context.Context) error
+}
+
+// Saga collects compensating actions for completed steps.
+//
+// A nil *Saga is valid and registers nothing, so setup code can call it
+// unconditionally. Safe for concurrent use.
+type Saga struct {
+	mu    sync.Mutex
+	steps []compensation
+}
+
+// Register records how to undo a step that just completed.
+func (s *Saga) Register(description string, undo func(ctx context.Context) error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, compensation{description: description, undo: undo})
+}
+
+// Compensate runs all registered compensations in reverse order.
+//
+// Every compensation is attempted even if earlier ones fail, so one stuck
+// resource doesn't leave everything else behind.
+//
+// Returns:
+//   - Descriptions of the compensations that succeeded, in execution order
+//   - Errors from compensations that failed (nil if all succeeded)
+func (s *Saga) Compensate(ctx context.Context) ([]string, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	steps := s.steps
+	s.steps = nil
+	s.mu.Unlock()
+
+	var undone []string
+	var failures []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		if err := step.undo(ctx); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", step.description, err))
+			continue
+		}
+		undone = append(undone, step.description)
+	}
+
+	return undone, errors.Join(failures...)
+}
+
+// RollbackError reports a failed setup together with what rollback did.
+//
+// errors.Is/As see both the original failure and any rollback failures.
+type RollbackError struct {
+	Cause       error    // The failure that triggered rollback
+	RolledBack  []string // Compensations that succeeded, in execution order
+	RollbackErr error    // Compensations that failed (nil if rollback was clean)
+}
+
+func (e *RollbackError) Error() string {
+	msg := fmt.Sprintf("setup failed and %d step(s) were rolled back: %v", len(e.RolledBack), e.Cause)
+	if e.RollbackErr != nil {
+		msg += fmt.Sprintf("; rollback incomplete: %s", strings.ReplaceAll(e.RollbackErr.Error(), "\n", "; "))
+	}
+	return msg
+}
+
+// Unwrap returns the original cause and any rollback failures.
+func (e *RollbackError) Unwrap() []error {
+	if e.RollbackErr == nil {
+		return []error{e.Cause}
+	}
+	return []error{e.Cause, e.RollbackErr}
+}
@@ -0,0 +1,221 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// failingCDKAWS fails BootstrapCDK for one account.
+type failingCDKAWS struct {
+	*mock.AWSClient
+	failAccountID string
+}
+
+var errCDK = errors.New("simulated CDK bootstrap failure")
+
+func (f *failingCDKAWS) BootstrapCDK(ctx context.Context, accountID, region, trustAccountID string) error {
+	if accountID == f.failAccountID {
+		return errCDK
+	}
+	return f.AWSClient.BootstrapCDK(ctx, accountID, region, trustAccountID)
+}
+
+func TestSagaCompensatesInReverseOrder(t *testing.T) {
+	saga := &Saga{}
+	var order []string
+
+	saga.Register("first", func(ctx context.Context) error {
+		order = append(order, "first")
+		return nil
+	})
+	saga.Register("second", func(ctx context.Context) error {
+		order = append(order, "second")
+		return errors.New("stuck")
+	})
+	saga.Register("third", func(ctx context.Context) error {
+		order = append(order, "third")
+		return nil
+	})
+
+	undone, err := saga.Compensate(context.Background())
+
+	if strings.Join(order, ",") != "third,second,first" {
+		t.Errorf("Compensation order = %v, want third,second,first", order)
+	}
+	if strings.Join(undone, ",") != "third,first" {
+		t.Errorf("Undone = %v, want third,first", undone)
+	}
+	if err == nil || !strings.Contains(err.Error(), "second: stuck") {
+		t.Errorf("Expected failure for second, got %v", err)
+	}
+}
+
+func TestSetupProjectRollbackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+
+	// Staging will fail during CDK bootstrap, after OIDC and roles exist everywhere
+	stagingID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{
+		Name:      "TPA_STAGING",
+		OrgUnitID: "ou-813y-8teevv2l",
+	})
+	aws := &failingCDKAWS{AWSClient: mockAWS, failAccountID: stagingID}
+
+	_, err := SetupProject(ctx, aws, testConfig(),
+		WithRollbackOnFailure(),
+		WithQuarantineOU("ou-813y-quarant1"),
+	)
+
+	var rollbackErr *RollbackError
+	if !errors.As(err, &rollbackErr) {
+		t.Fatalf("Expected RollbackError, got %v", err)
+	}
+	if !errors.Is(err, errCDK) {
+		t.Errorf("RollbackError should wrap the original cause, got %v", err)
+	}
+	if rollbackErr.RollbackErr != nil {
+		t.Errorf("Rollback should be clean, got %v", rollbackErr.RollbackErr)
+	}
+
+	// Nothing this run created is left behind
+	for _, name := range []string{"TPA_DEV", "TPA_STAGING", "TPA_PROD"} {
+		id, _ := mockAWS.GetAccountByName(ctx, name)
+		if arn, _ := mockAWS.GetOIDCProviderForGitHub(ctx, id); arn != "" {
+			t.Errorf("%s: OIDC provider should have been deleted", name)
+		}
+		if arn, _ := mockAWS.GetRole(ctx, id, "GitHubActionsDeployRole"); arn != "" {
+			t.Errorf("%s: deploy role should have been deleted", name)
+		}
+	}
+
+	// Roles are undone before OIDC providers; new accounts are quarantined last.
+	// Staging existed before the run, so it stays where it was.
+	var deletes, moves []string
+	for _, op := range mockAWS.GetOperations() {
+		switch {
+		case strings.HasPrefix(op, "Delete"):
+			deletes = append(deletes, op)
+		case strings.HasPrefix(op, "MoveAccount(") && strings.Contains(op, "-> ou-813y-quarant1"):
+			moves = append(moves, op)
+		}
+	}
+	if len(deletes) != 6 {
+		t.Fatalf("Expected 3 role + 3 OIDC deletions, got %v", deletes)
+	}
+	for i, op := range deletes {
+		wantPrefix := "DeleteRole("
+		if i >= 3 {
+			wantPrefix = "DeleteOIDCProviderForGitHub("
+		}
+		if !strings.HasPrefix(op, wantPrefix) {
+			t.Errorf("deletes[%d] = %s, want %s...", i, op, wantPrefix)
+		}
+	}
+	if len(moves) != 2 {
+		t.Fatalf("Expected dev and prod to be quarantined, got %v", moves)
+	}
+	for _, op := range moves {
+		if strings.Contains(op, stagingID) || !strings.Contains(op, "-> ou-813y-quarant1") {
+			t.Errorf("Unexpected quarantine move: %s", op)
+		}
+	}
+}
+
+func TestSetupProjectRollbackQuarantinesAccountsFromResumedRun(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	mockAWS.AddFault("BootstrapCDK", mock.FailFor("TPA_DEV", errCDK))
+	store := mock.NewJournalStore()
+
+	// The first run creates every account, then fails without rolling back
+	first, _ := journal.Open(ctx, store, "TPA")
+	if _, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(first)); !errors.Is(err, errCDK) {
+		t.Fatalf("Expected CDK failure, got %v", err)
+	}
+
+	// The resumed run reuses them from the journal, but they are still ours
+	mark := mockAWS.Mark()
+	resumed, _ := journal.Open(ctx, store, "TPA")
+	_, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(resumed),
+		WithRollbackOnFailure(),
+		WithQuarantineOU("ou-813y-quarant1"),
+	)
+	var rollbackErr *RollbackError
+	if !errors.As(err, &rollbackErr) || rollbackErr.RollbackErr != nil {
+		t.Fatalf("Expected a clean rollback, got %v", err)
+	}
+
+	moves := mockAWS.Operations().Since(mark).Method("MoveAccount").Where(func(op mock.Operation) bool {
+		return strings.Contains(op.String(), "-> ou-813y-quarant1")
+	})
+	if moves.Count() != 3 {
+		t.Errorf("Expected every account to be quarantined, got %v", moves)
+	}
+
+	// Once the fault is gone, resuming moves the accounts out of quarantine
+	mockAWS.ClearFaults()
+	again, _ := journal.Open(ctx, store, "TPA")
+	result, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(again))
+	if err != nil {
+		t.Fatalf("SetupProject() after rollback failed: %v", err)
+	}
+	for _, env := range result.Environments {
+		if parent, _ := mockAWS.GetAccountParent(ctx, env.Account.AccountID); parent != "ou-813y-8teevv2l" {
+			t.Errorf("%s: parent = %s, want it back in the project OU", env.Account.Name, parent)
+		}
+	}
+}
+
+func TestSetupProjectRollbackKeepsPreExistingResources(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+
+	// Dev already has a deploy role from an earlier, manual setup
+	devID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+	_, _ = mockAWS.CreateGitHubActionsRole(ctx, ports.AWSCreateRoleRequest{AccountID: devID, RoleName: "GitHubActionsDeployRole"})
+
+	aws := &failingCDKAWS{AWSClient: mockAWS, failAccountID: devID}
+	_, err := SetupProject(ctx, aws, testConfig(), WithRollbackOnFailure())
+	if err == nil {
+		t.Fatal("SetupProject() should fail")
+	}
+
+	if arn, _ := mockAWS.GetRole(ctx, devID, "GitHubActionsDeployRole"); arn == "" {
+		t.Error("Pre-existing role must survive rollback")
+	}
+}
+
+func TestSetupProjectWithoutRollbackLeavesResources(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	devID, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_DEV"})
+
+	_, err := SetupProject(ctx, &failingCDKAWS{AWSClient: mockAWS, failAccountID: devID}, testConfig())
+	if !errors.Is(err, errCDK) {
+		t.Fatalf("Expected CDK failure, got %v", err)
+	}
+
+	var rollbackErr *RollbackError
+	if errors.As(err, &rollbackErr) {
+		t.Error("Rollback must be opt-in")
+	}
+	if arn, _ := mockAWS.GetRole(ctx, devID, "GitHubActionsDeployRole"); arn == "" {
+		t.Error("Without rollback the role stays in place")
+	}
+}
+
+func TestSetupProjectRejectsInvalidQuarantineOU(t *testing.T) {
+	_, err := SetupProject(context.Background(), mock.NewAWSClient(), testConfig(),
+		WithRollbackOnFailure(),
+		WithQuarantineOU("quarantine"),
+	)
+	if err == nil || !strings.Contains(err.Error(), "quarantine OU") {
+		t.Errorf("Expected invalid quarantine OU error, got %v", err)
+	}
+}
@@ -0,0 +1,591 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/progress"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Option configures project setup.
+type Option func(*options)
+
+type options struct {
+	concurrency       int
+	journal           *journal.Journal
+	rollbackOnFailure bool
+	quarantineOUID    string
+	preflight         bool
+	stateStore        ports.StateStore
+	progress          ports.ProgressReporter
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithConcurrency provisions up to n accounts in parallel (see account.WithConcurrency).
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithJournal records every completed step in j so a failed setup can be
+// re-run and pick up where it stopped.
+func WithJournal(j *journal.Journal) Option {
+	return func(o *options) {
+		o.journal = j
+	}
+}
+
+// WithProgress reports every step to reporter: the account steps (see
+// account.WithProgress), then one step per phase and environment, named
+// after the resource (oidc-provider, deploy-role, cdk-bootstrap) or
+// "billing" for the alarm and budget.
+func WithProgress(reporter ports.ProgressReporter) Option {
+	return func(o *options) {
+		o.progress = reporter
+	}
+}
+
+// WithPreflight checks the organization for conflicting account names and
+// root emails before creating any account (see account.Preflight).
+func WithPreflight() Option {
+	return func(o *options) {
+		o.preflight = true
+	}
+}
+
+// WithRollbackOnFailure undoes everything this run created if a later step fails.
+//
+// Compensations run in reverse order: budgets, alarms and SNS topics are
+// deleted, then deploy roles and OIDC providers. Resources that existed
+// before the run are left alone. CDK bootstrap stacks are not removed.
+// The returned error is a *RollbackError describing what was undone.
+func WithRollbackOnFailure() Option {
+	return func(o *options) {
+		o.rollbackOnFailure = true
+	}
+}
+
+// WithQuarantineOU moves accounts created by a failed run into ouID during
+// rollback. AWS accounts can't be deleted outright, so this parks them
+// somewhere harmless (typically an OU with a deny-all SCP) for review.
+// Only applies together with WithRollbackOnFailure.
+func WithQuarantineOU(ouID string) Option {
+	return func(o *options) {
+		o.quarantineOUID = ouID
+	}
+}
+
+// SetupResult describes everything SetupProject produced.
+type SetupResult struct {
+	ManagementAccountID string              // Account trusted by CDK bootstrap
+	Environments        []EnvironmentResult // Ordered by environment
+}
+
+// EnvironmentResult holds the ARNs and IDs produced for one environment.
+type EnvironmentResult struct {
+	Account         account.AccountInfo
+	OIDCProviderARN string   // GitHub OIDC identity provider
+	RoleARN         string   // GitHub Actions deploy role
+	CDKRegions      []string // Regions where CDK was bootstrapped
+	TopicARN        string   // SNS topic for billing alerts
+	AlarmName       string   // CloudWatch billing alarm
+	BudgetName      string   // AWS Budget
+}
+
+// SetupProject runs the complete project setup.
+//
+// This is DOMAIN LOGIC - the same sequence as setup-complete-project.sh:
+//  1. Create (or reuse) one AWS account per environment
+//  2. Create the GitHub OIDC provider in each account
+//  3. Create the GitHub Actions deploy role in each account
+//  4. Bootstrap CDK in each account, trusting the management account
+//  5. Create billing alerts (SNS topic, email subscription, alarm, budget)
+//
+// Each phase runs for every environment before the next phase starts, like
+// the v1 scripts.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - aws: AWS client implementation (real AWS SDK or mock for testing)
+//   - config: Project configuration (defaults are applied)
+//   - opts: Optional settings (concurrency, journal, rollback, state store, progress)
+//
+// Returns:
+//   - SetupResult with every ARN and ID produced
+//   - Error if any step fails (with the result, if only saving state failed)
+func SetupProject(
+	ctx context.Context,
+	aws ports.AWSClient,
+	config Config,
+	opts ...Option,
+) (*SetupResult, error) {
+	config = config.WithDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	o := newOptions(opts)
+	if err := validateQuarantineOU(o); err != nil {
+		return nil, err
+	}
+
+	// CDK bootstrap trusts the account we're running from
+	identity, err := aws.GetCallerIdentity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	return newSetup(aws, config, o, identity.AccountID).execute(ctx)
+}
+
+func validateQuarantineOU(o options) error {
+	if o.quarantineOUID == "" {
+		return nil
+	}
+	if err := account.ValidateOUID(o.quarantineOUID); err != nil {
+		return fmt.Errorf("invalid quarantine OU: %w", err)
+	}
+	return nil
+}
+
+// setup carries shared state through the setup phases.
+type setup struct {
+	aws          ports.AWSClient
+	config       Config
+	journal      *journal.Journal
+	managementID string
+
+	// actions is set when applying a Plan. Resources the plan marks as
+	// reuse/no-op are skipped; nil means "ensure everything".
+	actions map[actionKey]Action
+
+	// saga is non-nil when rollback on failure is enabled.
+	saga           *Saga
+	quarantineOUID string
+	concurrency    int
+	preflight      bool
+	stateStore     ports.StateStore
+	state          *ports.ProjectState // Loaded by execute; nil if nothing is recorded
+	progress       *progress.Tracker
+	reporter       ports.ProgressReporter
+}
+
+func newSetup(aws ports.AWSClient, config Config, o options, managementID string) *setup {
+	s := &setup{
+		aws:            aws,
+		config:         config,
+		journal:        o.journal,
+		managementID:   managementID,
+		quarantineOUID: o.quarantineOUID,
+		concurrency:    o.concurrency,
+		preflight:      o.preflight,
+		stateStore:     o.stateStore,
+		progress:       progress.New(o.progress),
+		reporter:       o.progress,
+	}
+	if o.rollbackOnFailure {
+		s.saga = &Saga{}
+	}
+	return s
+}
+
+// execute runs every step and rolls back on failure if enabled.
+func (s *setup) execute(ctx context.Context) (result *SetupResult, err error) {
+	unlock, err := lockState(ctx, s.stateStore, s.config.Account.ProjectCode)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Unlock even if the run was cancelled
+		if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+			err = fmt.Errorf("failed to unlock state: %w", unlockErr)
+		}
+	}()
+
+	if err := s.loadState(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err = s.createAccounts(ctx)
+	if err == nil {
+		err = s.run(ctx, result)
+	}
+	if err != nil {
+		return nil, s.rollback(ctx, err)
+	}
+	// Everything exists at this point; a failed save must not roll it back
+	if err := s.saveState(ctx, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// createAccounts runs step 1 and seeds the result with one entry per environment.
+func (s *setup) createAccounts(ctx context.Context) (*SetupResult, error) {
+	accountOpts := []account.Option{account.WithJournal(s.journal)}
+	if recorded := recordedAccounts(s.state); len(recorded) > 0 {
+		accountOpts = append(accountOpts, account.WithRecordedAccounts(recorded))
+	}
+	if s.concurrency > 0 {
+		accountOpts = append(accountOpts, account.WithConcurrency(s.concurrency))
+	}
+	if s.preflight {
+		accountOpts = append(accountOpts, account.WithPreflight())
+	}
+	if s.reporter != nil {
+		accountOpts = append(accountOpts, account.WithProgress(s.reporter))
+	}
+	if s.saga != nil {
+		// Rollback needs to know about every account that was created,
+		// including ones that finished before another environment failed.
+		accountOpts = append(accountOpts, account.WithErrorMode(account.CollectAllErrors))
+	}
+
+	accounts, err := account.CreateAllAccounts(ctx, s.aws, s.config.Account, accountOpts...)
+
+	if s.quarantineOUID != "" {
+		for _, info := range accounts {
+			if info.Created {
+				s.registerQuarantine(info)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SetupResult{
+		ManagementAccountID: s.managementID,
+		Environments:        make([]EnvironmentResult, len(accounts)),
+	}
+	for i, info := range accounts {
+		result.Environments[i] = EnvironmentResult{Account: info}
+	}
+	return result, nil
+}
+
+func (s *setup) registerQuarantine(info account.AccountInfo) {
+	s.saga.Register(fmt.Sprintf("move account %s to quarantine OU %s", info.Name, s.quarantineOUID), func(ctx context.Context) error {
+		parentID, err := s.aws.GetAccountParent(ctx, info.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := s.aws.MoveAccount(ctx, info.AccountID, parentID, s.quarantineOUID); err != nil {
+			return err
+		}
+		return s.journal.Forget(ctx, string(info.Environment), journal.StepAccountPlaced)
+	})
+}
+
+// rollback runs compensations after a failure and reports what happened.
+func (s *setup) rollback(ctx context.Context, cause error) error {
+	if s.saga == nil {
+		return cause
+	}
+
+	// Clean up even if the failure was a cancelled context
+	undone, err := s.saga.Compensate(context.WithoutCancel(ctx))
+	return &RollbackError{
+		Cause:       cause,
+		RolledBack:  undone,
+		RollbackErr: err,
+	}
+}
+
+// existedBefore reports whether a resource already existed, so rollback
+// never deletes something this run didn't create. Only checked when
+// rollback is enabled.
+func (s *setup) existedBefore(lookup func() (bool, error)) (bool, error) {
+	if s.saga == nil {
+		return false, nil
+	}
+	return lookup()
+}
+
+// run executes steps 2-5, one phase at a time across all environments.
+func (s *setup) run(ctx context.Context, result *SetupResult) error {
+	phases := []struct {
+		name string
+		run  func(context.Context, *EnvironmentResult) error
+	}{
+		{string(ResourceOIDCProvider), s.createOIDCProvider},
+		{string(ResourceDeployRole), s.createDeployRole},
+		{string(ResourceCDKBootstrap), s.bootstrapCDK},
+		{"billing", s.createBillingAlerts},
+	}
+
+	for _, phase := range phases {
+		for i := range result.Environments {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			acct := result.Environments[i].Account
+			step := s.progress.Start(string(acct.Environment), phase.name, acct.Name, acct.AccountID)
+			if err := step.End(phase.run(ctx, &result.Environments[i])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// planned returns the plan's action for a resource, if applying a plan.
+func (s *setup) planned(env account.Environment, resource ResourceType) (Action, bool) {
+	action, ok := s.actions[actionKey{env: env, resource: resource}]
+	return action, ok
+}
+
+// unchanged reports whether the plan leaves a resource as it is.
+func (s *setup) unchanged(env account.Environment, resource ResourceType) bool {
+	return s.unchangedIn(env, resource, "")
+}
+
+// unchangedIn is unchanged for per-region resources (CDK bootstrap).
+func (s *setup) unchangedIn(env account.Environment, resource ResourceType, region string) bool {
+	action, ok := s.actions[actionKey{env: env, resource: resource, region: region}]
+	return ok && !action.Type.Mutates()
+}
+
+func (s *setup) createOIDCProvider(ctx context.Context, env *EnvironmentResult) error {
+	acct := env.Account
+	env.OIDCProviderARN = GitHubOIDCProviderARN(acct.AccountID)
+
+	if s.unchanged(acct.Environment, ResourceOIDCProvider) {
+		return nil
+	}
+	if _, done := s.journal.Lookup(string(acct.Environment), journal.StepOIDCCreated); done {
+		return nil
+	}
+
+	existed, err := s.existedBefore(func() (bool, error) {
+		arn, err := s.aws.GetOIDCProviderForGitHub(ctx, acct.AccountID)
+		return arn != "", err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check GitHub OIDC provider in %s: %w", acct.Name, err)
+	}
+
+	if err := s.aws.CreateOIDCProviderForGitHub(ctx, acct.AccountID); err != nil {
+		return fmt.Errorf("failed to create GitHub OIDC provider in %s: %w", acct.Name, err)
+	}
+
+	if !existed {
+		s.saga.Register(fmt.Sprintf("delete GitHub OIDC provider in %s", acct.Name), func(ctx context.Context) error {
+			if err := s.aws.DeleteOIDCProviderForGitHub(ctx, acct.AccountID); err != nil {
+				return err
+			}
+			return s.journal.Forget(ctx, string(acct.Environment), journal.StepOIDCCreated)
+		})
+	}
+
+	return s.journal.Record(ctx, string(acct.Environment), journal.StepOIDCCreated, acct.AccountID, env.OIDCProviderARN)
+}
+
+func (s *setup) createDeployRole(ctx context.Context, env *EnvironmentResult) error {
+	acct := env.Account
+	roleName := s.config.roleName(acct.Environment)
+
+	if action, ok := s.planned(acct.Environment, ResourceDeployRole); ok && !action.Type.Mutates() {
+		env.RoleARN = action.ARN
+		return nil
+	}
+	if entry, done := s.journal.Lookup(string(acct.Environment), journal.StepRoleCreated); done {
+		env.RoleARN = entry.ResourceID
+		return nil
+	}
+
+	existed, err := s.existedBefore(func() (bool, error) {
+		arn, err := s.aws.GetRole(ctx, acct.AccountID, roleName)
+		return arn != "", err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check role %s in %s: %w", roleName, acct.Name, err)
+	}
+
+	roleARN, err := s.aws.CreateGitHubActionsRole(ctx, ports.AWSCreateRoleRequest{
+		AccountID:        acct.AccountID,
+		RoleName:         roleName,
+		GitHubOrg:        s.config.GitHubOrg,
+		GitHubRepo:       s.config.GitHubRepo,
+		PolicyARNs:       s.config.settingsFor(acct.Environment).PolicyARNs,
+		AllowAllBranches: s.config.AllowAllBranches,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub Actions role in %s: %w", acct.Name, err)
+	}
+	env.RoleARN = roleARN
+
+	if !existed {
+		s.saga.Register(fmt.Sprintf("delete role %s in %s", roleName, acct.Name), func(ctx context.Context) error {
+			if err := s.aws.DeleteRole(ctx, acct.AccountID, roleName); err != nil {
+				return err
+			}
+			return s.journal.Forget(ctx, string(acct.Environment), journal.StepRoleCreated)
+		})
+	}
+
+	return s.journal.Record(ctx, string(acct.Environment), journal.StepRoleCreated, acct.AccountID, roleARN)
+}
+
+func (s *setup) bootstrapCDK(ctx context.Context, env *EnvironmentResult) error {
+	acct := env.Account
+	env.CDKRegions = s.config.settingsFor(acct.Environment).Regions
+
+	for _, region := range env.CDKRegions {
+		if s.unchangedIn(acct.Environment, ResourceCDKBootstrap, region) {
+			continue
+		}
+		step := journal.CDKBootstrapStep(region)
+		if _, done := s.journal.Lookup(string(acct.Environment), step); done {
+			continue
+		}
+		// Journals written before per-region bootstrap used a single step
+		if entry, done := s.journal.Lookup(string(acct.Environment), journal.StepCDKBootstrapped); done && entry.ResourceID == region {
+			continue
+		}
+
+		if err := s.aws.BootstrapCDK(ctx, acct.AccountID, region, s.managementID); err != nil {
+			return fmt.Errorf("failed to bootstrap CDK in %s (%s): %w", acct.Name, region, err)
+		}
+
+		if err := s.journal.Record(ctx, string(acct.Environment), step, acct.AccountID, region); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *setup) createBillingAlerts(ctx context.Context, env *EnvironmentResult) error {
+	acct := env.Account
+	billing := s.config.settingsFor(acct.Environment).Billing
+	names := s.config.Account.ResourceNames(acct.Environment)
+
+	env.AlarmName = names.Alarm
+	env.BudgetName = names.Budget
+
+	if action, ok := s.planned(acct.Environment, ResourceBillingAlarm); ok && !action.Type.Mutates() {
+		env.TopicARN = action.ARN
+	} else if err := s.createAlarm(ctx, env); err != nil {
+		return err
+	}
+
+	budget := ports.AWSCreateBudgetRequest{
+		AccountID:     acct.AccountID,
+		BudgetName:    env.BudgetName,
+		LimitAmount:   billing.MonthlyLimit,
+		AlertAmount:   billing.AlertThreshold,
+		Email:         billing.NotificationEmail,
+		AlertPercents: billing.AlertPercents,
+	}
+
+	if action, ok := s.planned(acct.Environment, ResourceBudget); ok {
+		switch action.Type {
+		case ActionUpdate:
+			if err := s.aws.UpdateBudget(ctx, budget); err != nil {
+				return fmt.Errorf("failed to update budget in %s: %w", acct.Name, err)
+			}
+			return s.journal.Record(ctx, string(acct.Environment), journal.StepBudgetCreated, acct.AccountID, env.BudgetName)
+		case ActionReuse, ActionNoOp:
+			return nil
+		}
+	}
+	if _, done := s.journal.Lookup(string(acct.Environment), journal.StepBudgetCreated); done {
+		return nil
+	}
+
+	existed, err := s.existedBefore(func() (bool, error) {
+		existing, err := s.aws.GetBudget(ctx, acct.AccountID, env.BudgetName)
+		return existing != nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check budget in %s: %w", acct.Name, err)
+	}
+
+	if err := s.aws.CreateBudget(ctx, budget); err != nil {
+		return fmt.Errorf("failed to create budget in %s: %w", acct.Name, err)
+	}
+
+	if !existed {
+		s.saga.Register(fmt.Sprintf("delete budget %s in %s", env.BudgetName, acct.Name), func(ctx context.Context) error {
+			if err := s.aws.DeleteBudget(ctx, acct.AccountID, env.BudgetName); err != nil {
+				return err
+			}
+			return s.journal.Forget(ctx, string(acct.Environment), journal.StepBudgetCreated)
+		})
+	}
+
+	return s.journal.Record(ctx, string(acct.Environment), journal.StepBudgetCreated, acct.AccountID, env.BudgetName)
+}
+
+// createAlarm creates the SNS topic, email subscription and billing alarm.
+//
+// SNS CreateTopic, Subscribe and PutMetricAlarm are idempotent in AWS, so
+// these aren't journaled - re-running them is harmless.
+func (s *setup) createAlarm(ctx context.Context, env *EnvironmentResult) error {
+	acct := env.Account
+	billing := s.config.settingsFor(acct.Environment).Billing
+	topicName := s.config.Account.ResourceNames(acct.Environment).Topic
+
+	// The alarm stands in for the topic and subscription: if it already
+	// existed, so did they
+	existed, err := s.existedBefore(func() (bool, error) {
+		alarm, err := s.aws.GetBillingAlarm(ctx, acct.AccountID, env.AlarmName)
+		return alarm != nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check billing alarm in %s: %w", acct.Name, err)
+	}
+
+	topicARN, err := s.aws.CreateSNSTopic(ctx, acct.AccountID, topicName)
+	if err != nil {
+		return fmt.Errorf("failed to create billing SNS topic in %s: %w", acct.Name, err)
+	}
+	env.TopicARN = topicARN
+
+	if !existed {
+		s.saga.Register(fmt.Sprintf("delete SNS topic %s", topicARN), func(ctx context.Context) error {
+			return s.aws.DeleteSNSTopic(ctx, topicARN)
+		})
+	}
+
+	if err := s.aws.SubscribeEmailToSNSTopic(ctx, topicARN, billing.NotificationEmail); err != nil {
+		return fmt.Errorf("failed to subscribe %s to billing alerts in %s: %w", billing.NotificationEmail, acct.Name, err)
+	}
+
+	if err := s.aws.CreateBillingAlarm(ctx, ports.AWSCreateBillingAlarmRequest{
+		AccountID: acct.AccountID,
+		AlarmName: env.AlarmName,
+		Threshold: billing.AlertThreshold,
+		TopicARN:  topicARN,
+	}); err != nil {
+		return fmt.Errorf("failed to create billing alarm in %s: %w", acct.Name, err)
+	}
+
+	if !existed {
+		s.saga.Register(fmt.Sprintf("delete billing alarm %s in %s", env.AlarmName, acct.Name), func(ctx context.Context) error {
+			return s.aws.DeleteBillingAlarm(ctx, acct.AccountID, env.AlarmName)
+		})
+	}
+
+	return nil
+}
+
+// GitHubOIDCProviderARN returns the ARN of the GitHub Actions OIDC provider in an account.
+//
+// IAM names OIDC providers after their issuer URL, so the ARN is fixed.
+func GitHubOIDCProviderARN(accountID string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:oidc-provider/%s", accountID, githubOIDCProviderName)
+}
@@ -0,0 +1,446 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/journal"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func testConfig() Config {
+	return Config{
+		Account: account.Config{
+			ProjectCode: "TPA",
+			EmailPrefix: "user",
+			OUID:        "ou-813y-8teevv2l",
+		},
+		GitHubOrg:  "myorg",
+		GitHubRepo: "myrepo",
+	}
+}
+
+// failingRoleAWS fails CreateGitHubActionsRole for one account.
+type failingRoleAWS struct {
+	*mock.AWSClient
+	failAccountID string
+}
+
+func (f *failingRoleAWS) CreateGitHubActionsRole(ctx context.Context, req ports.AWSCreateRoleRequest) (string, error) {
+	if req.AccountID == f.failAccountID {
+		return "", errors.New("simulated IAM failure")
+	}
+	return f.AWSClient.CreateGitHubActionsRole(ctx, req)
+}
+
+// policyRecordingAWS records the policies attached to each deploy role.
+type policyRecordingAWS struct {
+	*mock.AWSClient
+	policies map[string][]string
+}
+
+func (p *policyRecordingAWS) CreateGitHubActionsRole(ctx context.Context, req ports.AWSCreateRoleRequest) (string, error) {
+	p.policies[req.AccountID] = req.PolicyARNs
+	return p.AWSClient.CreateGitHubActionsRole(ctx, req)
+}
+
+func TestSetupProject(t *testing.T) {
+	mockAWS := mock.NewAWSClient()
+
+	result, err := SetupProject(context.Background(), mockAWS, testConfig())
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	if result.ManagementAccountID != "999999999999" {
+		t.Errorf("ManagementAccountID = %s, want mock caller account", result.ManagementAccountID)
+	}
+
+	if len(result.Environments) != 3 {
+		t.Fatalf("Expected 3 environments, got %d", len(result.Environments))
+	}
+
+	for i, env := range result.Environments {
+		id := env.Account.AccountID
+		if env.Account.Environment != account.AllEnvironments()[i] {
+			t.Errorf("Environments[%d] = %s, want %s", i, env.Account.Environment, account.AllEnvironments()[i])
+		}
+		if want := "arn:aws:iam::" + id + ":oidc-provider/token.actions.githubusercontent.com"; env.OIDCProviderARN != want {
+			t.Errorf("OIDCProviderARN = %s, want %s", env.OIDCProviderARN, want)
+		}
+		if want := "arn:aws:iam::" + id + ":role/GitHubActionsDeployRole"; env.RoleARN != want {
+			t.Errorf("RoleARN = %s, want %s", env.RoleARN, want)
+		}
+		if !slices.Equal(env.CDKRegions, []string{DefaultRegion}) {
+			t.Errorf("CDKRegions = %v, want [%s]", env.CDKRegions, DefaultRegion)
+		}
+		if !strings.HasSuffix(env.TopicARN, ":"+id+":TPA-"+string(env.Account.Environment)+"-billing-alerts") {
+			t.Errorf("Unexpected TopicARN: %s", env.TopicARN)
+		}
+		if env.BudgetName != "TPA-"+string(env.Account.Environment)+"-monthly-budget" {
+			t.Errorf("Unexpected BudgetName: %s", env.BudgetName)
+		}
+	}
+
+	// CDK bootstrap must trust the management account
+	ops := mockAWS.GetOperations()
+	bootstraps := 0
+	for _, op := range ops {
+		if strings.HasPrefix(op, "BootstrapCDK(") {
+			bootstraps++
+			if !strings.Contains(op, "trust=999999999999") {
+				t.Errorf("CDK bootstrap should trust the management account: %s", op)
+			}
+		}
+	}
+	if bootstraps != 3 {
+		t.Errorf("Expected 3 CDK bootstraps, got %d", bootstraps)
+	}
+
+	// Phases run in order: all OIDC providers before any role, etc.
+	assertPhaseOrder(t, ops, "CreateOIDCProviderForGitHub", "CreateGitHubActionsRole", "BootstrapCDK", "CreateSNSTopic")
+}
+
+func assertPhaseOrder(t *testing.T, ops []string, phases ...string) {
+	t.Helper()
+	last := make(map[string]int)
+	first := make(map[string]int)
+	for i, op := range ops {
+		for _, phase := range phases {
+			if strings.HasPrefix(op, phase+"(") {
+				if _, seen := first[phase]; !seen {
+					first[phase] = i
+				}
+				last[phase] = i
+			}
+		}
+	}
+	for i := 1; i < len(phases); i++ {
+		if last[phases[i-1]] > first[phases[i]] {
+			t.Errorf("Phase %s should finish before %s starts", phases[i-1], phases[i])
+		}
+	}
+}
+
+func TestSetupProjectResumesWithJournal(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewJournalStore()
+	mockAWS := mock.NewAWSClient()
+
+	// First run: role creation fails in staging
+	staging, _ := mockAWS.CreateAccount(ctx, ports.AWSCreateAccountRequest{Name: "TPA_STAGING"})
+	first, _ := journal.Open(ctx, store, "TPA")
+	_, err := SetupProject(ctx, &failingRoleAWS{AWSClient: mockAWS, failAccountID: staging}, testConfig(), WithJournal(first))
+	if err == nil {
+		t.Fatal("First run should fail")
+	}
+
+	// Second run picks up where the first stopped
+	opsBefore := len(mockAWS.GetOperations())
+	second, _ := journal.Open(ctx, store, "TPA")
+	result, err := SetupProject(ctx, mockAWS, testConfig(), WithJournal(second))
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+
+	devID := result.Environments[0].Account.AccountID
+	for _, op := range mockAWS.GetOperations()[opsBefore:] {
+		if strings.HasPrefix(op, "CreateOIDCProviderForGitHub(") {
+			t.Errorf("OIDC providers were all created in the first run, got %s", op)
+		}
+		if strings.HasPrefix(op, "CreateGitHubActionsRole("+devID) {
+			t.Errorf("Dev role was created in the first run, got %s", op)
+		}
+	}
+
+	// Journaled ARNs are still reported
+	if result.Environments[0].RoleARN == "" {
+		t.Error("Resumed dev environment should report its role ARN")
+	}
+}
+
+func TestSetupProjectReportsProgress(t *testing.T) {
+	recorder := mock.NewProgressRecorder()
+	if _, err := SetupProject(context.Background(), mock.NewAWSClient(), testConfig(), WithProgress(recorder)); err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	var finished []string
+	for _, e := range recorder.EventsFor("prod") {
+		if e.Type == ports.EventStepSucceeded {
+			finished = append(finished, e.Step)
+		}
+	}
+	want := []string{"create", "wait", "place", "oidc-provider", "deploy-role", "cdk-bootstrap", "billing"}
+	if !slices.Equal(finished, want) {
+		t.Errorf("prod steps = %v, want %v", finished, want)
+	}
+}
+
+func TestSetupProjectInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		field  string
+	}{
+		{name: "missing GitHub org", modify: func(c *Config) { c.GitHubOrg = "" }, field: "githubOrg"},
+		{name: "invalid GitHub repo", modify: func(c *Config) { c.GitHubRepo = "my repo" }, field: "githubRepo"},
+		{name: "alert above limit", modify: func(c *Config) { c.Billing.AlertThreshold = 50 }, field: "billing.alertThreshold"},
+		{name: "invalid account config", modify: func(c *Config) { c.Account.ProjectCode = "AB" }, field: "projectCode"},
+		{name: "no mailbox for alerts", modify: func(c *Config) {
+			c.Account.EmailPrefix = ""
+			c.Account.Email = account.EmailStrategy{Scheme: account.EmailSchemeSubdomain, Domain: "aws.example.com"}
+		}, field: "billing.notificationEmail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			tt.modify(&config)
+
+			mockAWS := mock.NewAWSClient()
+			_, err := SetupProject(context.Background(), mockAWS, config)
+
+			var validationErr *account.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("Field = %s, want %s", validationErr.Field, tt.field)
+			}
+			if ops := mockAWS.GetOperations(); len(ops) != 0 {
+				t.Errorf("No AWS calls expected for invalid config, got %v", ops)
+			}
+		})
+	}
+}
+
+func TestConfigWithDefaults(t *testing.T) {
+	config := testConfig().WithDefaults()
+
+	if config.Region != "us-east-1" {
+		t.Errorf("Region = %s, want us-east-1", config.Region)
+	}
+	if got := config.roleName(account.EnvironmentDev); got != "GitHubActionsDeployRole" {
+		t.Errorf("roleName() = %s, want GitHubActionsDeployRole", got)
+	}
+	if config.Billing.MonthlyLimit != 25 || config.Billing.AlertThreshold != 15 {
+		t.Errorf("Billing defaults = %+v, want limit 25 / alert 15", config.Billing)
+	}
+	if config.Billing.NotificationEmail != "user@gmail.com" {
+		t.Errorf("NotificationEmail = %s, want user@gmail.com", config.Billing.NotificationEmail)
+	}
+
+	config = testConfig()
+	config.Account.EmailPrefix = "cloud@example.com"
+	if got := config.WithDefaults().Billing.NotificationEmail; got != "cloud@example.com" {
+		t.Errorf("NotificationEmail = %s, want cloud@example.com", got)
+	}
+}
+
+func TestSetupProjectEnvironmentDefaults(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := testConfig()
+	config.Account.EnvironmentSpecs = []account.EnvironmentSpec{
+		{Name: "qa", Order: 1},
+		{Name: "prod", Order: 2, Production: true, Defaults: account.EnvironmentSettings{
+			Regions:        []string{"eu-west-1"},
+			MonthlyLimit:   500,
+			AlertThreshold: 400,
+		}},
+	}
+
+	result, err := SetupProject(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	if len(result.Environments) != 2 {
+		t.Fatalf("Expected 2 environments, got %d", len(result.Environments))
+	}
+
+	qa, prod := result.Environments[0], result.Environments[1]
+	if !slices.Equal(qa.CDKRegions, []string{DefaultRegion}) || !slices.Equal(prod.CDKRegions, []string{"eu-west-1"}) {
+		t.Errorf("CDK regions = %v, %v; want [%s], [eu-west-1]", qa.CDKRegions, prod.CDKRegions, DefaultRegion)
+	}
+
+	qaBudget, _ := mockAWS.GetBudget(ctx, qa.Account.AccountID, qa.BudgetName)
+	if qaBudget == nil || qaBudget.LimitAmount != DefaultMonthlyLimit {
+		t.Errorf("qa budget = %+v, want project-wide $%.2f", qaBudget, DefaultMonthlyLimit)
+	}
+	prodBudget, _ := mockAWS.GetBudget(ctx, prod.Account.AccountID, prod.BudgetName)
+	if prodBudget == nil || prodBudget.LimitAmount != 500 || prodBudget.AlertAmount != 400 {
+		t.Errorf("prod budget = %+v, want $500 limit and $400 alert", prodBudget)
+	}
+	prodAlarm, _ := mockAWS.GetBillingAlarm(ctx, prod.Account.AccountID, prod.AlarmName)
+	if prodAlarm == nil || prodAlarm.Threshold != 400 {
+		t.Errorf("prod alarm = %+v, want $400 threshold", prodAlarm)
+	}
+
+	// Re-planning with the same config finds nothing to change
+	plan, err := BuildPlan(ctx, mockAWS, config.WithDefaults())
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("Plan after setup should have no changes:\n%s", plan.Summary())
+	}
+}
+
+func TestSetupProjectRejectsInvalidEnvironmentDefaults(t *testing.T) {
+	config := testConfig()
+	config.Account.EnvironmentSpecs = []account.EnvironmentSpec{
+		{Name: "prod", Defaults: account.EnvironmentSettings{AlertThreshold: 100}}, // above the $25 project limit
+	}
+
+	_, err := SetupProject(context.Background(), mock.NewAWSClient(), config)
+
+	var validationErr *account.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "environments.prod.alertThreshold" {
+		t.Errorf("Expected environments.prod.alertThreshold validation error, got %v", err)
+	}
+}
+
+func TestConfigValidateAllReportsEnvironmentBillingPaths(t *testing.T) {
+	config := testConfig()
+	config.Account.Overrides = map[account.Environment]account.EnvironmentSettings{
+		account.EnvironmentDev: {MonthlyLimit: 10, AlertThreshold: 20},
+	}
+
+	// Same field names as the account report's override fields, but the
+	// resolved amounts aren't tied to one block
+	var fields []string
+	for _, e := range config.ValidateAll().Errors {
+		fields = append(fields, e.Field)
+	}
+	if !slices.Contains(fields, "environments.dev.alertThreshold") {
+		t.Errorf("fields = %v, want environments.dev.alertThreshold", fields)
+	}
+}
+
+func TestSetupProjectEnvironmentOverrides(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewJournalStore()
+	mockAWS := mock.NewAWSClient()
+	readOnly := "arn:aws:iam::aws:policy/ReadOnlyAccess"
+
+	config := testConfig()
+	config.Account.Overrides = map[account.Environment]account.EnvironmentSettings{
+		account.EnvironmentProd: {
+			OUID:              "ou-813y-prod0001",
+			NotificationEmail: "oncall@example.com",
+			Regions:           []string{"us-east-1", "eu-west-1"},
+			MonthlyLimit:      1000,
+			AlertThreshold:    800,
+			PolicyARNs:        []string{readOnly},
+		},
+	}
+
+	recorder := &policyRecordingAWS{AWSClient: mockAWS, policies: make(map[string][]string)}
+	j, _ := journal.Open(ctx, store, "TPA")
+	result, err := SetupProject(ctx, recorder, config, WithJournal(j))
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	dev, prod := result.Environments[0], result.Environments[2]
+	if !slices.Equal(prod.CDKRegions, []string{"us-east-1", "eu-west-1"}) {
+		t.Errorf("prod CDKRegions = %v, want both regions", prod.CDKRegions)
+	}
+	if parent, _ := mockAWS.GetAccountParent(ctx, prod.Account.AccountID); parent != "ou-813y-prod0001" {
+		t.Errorf("prod parent = %s, want override OU", parent)
+	}
+	if got := recorder.policies[prod.Account.AccountID]; !slices.Equal(got, []string{readOnly}) {
+		t.Errorf("prod role policies = %v, want [%s]", got, readOnly)
+	}
+	if got := recorder.policies[dev.Account.AccountID]; !slices.Equal(got, []string{AdministratorAccess}) {
+		t.Errorf("dev role policies = %v, want project default", got)
+	}
+	for _, region := range prod.CDKRegions {
+		if _, ok := j.Lookup("prod", journal.CDKBootstrapStep(region)); !ok {
+			t.Errorf("CDK bootstrap in %s should be journaled", region)
+		}
+	}
+
+	ops := strings.Join(mockAWS.GetOperations(), "\n")
+	if !strings.Contains(ops, "SubscribeEmailToSNSTopic(arn:aws:sns:us-east-1:"+prod.Account.AccountID+":TPA-prod-billing-alerts, oncall@example.com)") {
+		t.Errorf("prod alerts should go to the override address:\n%s", ops)
+	}
+	if !strings.Contains(ops, "SubscribeEmailToSNSTopic(arn:aws:sns:us-east-1:"+dev.Account.AccountID+":TPA-dev-billing-alerts, user@gmail.com)") {
+		t.Errorf("dev alerts should go to the project address:\n%s", ops)
+	}
+
+	prodBudget, _ := mockAWS.GetBudget(ctx, prod.Account.AccountID, prod.BudgetName)
+	if prodBudget == nil || prodBudget.LimitAmount != 1000 || prodBudget.AlertAmount != 800 {
+		t.Errorf("prod budget = %+v, want $1000 limit and $800 alert", prodBudget)
+	}
+
+	plan, err := BuildPlan(ctx, mockAWS, config.WithDefaults())
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("Plan after setup should have no changes:\n%s", plan.Summary())
+	}
+}
+
+func TestSetupProjectNamingConvention(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	config := testConfig()
+	config.Account.Naming = account.NamingConvention{
+		Preset: account.NamingPresetHyphenated,
+		Role:   "{project}-{env}-deploy",
+	}
+
+	result, err := SetupProject(ctx, mockAWS, config)
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	dev := result.Environments[0]
+	if dev.Account.Name != "TPA-dev" {
+		t.Errorf("Account name = %s, want TPA-dev", dev.Account.Name)
+	}
+	if want := "arn:aws:iam::" + dev.Account.AccountID + ":role/TPA-dev-deploy"; dev.RoleARN != want {
+		t.Errorf("RoleARN = %s, want %s", dev.RoleARN, want)
+	}
+	if dev.BudgetName != "TPA-dev-monthly-budget" {
+		t.Errorf("BudgetName = %s, want TPA-dev-monthly-budget", dev.BudgetName)
+	}
+
+	plan, err := BuildPlan(ctx, mockAWS, config.WithDefaults())
+	if err != nil {
+		t.Fatalf("BuildPlan() failed: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("Plan after setup should have no changes:\n%s", plan.Summary())
+	}
+}
+
+func TestConfigValidateAll(t *testing.T) {
+	config := testConfig()
+	config.Account.OUID = "bad"
+	config.GitHubRepo = ""
+	config.Billing.AlertThreshold = 100
+	config = config.WithDefaults()
+
+	report := config.ValidateAll()
+
+	var fields []string
+	for _, e := range report.Errors {
+		fields = append(fields, e.Field)
+	}
+	want := []string{"ouID", "githubRepo", "billing.alertThreshold"}
+	if !slices.Equal(fields, want) {
+		t.Errorf("ValidateAll() fields = %v, want %v", fields, want)
+	}
+	if report.Errors[1].Code != account.CodeRequired {
+		t.Errorf("githubRepo code = %s, want %s", report.Errors[1].Code, account.CodeRequired)
+	}
+}
@@ -0,0 +1,202 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/domain/account"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+// Project state.
+//
+// The run journal answers "what did this run finish?"; project state
+// answers "what does this project have?". It is what the v1 scripts keep
+// in .aws-bootstrap/account-ids.json, extended with every ARN setup
+// produces (see ports.ProjectState).
+
+// WithStateStore records the setup result in store once setup succeeds.
+//
+// Existing state is loaded and updated, not replaced: environments this run
+// didn't touch, and keys written by other tools, are kept. Accounts it
+// already records (including adopted ones) are reused by ID, even without
+// the journal that recorded them. If store is also a ports.StateLocker,
+// the whole run holds its lock, so concurrent runs against the project
+// take turns.
+//
+// AdoptAccount records the adopted account in store as well.
+func WithStateStore(store ports.StateStore) Option {
+	return func(o *options) {
+		o.stateStore = store
+	}
+}
+
+// RecordSetup applies a setup result to state.
+//
+// This is DOMAIN LOGIC - each environment in result replaces its recorded
+// entry. A nil state starts a new one.
+//
+// Parameters:
+//   - state: Previously recorded state, or nil
+//   - config: Project configuration the result was produced with
+//   - result: What SetupProject produced
+//   - now: Timestamp for the updated entries
+//
+// Returns:
+//   - The updated state (state itself, unless it was nil)
+func RecordSetup(state *ports.ProjectState, config Config, result *SetupResult, now time.Time) *ports.ProjectState {
+	if state == nil {
+		state = &ports.ProjectState{Version: ports.StateVersion, CreatedAt: now}
+	}
+	if state.Environments == nil {
+		state.Environments = make(map[string]ports.EnvironmentState)
+	}
+
+	state.ProjectCode = config.Account.ProjectCode
+	state.OUID = config.Account.OUID
+	state.ManagementAccountID = result.ManagementAccountID
+	state.UpdatedAt = now
+
+	for _, env := range result.Environments {
+		state.Environments[string(env.Account.Environment)] = ports.EnvironmentState{
+			AccountID:       env.Account.AccountID,
+			AccountName:     env.Account.Name,
+			Email:           env.Account.Email,
+			OUID:            env.Account.OUID,
+			OIDCProviderARN: env.OIDCProviderARN,
+			DeployRoleARN:   env.RoleARN,
+			CDKRegions:      env.CDKRegions,
+			TopicARN:        env.TopicARN,
+			AlarmName:       env.AlarmName,
+			BudgetName:      env.BudgetName,
+			UpdatedAt:       now,
+		}
+	}
+	return state
+}
+
+// RecordAdoption applies an adopted account to state.
+//
+// This is DOMAIN LOGIC - the environment's entry is replaced by the adopted
+// account, with its actual name. Resources recorded for the environment
+// belonged to the previous account, so they are dropped; setup records
+// them again. A nil state starts a new one.
+//
+// Parameters:
+//   - state: Previously recorded state, or nil
+//   - config: Project configuration the account was adopted with
+//   - result: What AdoptAccount found
+//   - now: Timestamp for the updated entry
+//
+// Returns:
+//   - The updated state (state itself, unless it was nil)
+func RecordAdoption(state *ports.ProjectState, config Config, result *AdoptResult, now time.Time) *ports.ProjectState {
+	if state == nil {
+		state = &ports.ProjectState{Version: ports.StateVersion, CreatedAt: now}
+	}
+	if state.Environments == nil {
+		state.Environments = make(map[string]ports.EnvironmentState)
+	}
+
+	state.ProjectCode = config.Account.ProjectCode
+	if state.OUID == "" {
+		state.OUID = config.Account.OUID
+	}
+	state.UpdatedAt = now
+
+	env := string(result.Environment)
+	if recorded := state.Environments[env]; recorded.AccountID == result.AccountID {
+		// Re-adopting keeps what setup recorded since
+		recorded.AccountName = result.AccountName
+		recorded.Email = result.Email
+		recorded.UpdatedAt = now
+		state.Environments[env] = recorded
+		return state
+	}
+	state.Environments[env] = ports.EnvironmentState{
+		AccountID:   result.AccountID,
+		AccountName: result.AccountName,
+		Email:       result.Email,
+		UpdatedAt:   now,
+	}
+	return state
+}
+
+// RecordedStateFrom extracts the account IDs and names DetectDrift checks.
+func RecordedStateFrom(state *ports.ProjectState) *RecordedState {
+	recorded := &RecordedState{
+		ProjectCode:  state.ProjectCode,
+		OUID:         state.OUID,
+		AccountIDs:   make(map[account.Environment]string, len(state.Environments)),
+		AccountNames: make(map[account.Environment]string, len(state.Environments)),
+	}
+	for env, s := range state.Environments {
+		if s.AccountID == "" {
+			continue
+		}
+		recorded.AccountIDs[account.Environment(env)] = s.AccountID
+		if s.AccountName != "" {
+			recorded.AccountNames[account.Environment(env)] = s.AccountName
+		}
+	}
+	return recorded
+}
+
+// recordedAccounts returns the accounts state records, for account.WithRecordedAccounts.
+func recordedAccounts(state *ports.ProjectState) map[account.Environment]account.RecordedAccount {
+	if state == nil {
+		return nil
+	}
+	accounts := make(map[account.Environment]account.RecordedAccount, len(state.Environments))
+	for env, s := range state.Environments {
+		if s.AccountID != "" {
+			accounts[account.Environment(env)] = account.RecordedAccount{AccountID: s.AccountID, Name: s.AccountName}
+		}
+	}
+	return accounts
+}
+
+// loadState reads the project's recorded state, if a state store is set.
+// Call it while holding the state lock.
+func (s *setup) loadState(ctx context.Context) error {
+	if s.stateStore == nil {
+		return nil
+	}
+
+	projectCode := s.config.Account.ProjectCode
+	state, err := s.stateStore.LoadState(ctx, projectCode)
+	if err != nil {
+		return fmt.Errorf("failed to load state for project %s: %w", projectCode, err)
+	}
+	s.state = state
+	return nil
+}
+
+// saveState records a successful run in the state store, if one is set.
+func (s *setup) saveState(ctx context.Context, result *SetupResult) error {
+	if s.stateStore == nil {
+		return nil
+	}
+
+	projectCode := s.config.Account.ProjectCode
+	state := RecordSetup(s.state, s.config, result, time.Now().UTC())
+	if err := s.stateStore.SaveState(ctx, state); err != nil {
+		return fmt.Errorf("failed to save state for project %s: %w", projectCode, err)
+	}
+	return nil
+}
+
+// lockState takes store's lock for the project, if it has one.
+func lockState(ctx context.Context, store ports.StateStore, projectCode string) (func(context.Context) error, error) {
+	locker, ok := store.(ports.StateLocker)
+	if !ok {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	unlock, err := locker.LockState(ctx, projectCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock state for project %s: %w", projectCode, err)
+	}
+	return unlock, nil
+}
@@ -0,0 +1,79 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/mock"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/adapters/remote"
+	"github.com/damonallison/aws-multi-account-bootstrap/v2/internal/ports"
+)
+
+func TestSetupProjectRecordsState(t *testing.T) {
+	ctx := context.Background()
+	mockAWS := mock.NewAWSClient()
+	store := mock.NewStateStore()
+
+	// Left by a v1 run that only got as far as dev, plus a key we don't own
+	var v1 ports.ProjectState
+	json.Unmarshal([]byte(`{"projectCode": "TPA", "devAccountId": "", "ouId": "ou-813y-8teevv2l", "githubRepo": "tpa-app"}`), &v1)
+	store.SaveState(ctx, &v1)
+
+	result, err := SetupProject(ctx, mockAWS, testConfig(), WithStateStore(store))
+	if err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+
+	state, err := store.LoadState(ctx, "TPA")
+	if err != nil || state == nil {
+		t.Fatalf("LoadState() = %v, %v", state, err)
+	}
+	if state.ManagementAccountID != result.ManagementAccountID || string(state.Extra["githubRepo"]) != `"tpa-app"` {
+		t.Errorf("State = %+v", state)
+	}
+	for _, env := range result.Environments {
+		recorded := state.Environments[string(env.Account.Environment)]
+		if recorded.AccountID != env.Account.AccountID || recorded.DeployRoleARN != env.RoleARN || recorded.BudgetName != env.BudgetName {
+			t.Errorf("%s = %+v, want %+v", env.Account.Environment, recorded, env)
+		}
+	}
+
+	// The recorded state is what drift detection checks
+	report, err := DetectDrift(ctx, mockAWS, testConfig(), RecordedStateFrom(state))
+	if err != nil {
+		t.Fatalf("DetectDrift() failed: %v", err)
+	}
+	if report.HasDrift() {
+		t.Errorf("Recorded state should have no drift:\n%s", report.Summary())
+	}
+}
+
+func TestSetupProjectHoldsStateLock(t *testing.T) {
+	ctx := context.Background()
+	bucket := mock.NewObjectStore()
+
+	if _, err := SetupProject(ctx, mock.NewAWSClient(), testConfig(), WithStateStore(remote.New(bucket))); err != nil {
+		t.Fatalf("SetupProject() failed: %v", err)
+	}
+	if keys := bucket.Keys(); len(keys) != 1 || keys[0] != "aws-bootstrap/TPA/account-ids.json" {
+		t.Errorf("Bucket keys = %v, want only the state (lock released)", keys)
+	}
+
+	// Another run in progress blocks this one
+	if _, err := remote.New(bucket).LockState(ctx, "TPA"); err != nil {
+		t.Fatal(err)
+	}
+	mockAWS := mock.NewAWSClient()
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err := SetupProject(waitCtx, mockAWS, testConfig(), WithStateStore(remote.New(bucket, remote.WithPollInterval(time.Millisecond))))
+	if !errors.Is(err, ports.ErrStateLocked) {
+		t.Fatalf("SetupProject() = %v, want ErrStateLocked", err)
+	}
+	if accounts, _ := mockAWS.ListAccounts(ctx); len(accounts) != 0 {
+		t.Errorf("Blocked run created %d accounts", len(accounts))
+	}
+}
@@ -46,6 +46,61 @@ type AWSClient interface {
 	//   - Error if the operation fails (NOT if account doesn't exist)
 	GetAccountByName(ctx context.Context, name string) (string, error)
 
+	// ListAccounts returns every account in the organization, including
+	// suspended ones.
+	//
+	// AWS-specific: Uses AWS Organizations ListAccounts (all pages).
+	// Account names are NOT unique in AWS Organizations; several accounts
+	// may share one.
+	ListAccounts(ctx context.Context) ([]AWSAccount, error)
+
+	// DescribeAccount returns one account, including its status.
+	//
+	// AWS-specific: Uses AWS Organizations DescribeAccount.
+	//
+	// Returns:
+	//   - The account, or nil if it doesn't exist
+	//   - Error if the operation fails (NOT if account doesn't exist)
+	DescribeAccount(ctx context.Context, accountID string) (*AWSAccount, error)
+
+	// GetAccountParent returns the ID of the root or OU that directly
+	// contains the account.
+	//
+	// AWS-specific: Uses AWS Organizations ListParents.
+	//
+	// Returns:
+	//   - Parent ID (e.g., "r-ab12" for the root, "ou-ab12-xxxxxxxx" for an OU)
+	//   - Error if the account doesn't exist or the operation fails
+	GetAccountParent(ctx context.Context, accountID string) (string, error)
+
+	// TagAccount adds or replaces tags on an account. Existing tags with
+	// other keys are kept.
+	//
+	// AWS-specific: Uses AWS Organizations TagResource.
+	TagAccount(ctx context.Context, accountID string, tags map[string]string) error
+
+	// GetAccountTags returns the account's tags (empty if it has none).
+	//
+	// AWS-specific: Uses AWS Organizations ListTagsForResource.
+	GetAccountTags(ctx context.Context, accountID string) (map[string]string, error)
+
+	// MoveAccount moves an account from one parent (root or OU) to another.
+	//
+	// AWS-specific: Uses AWS Organizations MoveAccount. The source parent
+	// must be the account's current parent.
+	MoveAccount(ctx context.Context, accountID, sourceParentID, destinationParentID string) error
+
+	// CloseAccount closes a member account.
+	//
+	// AWS-specific: Uses AWS Organizations CloseAccount. The account moves
+	// to PENDING_CLOSURE and then SUSPENDED; it can be reopened through
+	// AWS Support for 90 days, and its root email stays reserved.
+	// Organizations only allows closing 10% of member accounts (at least
+	// 10, at most 1000) per rolling 30 days; beyond that it returns
+	// ErrCloseAccountQuotaExceeded. Closing an already closed account
+	// returns ErrAccountAlreadyClosed.
+	CloseAccount(ctx context.Context, accountID string) error
+
 	// AWS IAM - OIDC for GitHub Actions
 
 	// CreateOIDCProviderForGitHub creates an OIDC identity provider for GitHub Actions.
@@ -54,6 +109,16 @@ type AWSClient interface {
 	// AWS-specific: Uses AWS IAM OIDC provider.
 	CreateOIDCProviderForGitHub(ctx context.Context, accountID string) error
 
+	// GetOIDCProviderForGitHub looks up the GitHub Actions OIDC provider in an account.
+	//
+	// Returns:
+	//   - OIDC provider ARN if found (empty string if not found)
+	//   - Error if the operation fails (NOT if the provider doesn't exist)
+	GetOIDCProviderForGitHub(ctx context.Context, accountID string) (string, error)
+
+	// DeleteOIDCProviderForGitHub removes the GitHub Actions OIDC provider from an account.
+	DeleteOIDCProviderForGitHub(ctx context.Context, accountID string) error
+
 	// CreateGitHubActionsRole creates an IAM role that GitHub Actions can assume via OIDC.
 	//
 	// AWS-specific: Creates AWS IAM role with trust policy for GitHub OIDC.
@@ -63,6 +128,16 @@ type AWSClient interface {
 	//   - Error if creation fails
 	CreateGitHubActionsRole(ctx context.Context, req AWSCreateRoleRequest) (string, error)
 
+	// GetRole looks up an IAM role by name.
+	//
+	// Returns:
+	//   - IAM Role ARN if found (empty string if not found)
+	//   - Error if the operation fails (NOT if the role doesn't exist)
+	GetRole(ctx context.Context, accountID, roleName string) (string, error)
+
+	// DeleteRole detaches the role's policies and deletes it.
+	DeleteRole(ctx context.Context, accountID, roleName string) error
+
 	// AWS CDK - Infrastructure as Code
 
 	// BootstrapCDK runs AWS CDK bootstrap in an account.
@@ -70,6 +145,11 @@ type AWSClient interface {
 	// AWS-specific: Creates S3 buckets, ECR repos, and IAM roles for AWS CDK.
 	BootstrapCDK(ctx context.Context, accountID, region, trustAccountID string) error
 
+	// IsCDKBootstrapped reports whether CDK has been bootstrapped in an account and region.
+	//
+	// AWS-specific: Checks for the CDKToolkit CloudFormation stack.
+	IsCDKBootstrapped(ctx context.Context, accountID, region string) (bool, error)
+
 	// AWS Budgets & Cost Management
 
 	// CreateBudget creates an AWS Budget with email notifications.
@@ -77,11 +157,37 @@ type AWSClient interface {
 	// AWS-specific: Uses AWS Budgets API.
 	CreateBudget(ctx context.Context, req AWSCreateBudgetRequest) error
 
+	// GetBudget looks up an AWS Budget by name.
+	//
+	// Returns:
+	//   - Budget details if found (nil if not found)
+	//   - Error if the operation fails (NOT if the budget doesn't exist)
+	GetBudget(ctx context.Context, accountID, budgetName string) (*AWSBudget, error)
+
+	// UpdateBudget changes the limit and notifications of an existing AWS Budget.
+	UpdateBudget(ctx context.Context, req AWSCreateBudgetRequest) error
+
+	// DeleteBudget deletes an AWS Budget and its notifications.
+	DeleteBudget(ctx context.Context, accountID, budgetName string) error
+
 	// CreateBillingAlarm creates a CloudWatch billing alarm.
 	//
 	// AWS-specific: Uses AWS CloudWatch Alarms + SNS for billing alerts.
+	//
+	// CloudWatch PutMetricAlarm is an upsert, so calling this again with a
+	// different threshold updates the existing alarm.
 	CreateBillingAlarm(ctx context.Context, req AWSCreateBillingAlarmRequest) error
 
+	// GetBillingAlarm looks up a CloudWatch billing alarm by name.
+	//
+	// Returns:
+	//   - Alarm details if found (nil if not found)
+	//   - Error if the operation fails (NOT if the alarm doesn't exist)
+	GetBillingAlarm(ctx context.Context, accountID, alarmName string) (*AWSBillingAlarm, error)
+
+	// DeleteBillingAlarm deletes a CloudWatch billing alarm.
+	DeleteBillingAlarm(ctx context.Context, accountID, alarmName string) error
+
 	// AWS SNS - Notifications
 
 	// CreateSNSTopic creates an AWS SNS topic for notifications.
@@ -94,6 +200,10 @@ type AWSClient interface {
 	// SubscribeEmailToSNSTopic subscribes an email address to an SNS topic.
 	SubscribeEmailToSNSTopic(ctx context.Context, topicARN, email string) error
 
+	// DeleteSNSTopic deletes an SNS topic and all of its subscriptions.
+	// Like SNS DeleteTopic, deleting a topic that doesn't exist succeeds.
+	DeleteSNSTopic(ctx context.Context, topicARN string) error
+
 	// AWS STS - Cross-Account Access
 
 	// AssumeRole assumes an IAM role in another AWS account.
@@ -112,12 +222,27 @@ type AWSClient interface {
 
 // AWSCreateAccountRequest contains parameters for creating an AWS account.
 type AWSCreateAccountRequest struct {
-	Name      string // AWS account name (e.g., "TPA-dev")
+	Name      string // AWS account name (e.g., "TPA_DEV"; see account.NamingConvention)
 	Email     string // Root email for the account
-	OrgUnitID string // AWS Organization Unit ID (e.g., "ou-813y-xxxxxxxx")
+	OrgUnitID string // Target OU (e.g., "ou-813y-xxxxxxxx"). AWS creates accounts in the root; callers move them (see MoveAccount)
 	RoleName  string // Cross-account access role name (usually "OrganizationAccountAccessRole")
 }
 
+// Account statuses reported by AWS Organizations.
+const (
+	AccountStatusActive         = "ACTIVE"
+	AccountStatusSuspended      = "SUSPENDED"       // Closed; the root email stays reserved
+	AccountStatusPendingClosure = "PENDING_CLOSURE" // Closing
+)
+
+// AWSAccount describes an existing account in the organization.
+type AWSAccount struct {
+	ID     string // AWS Account ID (12-digit number)
+	Name   string // Account name (e.g., "TPA_DEV")
+	Email  string // Root email
+	Status string // AccountStatusActive, AccountStatusSuspended, ...
+}
+
 // AWSCreateRoleRequest contains parameters for creating an AWS IAM role for GitHub Actions.
 type AWSCreateRoleRequest struct {
 	AccountID        string   // AWS Account ID where role should be created
@@ -131,7 +256,7 @@ type AWSCreateRoleRequest struct {
 // AWSCreateBudgetRequest contains parameters for creating an AWS Budget.
 type AWSCreateBudgetRequest struct {
 	AccountID     string  // AWS Account ID
-	BudgetName    string  // Budget name (e.g., "TPA-dev-monthly")
+	BudgetName    string  // Budget name (e.g., "TPA-dev-monthly-budget")
 	LimitAmount   float64 // Monthly limit in USD (e.g., 25.00)
 	AlertAmount   float64 // Amount to trigger alert (e.g., 15.00)
 	Email         string  // Email for notifications
@@ -146,6 +271,24 @@ type AWSCreateBillingAlarmRequest struct {
 	TopicARN  string  // SNS topic ARN for notifications
 }
 
+// AWSBudget describes an existing AWS Budget.
+type AWSBudget struct {
+	AccountID     string
+	BudgetName    string
+	LimitAmount   float64
+	AlertAmount   float64
+	Email         string
+	AlertPercents []int
+}
+
+// AWSBillingAlarm describes an existing CloudWatch billing alarm.
+type AWSBillingAlarm struct {
+	AccountID string
+	AlarmName string
+	Threshold float64
+	TopicARN  string
+}
+
 // AWSCredentials represents temporary AWS credentials from STS AssumeRole.
 type AWSCredentials struct {
 	AccessKeyID     string
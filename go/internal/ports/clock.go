@@ -0,0 +1,25 @@
+package ports
+
+import "time"
+
+// Clock abstracts the passage of time.
+//
+// Adapters that wait (retry backoff, rate limiting, polling) take a Clock
+// so tests can run them instantly with a fake clock instead of sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel that receives the time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock returns a Clock backed by the time package.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
@@ -0,0 +1,114 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors adapters return for well-known AWS failures.
+//
+// Adapters wrap these in *AWSError (which keeps the raw AWS error code) so
+// the domain can branch with errors.Is instead of matching strings:
+//
+//	if errors.Is(err, ports.ErrEmailAlreadyExists) { ... }
+var (
+	// ErrEmailAlreadyExists means another AWS account already uses the root email.
+	ErrEmailAlreadyExists = errors.New("email address is already used by another AWS account")
+
+	// ErrAccountLimitExceeded means the organization reached its account quota.
+	ErrAccountLimitExceeded = errors.New("AWS Organizations account limit exceeded")
+
+	// ErrCloseAccountQuotaExceeded means the organization closed as many
+	// accounts as AWS allows in the rolling 30-day window. Not retryable
+	// until older closures age out.
+	ErrCloseAccountQuotaExceeded = errors.New("AWS Organizations close-account quota exceeded")
+
+	// ErrAccountAlreadyClosed means the account is already suspended or
+	// pending closure.
+	ErrAccountAlreadyClosed = errors.New("AWS account is already closed")
+
+	// ErrConcurrentModification means another request is modifying the
+	// organization. Safe to retry after a short delay.
+	ErrConcurrentModification = errors.New("AWS Organizations is being modified by another request")
+
+	// ErrThrottled means AWS rejected the request due to rate limiting.
+	// Safe to retry with backoff.
+	ErrThrottled = errors.New("request throttled by AWS")
+)
+
+// AWS error codes mapped to the sentinel errors above.
+//
+// Organizations reports creation failures as CreateAccountStatus
+// FailureReason values (EMAIL_ALREADY_EXISTS, ACCOUNT_LIMIT_EXCEEDED),
+// constraint violations by their Reason (CLOSE_ACCOUNT_QUOTA_EXCEEDED) and
+// other API failures as exception names.
+const (
+	CodeEmailAlreadyExists     = "EMAIL_ALREADY_EXISTS"
+	CodeAccountLimitExceeded   = "ACCOUNT_LIMIT_EXCEEDED"
+	CodeCloseQuotaExceeded     = "CLOSE_ACCOUNT_QUOTA_EXCEEDED"
+	CodeAccountAlreadyClosed   = "AccountAlreadyClosedException"
+	CodeConcurrentModification = "ConcurrentModificationException"
+	CodeTooManyRequests        = "TooManyRequestsException"
+	CodeThrottling             = "ThrottlingException"
+	CodeThrottlingShort        = "Throttling"
+	CodeRequestLimitExceeded   = "RequestLimitExceeded"
+	CodeServiceException       = "ServiceException"
+)
+
+var codeKinds = map[string]error{
+	CodeEmailAlreadyExists:     ErrEmailAlreadyExists,
+	CodeAccountLimitExceeded:   ErrAccountLimitExceeded,
+	CodeCloseQuotaExceeded:     ErrCloseAccountQuotaExceeded,
+	CodeAccountAlreadyClosed:   ErrAccountAlreadyClosed,
+	CodeConcurrentModification: ErrConcurrentModification,
+	CodeTooManyRequests:        ErrThrottled,
+	CodeThrottling:             ErrThrottled,
+	CodeThrottlingShort:        ErrThrottled,
+	CodeRequestLimitExceeded:   ErrThrottled,
+}
+
+// AWSError is an error reported by AWS, carrying its error code.
+type AWSError struct {
+	Code    string // AWS error code (e.g., "ConcurrentModificationException")
+	Message string // Message from AWS
+	Kind    error  // Matching sentinel error (nil if the code isn't recognized)
+}
+
+// NewAWSError creates an AWSError, classifying well-known codes.
+func NewAWSError(code, message string) *AWSError {
+	return &AWSError{
+		Code:    code,
+		Message: message,
+		Kind:    codeKinds[code],
+	}
+}
+
+func (e *AWSError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap makes errors.Is(err, ErrThrottled) etc. work.
+func (e *AWSError) Unwrap() error {
+	return e.Kind
+}
+
+// ErrorCode returns the AWS error code in err's chain, or "" if there is none.
+func ErrorCode(err error) string {
+	var awsErr *AWSError
+	if errors.As(err, &awsErr) {
+		return awsErr.Code
+	}
+	return ""
+}
+
+// IsRetryable reports whether err is a transient AWS failure that is safe
+// to retry (throttling, concurrent modification, AWS-side service errors).
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrThrottled) || errors.Is(err, ErrConcurrentModification) {
+		return true
+	}
+	return ErrorCode(err) == CodeServiceException
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// JournalStore persists run journals so an interrupted setup can resume.
+//
+// The domain decides WHAT goes into a journal (which steps completed, for
+// which environment); the store only decides WHERE it lives (a local file,
+// memory in tests, or remote storage later on).
+type JournalStore interface {
+	// LoadJournal returns the journal for a run.
+	//
+	// Returns:
+	//   - The stored journal, or nil if no journal exists for runID
+	//   - Error if the journal exists but cannot be read
+	LoadJournal(ctx context.Context, runID string) (*RunJournal, error)
+
+	// SaveJournal persists the full journal, replacing any previous version.
+	SaveJournal(ctx context.Context, journal *RunJournal) error
+}
+
+// RunJournal is the persisted record of one setup run.
+type RunJournal struct {
+	RunID     string         `json:"runId"`
+	Entries   []JournalEntry `json:"entries"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// JournalEntry records a single completed step for one environment.
+type JournalEntry struct {
+	Environment string    `json:"environment"`          // e.g., "dev"
+	Step        string    `json:"step"`                 // e.g., "account-created"
+	AccountID   string    `json:"accountId,omitempty"`  // AWS Account ID the step applied to
+	ResourceID  string    `json:"resourceId,omitempty"` // ARN or name produced by the step, if any
+	CompletedAt time.Time `json:"completedAt"`
+}
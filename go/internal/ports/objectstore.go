@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ObjectStore is the subset of S3 the remote state backend needs.
+//
+// Like AWSClient it is deliberately S3-shaped: ETags and conditional
+// writes (If-Match / If-None-Match) are what make optimistic locking work,
+// and any S3-compatible service (AWS S3, MinIO, R2, ...) offers them.
+type ObjectStore interface {
+	// GetObject reads an object.
+	//
+	// Returns:
+	//   - The object, or nil if the key doesn't exist
+	//   - Error if the operation fails (NOT if the object doesn't exist)
+	GetObject(ctx context.Context, key string) (*Object, error)
+
+	// PutObject writes an object if the precondition holds.
+	//
+	// AWS-specific: Uses S3 PutObject with If-Match / If-None-Match.
+	//
+	// Returns:
+	//   - The new object's ETag
+	//   - ErrPreconditionFailed if the condition doesn't hold
+	PutObject(ctx context.Context, key string, data []byte, cond WriteCondition) (string, error)
+
+	// DeleteObject deletes an object if the precondition holds. Deleting a
+	// missing object succeeds unless IfMatch is set.
+	DeleteObject(ctx context.Context, key string, cond WriteCondition) error
+}
+
+// Object is an object read from an ObjectStore.
+type Object struct {
+	Data []byte
+	ETag string // Changes on every write
+}
+
+// WriteCondition guards a write. The zero value writes unconditionally.
+type WriteCondition struct {
+	IfMatch     string // Only if the object's current ETag is this one
+	IfNoneMatch bool   // Only if the object doesn't exist (If-None-Match: *)
+}
+
+// ErrPreconditionFailed means a conditional write lost a race: the object
+// changed (or appeared) since it was read.
+var ErrPreconditionFailed = errors.New("precondition failed: object was modified")
@@ -0,0 +1,36 @@
+package ports
+
+import "time"
+
+// ProgressReporter receives progress events from domain orchestration.
+//
+// Account creation takes minutes; events let a CLI spinner or a web UI
+// show what is happening meanwhile. Report is called from whichever
+// goroutine provisions an environment, so implementations must be safe for
+// concurrent use, and must not block for long: orchestration waits for it.
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
+
+// ProgressEventType classifies a progress event.
+type ProgressEventType string
+
+const (
+	EventStepStarted   ProgressEventType = "step-started"
+	EventPolling       ProgressEventType = "polling" // Still waiting on AWS (e.g., account creation)
+	EventStepSucceeded ProgressEventType = "step-succeeded"
+	EventStepFailed    ProgressEventType = "step-failed"
+	EventReused        ProgressEventType = "reused" // Nothing to do: it already existed or a previous run did it
+)
+
+// ProgressEvent describes one moment of an orchestration step.
+type ProgressEvent struct {
+	Type        ProgressEventType
+	Time        time.Time
+	Environment string        // e.g., "dev"
+	Step        string        // e.g., "create", "wait", "deploy-role"
+	AccountName string        // e.g., "TPA_DEV"
+	AccountID   string        // Empty until the account exists
+	Duration    time.Duration // Time since the step started (polling, succeeded, failed)
+	Err         error         // Set for EventStepFailed
+}
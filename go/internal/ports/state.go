@@ -0,0 +1,240 @@
+package ports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StateStore persists what setup produced for a project: which account
+// serves each environment, and the ARNs created inside it.
+//
+// Like JournalStore, the domain decides WHAT is recorded; the store only
+// decides WHERE it lives. The local adapter keeps it in
+// .aws-bootstrap/account-ids.json, where the bash scripts read it.
+type StateStore interface {
+	// LoadState returns the recorded state of a project.
+	//
+	// Returns:
+	//   - The stored state, or nil if nothing is recorded for projectCode
+	//   - Error if the state exists but cannot be read
+	LoadState(ctx context.Context, projectCode string) (*ProjectState, error)
+
+	// SaveState persists the full state, replacing any previous version.
+	//
+	// Stores shared between machines reject the write with
+	// ErrStateConflict if the state changed since it was loaded (see
+	// ProjectState.Revision). On success they update state.Revision.
+	SaveState(ctx context.Context, state *ProjectState) error
+}
+
+// StateLocker is implemented by state stores that several machines share
+// (e.g., a laptop and CI), so concurrent runs against one project take
+// turns instead of overwriting each other's state.
+type StateLocker interface {
+	// LockState waits until the caller holds the project's lock.
+	//
+	// Returns:
+	//   - unlock, which releases the lock
+	//   - ErrStateLocked if ctx ends while another run holds the lock
+	LockState(ctx context.Context, projectCode string) (unlock func(context.Context) error, err error)
+}
+
+var (
+	// ErrStateConflict means the state was saved by someone else since it
+	// was loaded. Load it again and re-apply the change.
+	ErrStateConflict = errors.New("project state was modified concurrently")
+
+	// ErrStateLocked means another run holds the project's state lock.
+	ErrStateLocked = errors.New("project state is locked by another run")
+)
+
+// StateVersion is the schema version SaveState writes.
+//
+// Version 1 is the flat file written by create-project-accounts.sh:
+//
+//	{"projectCode": "TPA", "devAccountId": "...", "ouId": "ou-..."}
+//
+// Version 2 keeps those keys, so bash scripts can still read it with jq,
+// and adds "version", an "environments" section with every ARN, and
+// timestamps.
+const StateVersion = 2
+
+// ProjectState is the recorded state of one project.
+//
+// It encodes to and decodes from both schema versions (see StateVersion).
+// Decoding a version 1 file yields Version 1; encoding always writes
+// StateVersion, so loading and saving upgrades the file. Keys this
+// package doesn't know are kept in Extra and written back unchanged.
+type ProjectState struct {
+	Version             int
+	ProjectCode         string
+	OUID                string // Project OU (v1 "ouId")
+	ManagementAccountID string // Account CDK bootstrap trusts
+	Environments        map[string]EnvironmentState
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+
+	Extra map[string]json.RawMessage // Unknown top-level keys
+
+	// Revision identifies the stored version this state was loaded from
+	// (e.g., an S3 ETag), for stores that detect concurrent writes. Empty
+	// for new state. Not part of the JSON.
+	Revision string
+}
+
+// EnvironmentState records the account and resources of one environment.
+type EnvironmentState struct {
+	AccountID       string    `json:"accountId"`
+	AccountName     string    `json:"accountName,omitempty"`
+	Email           string    `json:"email,omitempty"`
+	OUID            string    `json:"ouId,omitempty"` // OU the account was placed in
+	OIDCProviderARN string    `json:"oidcProviderArn,omitempty"`
+	DeployRoleARN   string    `json:"deployRoleArn,omitempty"`
+	CDKRegions      []string  `json:"cdkRegions,omitempty"`
+	TopicARN        string    `json:"topicArn,omitempty"`
+	AlarmName       string    `json:"alarmName,omitempty"`
+	BudgetName      string    `json:"budgetName,omitempty"`
+	UpdatedAt       time.Time `json:"updatedAt,omitzero"`
+}
+
+// accountIDSuffix turns an environment name into its v1 key ("devAccountId").
+const accountIDSuffix = "AccountId"
+
+// Top-level keys owned by ProjectState.
+const (
+	stateKeyVersion      = "version"
+	stateKeyProjectCode  = "projectCode"
+	stateKeyOUID         = "ouId"
+	stateKeyManagement   = "managementAccountId"
+	stateKeyEnvironments = "environments"
+	stateKeyCreatedAt    = "createdAt"
+	stateKeyUpdatedAt    = "updatedAt"
+)
+
+// MarshalJSON writes the current schema. Keys keep the order of the v1
+// file (project code, account IDs, OU) so diffs against it stay small.
+func (s ProjectState) MarshalJSON() ([]byte, error) {
+	type field struct {
+		key   string
+		value any
+	}
+	fields := []field{
+		{stateKeyVersion, StateVersion},
+		{stateKeyProjectCode, s.ProjectCode},
+	}
+	envs := slices.Sorted(maps.Keys(s.Environments))
+	for _, env := range envs {
+		fields = append(fields, field{env + accountIDSuffix, s.Environments[env].AccountID})
+	}
+	fields = append(fields, field{stateKeyOUID, s.OUID})
+	if s.ManagementAccountID != "" {
+		fields = append(fields, field{stateKeyManagement, s.ManagementAccountID})
+	}
+	environments := s.Environments
+	if environments == nil {
+		environments = map[string]EnvironmentState{}
+	}
+	fields = append(fields, field{stateKeyEnvironments, environments})
+	if !s.CreatedAt.IsZero() {
+		fields = append(fields, field{stateKeyCreatedAt, s.CreatedAt})
+	}
+	if !s.UpdatedAt.IsZero() {
+		fields = append(fields, field{stateKeyUpdatedAt, s.UpdatedAt})
+	}
+	for _, key := range slices.Sorted(maps.Keys(s.Extra)) {
+		fields = append(fields, field{key, s.Extra[key]})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", f.key, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads either schema version.
+//
+// The flat "<env>AccountId" keys are authoritative: a bash script may have
+// rewritten them since Go last saved. If one names a different account
+// than the environments section, the section's ARNs belonged to the old
+// account and are dropped. Empty IDs (a failed v1 run) mean "not recorded":
+// the environment is kept with an empty AccountID, so the key is written
+// back as "" and `jq -r` in the scripts doesn't start printing "null".
+func (s *ProjectState) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	state := ProjectState{Version: 1, Environments: make(map[string]EnvironmentState)}
+	decode := func(key string, v any) error {
+		value, ok := raw[key]
+		if !ok {
+			return nil
+		}
+		delete(raw, key)
+		if err := json.Unmarshal(value, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return nil
+	}
+	for key, v := range map[string]any{
+		stateKeyVersion:      &state.Version,
+		stateKeyProjectCode:  &state.ProjectCode,
+		stateKeyOUID:         &state.OUID,
+		stateKeyManagement:   &state.ManagementAccountID,
+		stateKeyEnvironments: &state.Environments,
+		stateKeyCreatedAt:    &state.CreatedAt,
+		stateKeyUpdatedAt:    &state.UpdatedAt,
+	} {
+		if err := decode(key, v); err != nil {
+			return err
+		}
+	}
+	if state.Version > StateVersion {
+		return fmt.Errorf("state version %d is newer than supported version %d", state.Version, StateVersion)
+	}
+	if state.Environments == nil {
+		state.Environments = make(map[string]EnvironmentState)
+	}
+
+	for key := range raw {
+		env, ok := strings.CutSuffix(key, accountIDSuffix)
+		if !ok || env == "" {
+			continue
+		}
+		var accountID string
+		if err := decode(key, &accountID); err != nil {
+			return err
+		}
+		current, exists := state.Environments[env]
+		if !exists || current.AccountID != accountID {
+			state.Environments[env] = EnvironmentState{AccountID: accountID}
+		}
+	}
+
+	if len(raw) > 0 {
+		state.Extra = raw
+	}
+	*s = state
+	return nil
+}